@@ -0,0 +1,56 @@
+package api
+
+import (
+	"context"
+
+	"github.com/filecoin-project/go-address"
+	types "github.com/filecoin-project/venus/venus-shared/types/messager"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+)
+
+// audit records a mutating call, prev is the value before the call, nil if it makes no sense
+func (m MessageImp) audit(ctx context.Context, method string, params interface{}, prev interface{}, result interface{}, err error) {
+	if m.AuditSrv == nil {
+		return
+	}
+	m.AuditSrv.Record(ctx, method, params, prev, result, err)
+}
+
+func (m MessageImp) prevMessageState(ctx context.Context, id string) interface{} {
+	state, err := m.MessageSrv.GetMessageState(ctx, id)
+	if err != nil {
+		return nil
+	}
+	return state.String()
+}
+
+func (m MessageImp) prevAddress(ctx context.Context, addr address.Address) interface{} {
+	addrInfo, err := m.AddressSrv.GetAddress(ctx, addr)
+	if err != nil {
+		return nil
+	}
+	return addrInfo
+}
+
+func (m MessageImp) prevNode(ctx context.Context, name string) interface{} {
+	node, err := m.NodeSrv.GetNode(ctx, name)
+	if err != nil {
+		return nil
+	}
+	return hideNodeToken(node)
+}
+
+// hideNodeToken avoid to save the token of node to audit log
+func hideNodeToken(node *types.Node) *types.Node {
+	if node == nil {
+		return nil
+	}
+	cpy := *node
+	cpy.Token = "******"
+	return &cpy
+}
+
+func (m MessageImp) ListAuditLog(ctx context.Context, query *mtypes.AuditQuery) ([]*mtypes.AuditLog, error) {
+	return m.AuditSrv.ListAuditLog(ctx, query)
+}
@@ -0,0 +1,60 @@
+package extend
+
+import (
+	"context"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/filecoin-project/venus/venus-shared/api/messager"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+)
+
+// IMessagerExt is the api of messager, it extends messager.IMessager with the functions which only exist in this repo
+type IMessagerExt interface {
+	messager.IMessager
+
+	ApproveMessage(ctx context.Context, id string, comment string) error                                                          //perm:admin
+	RejectMessage(ctx context.Context, id string, comment string) error                                                           //perm:admin
+	ListMessageApproval(ctx context.Context, from address.Address, state mtypes.ApprovalState) ([]*mtypes.MessageApproval, error) //perm:admin
+
+	ListTopUpRecord(ctx context.Context, addr address.Address) ([]*mtypes.TopUpRecord, error) //perm:admin
+	DiscoverAddress(ctx context.Context, register bool) ([]*mtypes.DiscoveredAddress, error)  //perm:admin
+	ListPausedAddress(ctx context.Context) ([]*mtypes.PausedAddress, error)                   //perm:read
+	ResumeAddress(ctx context.Context, addr address.Address) error                            //perm:admin
+	InvalidateSignerCache(ctx context.Context, addrs []address.Address) error                 //perm:admin
+
+	ListAuditLog(ctx context.Context, query *mtypes.AuditQuery) ([]*mtypes.AuditLog, error) //perm:admin
+
+	AuthCreateUser(ctx context.Context, name string, comment string) error              //perm:admin
+	AuthDeleteUser(ctx context.Context, name string) error                              //perm:admin
+	AuthListUsers(ctx context.Context) ([]*mtypes.AuthUser, error)                      //perm:admin
+	AuthCreateToken(ctx context.Context, name string, perm string) (string, error)      //perm:admin
+	AuthListTokens(ctx context.Context, name string) ([]*mtypes.AuthToken, error)       //perm:admin
+	AuthRemoveToken(ctx context.Context, token string) error                            //perm:admin
+	AuthBindSigner(ctx context.Context, name string, signers []address.Address) error   //perm:admin
+	AuthUnbindSigner(ctx context.Context, name string, signers []address.Address) error //perm:admin
+
+	ListMessageHistory(ctx context.Context, id string) ([]*mtypes.MessageHistory, error)      //perm:read
+	ListMessageVersion(ctx context.Context, id string) ([]*mtypes.MessageVersion, error)      //perm:read
+	ListPublishResult(ctx context.Context, id string) ([]*mtypes.PublishResult, error)        //perm:read
+	GetMessagePropagation(ctx context.Context, id string) (*mtypes.MessagePropagation, error) //perm:read
+
+	GetLeaderInfo(ctx context.Context) (*mtypes.LeaderInfo, error)                //perm:read
+	GetNodePoolInfo(ctx context.Context) (*mtypes.NodePoolInfo, error)            //perm:read
+	GetNodeSyncStatus(ctx context.Context) (*mtypes.NodeSyncStatus, error)        //perm:read
+	GetShardingInfo(ctx context.Context) (*mtypes.ShardingInfo, error)            //perm:read
+	GetPublishRoute(ctx context.Context, id string) (*mtypes.PublishRoute, error) //perm:read
+
+	ListNodeHealth(ctx context.Context) ([]*mtypes.PublishNodeHealth, error) //perm:read
+
+	NetListenInfo(ctx context.Context) (*mtypes.NetListenInfo, error)     //perm:read
+	NetPeersInfo(ctx context.Context) ([]*mtypes.PeerInfo, error)         //perm:read
+	NetDisconnect(ctx context.Context, peerID peer.ID) error              //perm:admin
+	NetBanPeer(ctx context.Context, peerID peer.ID) error                 //perm:admin
+	NetUnbanPeer(ctx context.Context, peerID peer.ID) error               //perm:admin
+	NetListBannedPeers(ctx context.Context) ([]*mtypes.BannedPeer, error) //perm:read
+
+	ListGatewayStatus(ctx context.Context) ([]*mtypes.GatewayStatus, error) //perm:read
+}
@@ -0,0 +1,32 @@
+package extend
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/filecoin-project/go-jsonrpc"
+
+	"github.com/filecoin-project/venus/venus-shared/api"
+	"github.com/filecoin-project/venus/venus-shared/api/messager"
+)
+
+// DialIMessagerExtRPC is the same as messager.DialIMessagerRPC, but also binds the extended functions
+func DialIMessagerExtRPC(ctx context.Context, addr string, token string, requestHeader http.Header, opts ...jsonrpc.Option) (IMessagerExt, jsonrpc.ClientCloser, error) {
+	ainfo := api.NewAPIInfo(addr, token)
+	endpoint, err := ainfo.DialArgs(api.VerString(messager.MajorVersion))
+	if err != nil {
+		return nil, nil, fmt.Errorf("get dial args: %w", err)
+	}
+
+	if requestHeader == nil {
+		requestHeader = http.Header{}
+	}
+	requestHeader.Set(api.VenusAPINamespaceHeader, messager.APINamespace)
+	ainfo.SetAuthHeader(requestHeader)
+
+	var res IMessagerExtStruct
+	closer, err := jsonrpc.NewMergeClient(ctx, endpoint, messager.MethodNamespace, api.GetInternalStructs(&res), requestHeader, opts...)
+
+	return &res, closer, err
+}
@@ -0,0 +1,156 @@
+package extend
+
+import (
+	"context"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/filecoin-project/venus/venus-shared/api/messager"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+)
+
+type IMessagerExtStruct struct {
+	messager.IMessagerStruct
+
+	Internal struct {
+		ApproveMessage        func(ctx context.Context, id string, comment string) error                                                     `perm:"admin"`
+		AuthBindSigner        func(ctx context.Context, name string, signers []address.Address) error                                        `perm:"admin"`
+		AuthCreateToken       func(ctx context.Context, name string, perm string) (string, error)                                            `perm:"admin"`
+		AuthCreateUser        func(ctx context.Context, name string, comment string) error                                                   `perm:"admin"`
+		AuthDeleteUser        func(ctx context.Context, name string) error                                                                   `perm:"admin"`
+		AuthListTokens        func(ctx context.Context, name string) ([]*mtypes.AuthToken, error)                                            `perm:"admin"`
+		AuthListUsers         func(ctx context.Context) ([]*mtypes.AuthUser, error)                                                          `perm:"admin"`
+		AuthRemoveToken       func(ctx context.Context, token string) error                                                                  `perm:"admin"`
+		AuthUnbindSigner      func(ctx context.Context, name string, signers []address.Address) error                                        `perm:"admin"`
+		DiscoverAddress       func(ctx context.Context, register bool) ([]*mtypes.DiscoveredAddress, error)                                  `perm:"admin"`
+		GetLeaderInfo         func(ctx context.Context) (*mtypes.LeaderInfo, error)                                                          `perm:"read"`
+		GetMessagePropagation func(ctx context.Context, id string) (*mtypes.MessagePropagation, error)                                       `perm:"read"`
+		GetNodePoolInfo       func(ctx context.Context) (*mtypes.NodePoolInfo, error)                                                        `perm:"read"`
+		GetNodeSyncStatus     func(ctx context.Context) (*mtypes.NodeSyncStatus, error)                                                      `perm:"read"`
+		GetPublishRoute       func(ctx context.Context, id string) (*mtypes.PublishRoute, error)                                             `perm:"read"`
+		GetShardingInfo       func(ctx context.Context) (*mtypes.ShardingInfo, error)                                                        `perm:"read"`
+		InvalidateSignerCache func(ctx context.Context, addrs []address.Address) error                                                       `perm:"admin"`
+		ListAuditLog          func(ctx context.Context, query *mtypes.AuditQuery) ([]*mtypes.AuditLog, error)                                `perm:"admin"`
+		ListGatewayStatus     func(ctx context.Context) ([]*mtypes.GatewayStatus, error)                                                     `perm:"read"`
+		ListMessageApproval   func(ctx context.Context, from address.Address, state mtypes.ApprovalState) ([]*mtypes.MessageApproval, error) `perm:"admin"`
+		ListMessageHistory    func(ctx context.Context, id string) ([]*mtypes.MessageHistory, error)                                         `perm:"read"`
+		ListMessageVersion    func(ctx context.Context, id string) ([]*mtypes.MessageVersion, error)                                         `perm:"read"`
+		ListNodeHealth        func(ctx context.Context) ([]*mtypes.PublishNodeHealth, error)                                                 `perm:"read"`
+		ListPausedAddress     func(ctx context.Context) ([]*mtypes.PausedAddress, error)                                                     `perm:"read"`
+		ListPublishResult     func(ctx context.Context, id string) ([]*mtypes.PublishResult, error)                                          `perm:"read"`
+		ListTopUpRecord       func(ctx context.Context, addr address.Address) ([]*mtypes.TopUpRecord, error)                                 `perm:"admin"`
+		NetBanPeer            func(ctx context.Context, peerID peer.ID) error                                                                `perm:"admin"`
+		NetDisconnect         func(ctx context.Context, peerID peer.ID) error                                                                `perm:"admin"`
+		NetListBannedPeers    func(ctx context.Context) ([]*mtypes.BannedPeer, error)                                                        `perm:"read"`
+		NetListenInfo         func(ctx context.Context) (*mtypes.NetListenInfo, error)                                                       `perm:"read"`
+		NetPeersInfo          func(ctx context.Context) ([]*mtypes.PeerInfo, error)                                                          `perm:"read"`
+		NetUnbanPeer          func(ctx context.Context, peerID peer.ID) error                                                                `perm:"admin"`
+		RejectMessage         func(ctx context.Context, id string, comment string) error                                                     `perm:"admin"`
+		ResumeAddress         func(ctx context.Context, addr address.Address) error                                                          `perm:"admin"`
+	}
+}
+
+func (s *IMessagerExtStruct) ApproveMessage(p0 context.Context, p1 string, p2 string) error {
+	return s.Internal.ApproveMessage(p0, p1, p2)
+}
+func (s *IMessagerExtStruct) AuthBindSigner(p0 context.Context, p1 string, p2 []address.Address) error {
+	return s.Internal.AuthBindSigner(p0, p1, p2)
+}
+func (s *IMessagerExtStruct) AuthCreateToken(p0 context.Context, p1 string, p2 string) (string, error) {
+	return s.Internal.AuthCreateToken(p0, p1, p2)
+}
+func (s *IMessagerExtStruct) AuthCreateUser(p0 context.Context, p1 string, p2 string) error {
+	return s.Internal.AuthCreateUser(p0, p1, p2)
+}
+func (s *IMessagerExtStruct) AuthDeleteUser(p0 context.Context, p1 string) error {
+	return s.Internal.AuthDeleteUser(p0, p1)
+}
+func (s *IMessagerExtStruct) AuthListTokens(p0 context.Context, p1 string) ([]*mtypes.AuthToken, error) {
+	return s.Internal.AuthListTokens(p0, p1)
+}
+func (s *IMessagerExtStruct) AuthListUsers(p0 context.Context) ([]*mtypes.AuthUser, error) {
+	return s.Internal.AuthListUsers(p0)
+}
+func (s *IMessagerExtStruct) AuthRemoveToken(p0 context.Context, p1 string) error {
+	return s.Internal.AuthRemoveToken(p0, p1)
+}
+func (s *IMessagerExtStruct) AuthUnbindSigner(p0 context.Context, p1 string, p2 []address.Address) error {
+	return s.Internal.AuthUnbindSigner(p0, p1, p2)
+}
+func (s *IMessagerExtStruct) DiscoverAddress(p0 context.Context, p1 bool) ([]*mtypes.DiscoveredAddress, error) {
+	return s.Internal.DiscoverAddress(p0, p1)
+}
+func (s *IMessagerExtStruct) GetLeaderInfo(p0 context.Context) (*mtypes.LeaderInfo, error) {
+	return s.Internal.GetLeaderInfo(p0)
+}
+func (s *IMessagerExtStruct) GetMessagePropagation(p0 context.Context, p1 string) (*mtypes.MessagePropagation, error) {
+	return s.Internal.GetMessagePropagation(p0, p1)
+}
+func (s *IMessagerExtStruct) GetNodePoolInfo(p0 context.Context) (*mtypes.NodePoolInfo, error) {
+	return s.Internal.GetNodePoolInfo(p0)
+}
+func (s *IMessagerExtStruct) GetNodeSyncStatus(p0 context.Context) (*mtypes.NodeSyncStatus, error) {
+	return s.Internal.GetNodeSyncStatus(p0)
+}
+func (s *IMessagerExtStruct) GetPublishRoute(p0 context.Context, p1 string) (*mtypes.PublishRoute, error) {
+	return s.Internal.GetPublishRoute(p0, p1)
+}
+func (s *IMessagerExtStruct) GetShardingInfo(p0 context.Context) (*mtypes.ShardingInfo, error) {
+	return s.Internal.GetShardingInfo(p0)
+}
+func (s *IMessagerExtStruct) InvalidateSignerCache(p0 context.Context, p1 []address.Address) error {
+	return s.Internal.InvalidateSignerCache(p0, p1)
+}
+func (s *IMessagerExtStruct) ListAuditLog(p0 context.Context, p1 *mtypes.AuditQuery) ([]*mtypes.AuditLog, error) {
+	return s.Internal.ListAuditLog(p0, p1)
+}
+func (s *IMessagerExtStruct) ListGatewayStatus(p0 context.Context) ([]*mtypes.GatewayStatus, error) {
+	return s.Internal.ListGatewayStatus(p0)
+}
+func (s *IMessagerExtStruct) ListMessageApproval(p0 context.Context, p1 address.Address, p2 mtypes.ApprovalState) ([]*mtypes.MessageApproval, error) {
+	return s.Internal.ListMessageApproval(p0, p1, p2)
+}
+func (s *IMessagerExtStruct) ListMessageHistory(p0 context.Context, p1 string) ([]*mtypes.MessageHistory, error) {
+	return s.Internal.ListMessageHistory(p0, p1)
+}
+func (s *IMessagerExtStruct) ListMessageVersion(p0 context.Context, p1 string) ([]*mtypes.MessageVersion, error) {
+	return s.Internal.ListMessageVersion(p0, p1)
+}
+func (s *IMessagerExtStruct) ListNodeHealth(p0 context.Context) ([]*mtypes.PublishNodeHealth, error) {
+	return s.Internal.ListNodeHealth(p0)
+}
+func (s *IMessagerExtStruct) ListPausedAddress(p0 context.Context) ([]*mtypes.PausedAddress, error) {
+	return s.Internal.ListPausedAddress(p0)
+}
+func (s *IMessagerExtStruct) ListPublishResult(p0 context.Context, p1 string) ([]*mtypes.PublishResult, error) {
+	return s.Internal.ListPublishResult(p0, p1)
+}
+func (s *IMessagerExtStruct) ListTopUpRecord(p0 context.Context, p1 address.Address) ([]*mtypes.TopUpRecord, error) {
+	return s.Internal.ListTopUpRecord(p0, p1)
+}
+func (s *IMessagerExtStruct) NetBanPeer(p0 context.Context, p1 peer.ID) error {
+	return s.Internal.NetBanPeer(p0, p1)
+}
+func (s *IMessagerExtStruct) NetDisconnect(p0 context.Context, p1 peer.ID) error {
+	return s.Internal.NetDisconnect(p0, p1)
+}
+func (s *IMessagerExtStruct) NetListBannedPeers(p0 context.Context) ([]*mtypes.BannedPeer, error) {
+	return s.Internal.NetListBannedPeers(p0)
+}
+func (s *IMessagerExtStruct) NetListenInfo(p0 context.Context) (*mtypes.NetListenInfo, error) {
+	return s.Internal.NetListenInfo(p0)
+}
+func (s *IMessagerExtStruct) NetPeersInfo(p0 context.Context) ([]*mtypes.PeerInfo, error) {
+	return s.Internal.NetPeersInfo(p0)
+}
+func (s *IMessagerExtStruct) NetUnbanPeer(p0 context.Context, p1 peer.ID) error {
+	return s.Internal.NetUnbanPeer(p0, p1)
+}
+func (s *IMessagerExtStruct) RejectMessage(p0 context.Context, p1 string, p2 string) error {
+	return s.Internal.RejectMessage(p0, p1, p2)
+}
+func (s *IMessagerExtStruct) ResumeAddress(p0 context.Context, p1 address.Address) error {
+	return s.Internal.ResumeAddress(p0, p1)
+}
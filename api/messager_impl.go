@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/ipfs/go-cid"
@@ -10,11 +11,16 @@ import (
 	"go.uber.org/fx"
 
 	"github.com/filecoin-project/go-address"
+	venusauth "github.com/filecoin-project/venus-auth/auth"
 	"github.com/filecoin-project/venus-messager/publisher/pubsub"
-	"github.com/filecoin-project/venus/venus-shared/api/messager"
 	venusTypes "github.com/filecoin-project/venus/venus-shared/types"
 	types "github.com/filecoin-project/venus/venus-shared/types/messager"
 
+	"github.com/filecoin-project/venus-messager/api/extend"
+	"github.com/filecoin-project/venus-messager/gateway"
+	"github.com/filecoin-project/venus-messager/localauth"
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/publisher"
 	"github.com/filecoin-project/venus-messager/service"
 	"github.com/filecoin-project/venus-messager/version"
 )
@@ -25,25 +31,53 @@ type ImplParams struct {
 	MessageService      *service.MessageService
 	NodeService         service.INodeService
 	SharedParamsService *service.SharedParamsService
+	ApprovalService     *service.ApprovalService
+	AuditService        *service.AuditService
+	LeaderElector       *service.LeaderElector
+	AddressSharding     *service.AddressSharding
+	NodePool            *service.NodePool
+	RpcPublisher        *publisher.RpcPublisher
+	PropagationTracker  *publisher.PropagationTracker
 	Net                 pubsub.INet
+	GatewayStatus       gateway.IGatewayStatus
+	LocalAuth           *localauth.AuthClient `optional:"true"`
 }
 
 func NewMessageImp(implParams ImplParams) *MessageImp {
 	return &MessageImp{
-		AddressSrv: implParams.AddressService,
-		MessageSrv: implParams.MessageService,
-		NodeSrv:    implParams.NodeService,
-		ParamsSrv:  implParams.SharedParamsService,
-		Net:        implParams.Net,
+		AddressSrv:  implParams.AddressService,
+		MessageSrv:  implParams.MessageService,
+		NodeSrv:     implParams.NodeService,
+		ParamsSrv:   implParams.SharedParamsService,
+		ApprovalSrv: implParams.ApprovalService,
+		AuditSrv:    implParams.AuditService,
+		Elector:     implParams.LeaderElector,
+		Sharding:    implParams.AddressSharding,
+		NodePool:    implParams.NodePool,
+		Publisher:   implParams.RpcPublisher,
+		Propagation: implParams.PropagationTracker,
+		Net:         implParams.Net,
+		Gateway:     implParams.GatewayStatus,
+		LocalAuth:   implParams.LocalAuth,
 	}
 }
 
 type MessageImp struct {
-	AddressSrv *service.AddressService
-	MessageSrv *service.MessageService
-	NodeSrv    service.INodeService
-	ParamsSrv  *service.SharedParamsService
-	Net        pubsub.INet
+	AddressSrv  *service.AddressService
+	MessageSrv  *service.MessageService
+	NodeSrv     service.INodeService
+	ParamsSrv   *service.SharedParamsService
+	ApprovalSrv *service.ApprovalService
+	AuditSrv    *service.AuditService
+	Elector     *service.LeaderElector
+	Sharding    *service.AddressSharding
+	NodePool    *service.NodePool
+	Publisher   *publisher.RpcPublisher
+	Propagation *publisher.PropagationTracker
+	Net         pubsub.INet
+	Gateway     gateway.IGatewayStatus
+	// LocalAuth is nil unless `jwt.type` is `local`
+	LocalAuth *localauth.AuthClient
 }
 
 func (m MessageImp) HasMessageByUid(ctx context.Context, id string) (bool, error) {
@@ -55,11 +89,15 @@ func (m MessageImp) WaitMessage(ctx context.Context, id string, confidence uint6
 }
 
 func (m MessageImp) PushMessage(ctx context.Context, msg *venusTypes.Message, meta *types.SendSpec) (string, error) {
-	return m.MessageSrv.PushMessage(ctx, msg, meta)
+	id, err := m.MessageSrv.PushMessage(ctx, msg, meta)
+	m.audit(ctx, "PushMessage", map[string]interface{}{"msg": msg, "meta": meta}, nil, id, err)
+	return id, err
 }
 
 func (m MessageImp) PushMessageWithId(ctx context.Context, id string, msg *venusTypes.Message, meta *types.SendSpec) (string, error) {
-	return m.MessageSrv.PushMessageWithId(ctx, id, msg, meta)
+	res, err := m.MessageSrv.PushMessageWithId(ctx, id, msg, meta)
+	m.audit(ctx, "PushMessageWithId", map[string]interface{}{"id": id, "msg": msg, "meta": meta}, nil, res, err)
+	return res, err
 }
 
 func (m MessageImp) GetMessageByUid(ctx context.Context, id string) (*types.Message, error) {
@@ -99,31 +137,59 @@ func (m MessageImp) ListBlockedMessage(ctx context.Context, addr address.Address
 }
 
 func (m MessageImp) UpdateMessageStateByID(ctx context.Context, id string, state types.MessageState) error {
-	return m.MessageSrv.UpdateMessageStateByID(ctx, id, state)
+	prev := m.prevMessageState(ctx, id)
+	err := m.MessageSrv.UpdateMessageStateByID(ctx, id, state)
+	m.audit(ctx, "UpdateMessageStateByID", map[string]interface{}{"id": id, "state": state.String()}, prev, nil, err)
+	return err
 }
 
 func (m MessageImp) UpdateAllFilledMessage(ctx context.Context) (int, error) {
-	return m.MessageSrv.UpdateAllFilledMessage(ctx)
+	count, err := m.MessageSrv.UpdateAllFilledMessage(ctx)
+	m.audit(ctx, "UpdateAllFilledMessage", nil, nil, count, err)
+	return count, err
 }
 
 func (m MessageImp) UpdateFilledMessageByID(ctx context.Context, id string) (string, error) {
-	return m.MessageSrv.UpdateFilledMessageByID(ctx, id)
+	prev := m.prevMessageState(ctx, id)
+	res, err := m.MessageSrv.UpdateFilledMessageByID(ctx, id)
+	m.audit(ctx, "UpdateFilledMessageByID", map[string]interface{}{"id": id}, prev, res, err)
+	return res, err
 }
 
 func (m MessageImp) ReplaceMessage(ctx context.Context, params *types.ReplacMessageParams) (cid.Cid, error) {
-	return m.MessageSrv.ReplaceMessage(ctx, params)
+	var prev interface{}
+	if params != nil {
+		if msg, err := m.MessageSrv.GetMessageByUid(ctx, params.ID); err == nil {
+			prev = map[string]interface{}{
+				"gasLimit":   msg.GasLimit,
+				"gasFeeCap":  msg.GasFeeCap,
+				"gasPremium": msg.GasPremium,
+				"signedCid":  msg.SignedCid,
+			}
+		}
+	}
+	res, err := m.MessageSrv.ReplaceMessage(ctx, params)
+	m.audit(ctx, "ReplaceMessage", params, prev, res, err)
+	return res, err
 }
 
 func (m MessageImp) RepublishMessage(ctx context.Context, id string) error {
-	return m.MessageSrv.RepublishMessage(ctx, id)
+	err := m.MessageSrv.RepublishMessage(ctx, id)
+	m.audit(ctx, "RepublishMessage", map[string]interface{}{"id": id}, nil, nil, err)
+	return err
 }
 
 func (m MessageImp) MarkBadMessage(ctx context.Context, id string) error {
-	return m.MessageSrv.MarkBadMessage(ctx, id)
+	prev := m.prevMessageState(ctx, id)
+	err := m.MessageSrv.MarkBadMessage(ctx, id)
+	m.audit(ctx, "MarkBadMessage", map[string]interface{}{"id": id}, prev, nil, err)
+	return err
 }
 
 func (m MessageImp) RecoverFailedMsg(ctx context.Context, addr address.Address) ([]string, error) {
-	return m.MessageSrv.RecoverFailedMsg(ctx, addr)
+	ids, err := m.MessageSrv.RecoverFailedMsg(ctx, addr)
+	m.audit(ctx, "RecoverFailedMsg", map[string]interface{}{"addr": addr}, nil, ids, err)
+	return ids, err
 }
 
 func (m MessageImp) GetAddress(ctx context.Context, addr address.Address) (*types.Address, error) {
@@ -143,31 +209,54 @@ func (m MessageImp) ListAddress(ctx context.Context) ([]*types.Address, error) {
 }
 
 func (m MessageImp) UpdateNonce(ctx context.Context, addr address.Address, nonce uint64) error {
-	return m.AddressSrv.UpdateNonce(ctx, addr, nonce)
+	prev := m.prevAddress(ctx, addr)
+	err := m.AddressSrv.UpdateNonce(ctx, addr, nonce)
+	m.audit(ctx, "UpdateNonce", map[string]interface{}{"addr": addr, "nonce": nonce}, prev, nil, err)
+	return err
 }
 
 func (m MessageImp) DeleteAddress(ctx context.Context, addr address.Address) error {
-	return m.AddressSrv.DeleteAddress(ctx, addr)
+	prev := m.prevAddress(ctx, addr)
+	err := m.AddressSrv.DeleteAddress(ctx, addr)
+	m.audit(ctx, "DeleteAddress", map[string]interface{}{"addr": addr}, prev, nil, err)
+	return err
 }
 
 func (m MessageImp) ForbiddenAddress(ctx context.Context, addr address.Address) error {
-	return m.AddressSrv.ForbiddenAddress(ctx, addr)
+	prev := m.prevAddress(ctx, addr)
+	err := m.AddressSrv.ForbiddenAddress(ctx, addr)
+	m.audit(ctx, "ForbiddenAddress", map[string]interface{}{"addr": addr}, prev, nil, err)
+	return err
 }
 
 func (m MessageImp) ActiveAddress(ctx context.Context, addr address.Address) error {
-	return m.AddressSrv.ActiveAddress(ctx, addr)
+	prev := m.prevAddress(ctx, addr)
+	err := m.AddressSrv.ActiveAddress(ctx, addr)
+	m.audit(ctx, "ActiveAddress", map[string]interface{}{"addr": addr}, prev, nil, err)
+	return err
 }
 
 func (m MessageImp) SetSelectMsgNum(ctx context.Context, addr address.Address, num uint64) error {
-	return m.AddressSrv.SetSelectMsgNum(ctx, addr, num)
+	prev := m.prevAddress(ctx, addr)
+	err := m.AddressSrv.SetSelectMsgNum(ctx, addr, num)
+	m.audit(ctx, "SetSelectMsgNum", map[string]interface{}{"addr": addr, "num": num}, prev, nil, err)
+	return err
 }
 
 func (m MessageImp) SetFeeParams(ctx context.Context, params *types.AddressSpec) error {
-	return m.AddressSrv.SetFeeParams(ctx, params)
+	var prev interface{}
+	if params != nil {
+		prev = m.prevAddress(ctx, params.Address)
+	}
+	err := m.AddressSrv.SetFeeParams(ctx, params)
+	m.audit(ctx, "SetFeeParams", params, prev, nil, err)
+	return err
 }
 
 func (m MessageImp) ClearUnFillMessage(ctx context.Context, addr address.Address) (int, error) {
-	return m.MessageSrv.ClearUnFillMessage(ctx, addr)
+	count, err := m.MessageSrv.ClearUnFillMessage(ctx, addr)
+	m.audit(ctx, "ClearUnFillMessage", map[string]interface{}{"addr": addr}, nil, count, err)
+	return count, err
 }
 
 func (m MessageImp) GetSharedParams(ctx context.Context) (*types.SharedSpec, error) {
@@ -175,11 +264,20 @@ func (m MessageImp) GetSharedParams(ctx context.Context) (*types.SharedSpec, err
 }
 
 func (m MessageImp) SetSharedParams(ctx context.Context, params *types.SharedSpec) error {
-	return m.ParamsSrv.SetSharedParams(ctx, params)
+	prev, _ := m.ParamsSrv.GetSharedParams(ctx)
+	err := m.ParamsSrv.SetSharedParams(ctx, params)
+	m.audit(ctx, "SetSharedParams", params, prev, nil, err)
+	return err
 }
 
 func (m MessageImp) SaveNode(ctx context.Context, node *types.Node) error {
-	return m.NodeSrv.SaveNode(ctx, node)
+	var prev interface{}
+	if node != nil {
+		prev = m.prevNode(ctx, node.Name)
+	}
+	err := m.NodeSrv.SaveNode(ctx, node)
+	m.audit(ctx, "SaveNode", hideNodeToken(node), prev, nil, err)
+	return err
 }
 
 func (m MessageImp) GetNode(ctx context.Context, name string) (*types.Node, error) {
@@ -195,11 +293,16 @@ func (m MessageImp) ListNode(ctx context.Context) ([]*types.Node, error) {
 }
 
 func (m MessageImp) DeleteNode(ctx context.Context, name string) error {
-	return m.NodeSrv.DeleteNode(ctx, name)
+	prev := m.prevNode(ctx, name)
+	err := m.NodeSrv.DeleteNode(ctx, name)
+	m.audit(ctx, "DeleteNode", map[string]interface{}{"name": name}, prev, nil, err)
+	return err
 }
 
 func (m MessageImp) Send(ctx context.Context, params types.QuickSendParams) (string, error) {
-	return m.MessageSrv.Send(ctx, params)
+	id, err := m.MessageSrv.Send(ctx, params)
+	m.audit(ctx, "Send", params, nil, id, err)
+	return id, err
 }
 
 func (m MessageImp) NetFindPeer(ctx context.Context, peerID peer.ID) (peer.AddrInfo, error) {
@@ -207,7 +310,9 @@ func (m MessageImp) NetFindPeer(ctx context.Context, peerID peer.ID) (peer.AddrI
 }
 
 func (m MessageImp) NetConnect(ctx context.Context, pi peer.AddrInfo) error {
-	return m.Net.Connect(ctx, pi)
+	err := m.Net.Connect(ctx, pi)
+	m.audit(ctx, "NetConnect", pi, nil, nil, err)
+	return err
 }
 
 func (m MessageImp) NetPeers(ctx context.Context) ([]peer.AddrInfo, error) {
@@ -218,7 +323,41 @@ func (m MessageImp) NetAddrsListen(ctx context.Context) (peer.AddrInfo, error) {
 	return m.Net.AddrListen(ctx)
 }
 
-var _ messager.IMessager = (*MessageImp)(nil)
+func (m MessageImp) NetListenInfo(ctx context.Context) (*mtypes.NetListenInfo, error) {
+	return m.Net.ListenInfo(ctx)
+}
+
+func (m MessageImp) NetPeersInfo(ctx context.Context) ([]*mtypes.PeerInfo, error) {
+	return m.Net.PeersInfo(ctx)
+}
+
+func (m MessageImp) NetDisconnect(ctx context.Context, peerID peer.ID) error {
+	err := m.Net.Disconnect(ctx, peerID)
+	m.audit(ctx, "NetDisconnect", peerID, nil, nil, err)
+	return err
+}
+
+func (m MessageImp) NetBanPeer(ctx context.Context, peerID peer.ID) error {
+	err := m.Net.BanPeer(ctx, peerID)
+	m.audit(ctx, "NetBanPeer", peerID, nil, nil, err)
+	return err
+}
+
+func (m MessageImp) NetUnbanPeer(ctx context.Context, peerID peer.ID) error {
+	err := m.Net.UnbanPeer(ctx, peerID)
+	m.audit(ctx, "NetUnbanPeer", peerID, nil, nil, err)
+	return err
+}
+
+func (m MessageImp) NetListBannedPeers(ctx context.Context) ([]*mtypes.BannedPeer, error) {
+	return m.Net.ListBannedPeers(ctx)
+}
+
+func (m MessageImp) ListGatewayStatus(ctx context.Context) ([]*mtypes.GatewayStatus, error) {
+	return m.Gateway.ListGatewayStatus(ctx)
+}
+
+var _ extend.IMessagerExt = (*MessageImp)(nil)
 
 func (m MessageImp) Version(_ context.Context) (venusTypes.Version, error) {
 	return venusTypes.Version{
@@ -227,9 +366,196 @@ func (m MessageImp) Version(_ context.Context) (venusTypes.Version, error) {
 }
 
 func (m MessageImp) SetLogLevel(ctx context.Context, subSystem, level string) error {
-	return logging.SetLogLevel(subSystem, level)
+	err := logging.SetLogLevel(subSystem, level)
+	m.audit(ctx, "SetLogLevel", map[string]interface{}{"subSystem": subSystem, "level": level}, nil, nil, err)
+	return err
 }
 
 func (m MessageImp) LogList(ctx context.Context) ([]string, error) {
 	return logging.GetSubsystems(), nil
 }
+
+func (m MessageImp) ApproveMessage(ctx context.Context, id string, comment string) error {
+	err := m.ApprovalSrv.ApproveMessage(ctx, id, comment)
+	m.audit(ctx, "ApproveMessage", map[string]interface{}{"id": id, "comment": comment}, nil, nil, err)
+	return err
+}
+
+func (m MessageImp) RejectMessage(ctx context.Context, id string, comment string) error {
+	prev := m.prevMessageState(ctx, id)
+	err := m.ApprovalSrv.RejectMessage(ctx, id, comment)
+	m.audit(ctx, "RejectMessage", map[string]interface{}{"id": id, "comment": comment}, prev, nil, err)
+	return err
+}
+
+func (m MessageImp) ListMessageApproval(ctx context.Context, from address.Address, state mtypes.ApprovalState) ([]*mtypes.MessageApproval, error) {
+	return m.ApprovalSrv.ListMessageApproval(ctx, from, state)
+}
+
+func (m MessageImp) DiscoverAddress(ctx context.Context, register bool) ([]*mtypes.DiscoveredAddress, error) {
+	res, err := m.AddressSrv.DiscoverAddress(ctx, register)
+	if register {
+		m.audit(ctx, "DiscoverAddress", register, nil, nil, err)
+	}
+	return res, err
+}
+
+func (m MessageImp) ListPausedAddress(ctx context.Context) ([]*mtypes.PausedAddress, error) {
+	return m.MessageSrv.ListPausedAddress(ctx)
+}
+
+func (m MessageImp) ResumeAddress(ctx context.Context, addr address.Address) error {
+	err := m.MessageSrv.ResumeAddress(ctx, addr)
+	m.audit(ctx, "ResumeAddress", map[string]interface{}{"addr": addr}, nil, nil, err)
+	return err
+}
+
+func (m MessageImp) InvalidateSignerCache(ctx context.Context, addrs []address.Address) error {
+	err := m.AddressSrv.InvalidateSignerCache(ctx, addrs)
+	m.audit(ctx, "InvalidateSignerCache", map[string]interface{}{"addrs": addrs}, nil, nil, err)
+	return err
+}
+
+var errLocalAuthDisabled = fmt.Errorf("local auth is not enabled, set `jwt.type` to `local`")
+
+func (m MessageImp) AuthCreateUser(ctx context.Context, name string, comment string) error {
+	if m.LocalAuth == nil {
+		return errLocalAuthDisabled
+	}
+	err := m.LocalAuth.CreateUser(name, comment)
+	m.audit(ctx, "AuthCreateUser", map[string]interface{}{"name": name, "comment": comment}, nil, nil, err)
+	return err
+}
+
+func (m MessageImp) AuthDeleteUser(ctx context.Context, name string) error {
+	if m.LocalAuth == nil {
+		return errLocalAuthDisabled
+	}
+	err := m.LocalAuth.DeleteUser(name)
+	if err == nil {
+		// the signers of the user are gone with it, so invalidate all
+		_ = m.AddressSrv.InvalidateSignerCache(ctx, nil)
+	}
+	m.audit(ctx, "AuthDeleteUser", map[string]interface{}{"name": name}, nil, nil, err)
+	return err
+}
+
+func (m MessageImp) AuthListUsers(ctx context.Context) ([]*mtypes.AuthUser, error) {
+	if m.LocalAuth == nil {
+		return nil, errLocalAuthDisabled
+	}
+	return m.LocalAuth.ListLocalUsers()
+}
+
+func (m MessageImp) AuthCreateToken(ctx context.Context, name string, perm string) (string, error) {
+	if m.LocalAuth == nil {
+		return "", errLocalAuthDisabled
+	}
+	token, err := m.LocalAuth.CreateToken(name, perm)
+	// not save the token to audit log
+	m.audit(ctx, "AuthCreateToken", map[string]interface{}{"name": name, "perm": perm}, nil, nil, err)
+	return token, err
+}
+
+func (m MessageImp) AuthListTokens(ctx context.Context, name string) ([]*mtypes.AuthToken, error) {
+	if m.LocalAuth == nil {
+		return nil, errLocalAuthDisabled
+	}
+	return m.LocalAuth.ListTokens(name)
+}
+
+func (m MessageImp) AuthRemoveToken(ctx context.Context, token string) error {
+	if m.LocalAuth == nil {
+		return errLocalAuthDisabled
+	}
+	err := m.LocalAuth.RemoveToken(token)
+	name, _ := venusauth.JwtUserFromToken(token)
+	m.audit(ctx, "AuthRemoveToken", map[string]interface{}{"name": name}, nil, nil, err)
+	return err
+}
+
+func (m MessageImp) AuthBindSigner(ctx context.Context, name string, signers []address.Address) error {
+	if m.LocalAuth == nil {
+		return errLocalAuthDisabled
+	}
+	err := m.LocalAuth.BindSigners(name, signers)
+	if err == nil {
+		_ = m.AddressSrv.InvalidateSignerCache(ctx, signers)
+	}
+	m.audit(ctx, "AuthBindSigner", map[string]interface{}{"name": name, "signers": signers}, nil, nil, err)
+	return err
+}
+
+func (m MessageImp) AuthUnbindSigner(ctx context.Context, name string, signers []address.Address) error {
+	if m.LocalAuth == nil {
+		return errLocalAuthDisabled
+	}
+	err := m.LocalAuth.UnbindSigners(name, signers)
+	if err == nil {
+		_ = m.AddressSrv.InvalidateSignerCache(ctx, signers)
+	}
+	m.audit(ctx, "AuthUnbindSigner", map[string]interface{}{"name": name, "signers": signers}, nil, nil, err)
+	return err
+}
+
+func (m MessageImp) ListTopUpRecord(ctx context.Context, addr address.Address) ([]*mtypes.TopUpRecord, error) {
+	return m.MessageSrv.ListTopUpRecord(ctx, addr)
+}
+
+func (m MessageImp) ListMessageHistory(ctx context.Context, id string) ([]*mtypes.MessageHistory, error) {
+	return m.MessageSrv.ListMessageHistory(ctx, id)
+}
+
+func (m MessageImp) ListMessageVersion(ctx context.Context, id string) ([]*mtypes.MessageVersion, error) {
+	return m.MessageSrv.ListMessageVersion(ctx, id)
+}
+
+func (m MessageImp) GetLeaderInfo(ctx context.Context) (*mtypes.LeaderInfo, error) {
+	return m.Elector.LeaderInfo(ctx)
+}
+
+func (m MessageImp) GetShardingInfo(ctx context.Context) (*mtypes.ShardingInfo, error) {
+	return m.Sharding.ShardingInfo(ctx)
+}
+
+func (m MessageImp) GetNodeSyncStatus(ctx context.Context) (*mtypes.NodeSyncStatus, error) {
+	return m.MessageSrv.NodeSyncStatus(ctx)
+}
+
+func (m MessageImp) GetNodePoolInfo(ctx context.Context) (*mtypes.NodePoolInfo, error) {
+	return m.NodePool.NodePoolInfo(ctx)
+}
+
+func (m MessageImp) ListNodeHealth(ctx context.Context) ([]*mtypes.PublishNodeHealth, error) {
+	return m.Publisher.ListNodeHealth(ctx)
+}
+
+func (m MessageImp) ListPublishResult(ctx context.Context, id string) ([]*mtypes.PublishResult, error) {
+	msg, err := m.MessageSrv.GetMessageByUid(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if msg.SignedCid == nil {
+		return []*mtypes.PublishResult{}, nil
+	}
+	return m.Publisher.ListPublishResult(ctx, *msg.SignedCid)
+}
+
+func (m MessageImp) GetPublishRoute(ctx context.Context, id string) (*mtypes.PublishRoute, error) {
+	msg, err := m.MessageSrv.GetMessageByUid(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return m.Publisher.PublishRoute(ctx, msg.From)
+}
+
+func (m MessageImp) GetMessagePropagation(ctx context.Context, id string) (*mtypes.MessagePropagation, error) {
+	msg, err := m.MessageSrv.GetMessageByUid(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if msg.SignedCid == nil {
+		return nil, fmt.Errorf("message %s is not signed", id)
+	}
+	return m.Propagation.MessagePropagation(ctx, *msg.SignedCid)
+}
@@ -8,21 +8,30 @@ import (
 
 	"github.com/filecoin-project/go-jsonrpc"
 	"github.com/filecoin-project/venus-auth/jwtclient"
-	"github.com/filecoin-project/venus/venus-shared/api/messager"
 	"github.com/filecoin-project/venus/venus-shared/api/permission"
 	"github.com/ipfs-force-community/metrics/ratelimit"
 	logging "github.com/ipfs/go-log/v2"
 	"go.uber.org/fx"
 
+	"github.com/filecoin-project/venus-messager/api/extend"
 	"github.com/filecoin-project/venus-messager/config"
 )
 
 var log = logging.Logger("api")
 
-func BindRateLimit(msgImp *MessageImp, remoteAuthCli *jwtclient.AuthClient, rateLimitCfg *config.RateLimitConfig) (messager.IMessager, error) {
-	var msgAPI messager.IMessagerStruct
+// BindRateLimit wraps the api with the rate limiter, the limits of users are managed by venus-auth, so the limiter is
+// disabled with local auth, in which remoteAuthCli is nil
+func BindRateLimit(msgImp *MessageImp, remoteAuthCli *jwtclient.AuthClient, rateLimitCfg *config.RateLimitConfig) (extend.IMessagerExt, error) {
+	var msgAPI extend.IMessagerExtStruct
 	permission.PermissionProxy(msgImp, &msgAPI)
+	if msgImp.Elector != nil {
+		guardStandby(msgImp.Elector, &msgAPI.IMessagerStruct.Internal)
+		guardStandby(msgImp.Elector, &msgAPI.Internal)
+	}
 
+	if len(rateLimitCfg.Redis) != 0 && remoteAuthCli == nil {
+		log.Warnf("rate limit is disabled with local auth, which has no rate limits of users")
+	}
 	if len(rateLimitCfg.Redis) != 0 && remoteAuthCli != nil {
 		limiter, err := ratelimit.NewRateLimitHandler(
 			rateLimitCfg.Redis,
@@ -34,7 +43,8 @@ func BindRateLimit(msgImp *MessageImp, remoteAuthCli *jwtclient.AuthClient, rate
 		if err != nil {
 			return nil, err
 		}
-		var rateLimitAPI messager.IMessagerStruct
+		var rateLimitAPI extend.IMessagerExtStruct
+		limiter.WraperLimiter(msgAPI.IMessagerStruct.Internal, &rateLimitAPI.IMessagerStruct.Internal)
 		limiter.WraperLimiter(msgAPI.Internal, &rateLimitAPI.Internal)
 		msgAPI = rateLimitAPI
 	}
@@ -43,12 +53,12 @@ func BindRateLimit(msgImp *MessageImp, remoteAuthCli *jwtclient.AuthClient, rate
 
 // RunAPI bind rpc call and start rpc
 // todo
-func RunAPI(lc fx.Lifecycle, localAuthCli *jwtclient.LocalAuthClient, remoteAuthCli *jwtclient.AuthClient, lst net.Listener, msgImp messager.IMessager) error {
+func RunAPI(lc fx.Lifecycle, localAuthCli *jwtclient.LocalAuthClient, authCli jwtclient.IJwtAuthClient, lst net.Listener, msgImp extend.IMessagerExt) error {
 	srv := jsonrpc.NewServer()
 	srv.Register("Message", msgImp)
 	handler := http.NewServeMux()
 	handler.Handle("/rpc/v0", srv)
-	authMux := jwtclient.NewAuthMux(localAuthCli, jwtclient.WarpIJwtAuthClient(remoteAuthCli), handler)
+	authMux := jwtclient.NewAuthMux(localAuthCli, authCli, handler)
 	authMux.TrustHandle("/debug/pprof/", http.DefaultServeMux)
 
 	apiserv := &http.Server{
@@ -6,8 +6,8 @@ import (
 
 	"github.com/filecoin-project/venus-auth/jwtclient"
 	"github.com/filecoin-project/venus-messager/api"
+	"github.com/filecoin-project/venus-messager/api/extend"
 	"github.com/filecoin-project/venus-messager/config"
-	"github.com/filecoin-project/venus/venus-shared/api/messager"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/fx"
 )
@@ -27,6 +27,6 @@ func TestLimitWrap(t *testing.T) {
 		fx.Supply(&api.MessageImp{}),
 		fx.Provide(api.BindRateLimit),
 	)
-	app := fx.New(provider, fx.Invoke(func(_ messager.IMessager) error { return nil }))
+	app := fx.New(provider, fx.Invoke(func(_ extend.IMessagerExt) error { return nil }))
 	assert.Nil(t, app.Start(context.Background()))
 }
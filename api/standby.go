@@ -0,0 +1,45 @@
+package api
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/filecoin-project/venus/venus-shared/api/permission"
+
+	"github.com/filecoin-project/venus-messager/service"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// guardStandby wraps the functions of `internal`, which must be a pointer to the Internal struct of api proxy,
+// the functions which need a permission higher than read are refused when the instance is standby.
+func guardStandby(elector *service.LeaderElector, internal interface{}) {
+	rv := reflect.ValueOf(internal).Elem()
+	rt := rv.Type()
+	for i := 0; i < rv.NumField(); i++ {
+		field := rt.Field(i)
+		fn := rv.Field(i)
+		if fn.Kind() != reflect.Func || fn.IsNil() || field.Tag.Get("perm") == string(permission.PermRead) {
+			continue
+		}
+		ft := fn.Type()
+		if ft.NumOut() == 0 || ft.Out(ft.NumOut()-1) != errorType {
+			continue
+		}
+
+		// copy the function out of the field, the field is replaced below
+		name, origin := field.Name, reflect.ValueOf(fn.Interface())
+		rv.Field(i).Set(reflect.MakeFunc(ft, func(args []reflect.Value) []reflect.Value {
+			if elector.IsLeader() {
+				return origin.Call(args)
+			}
+			out := make([]reflect.Value, ft.NumOut())
+			for j := 0; j < ft.NumOut()-1; j++ {
+				out[j] = reflect.Zero(ft.Out(j))
+			}
+			err := fmt.Errorf("%s: %w, please call the leader", name, service.ErrNotLeader)
+			out[ft.NumOut()-1] = reflect.ValueOf(&err).Elem()
+			return out
+		}))
+	}
+}
@@ -3,10 +3,15 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/filecoin-project/go-address"
+	venusTypes "github.com/filecoin-project/venus/venus-shared/types"
 	"github.com/filecoin-project/venus/venus-shared/types/messager"
 	"github.com/urfave/cli/v2"
+
+	"github.com/filecoin-project/venus-messager/cli/tablewriter"
 )
 
 var AddrCmds = &cli.Command{
@@ -20,6 +25,11 @@ var AddrCmds = &cli.Command{
 		activeAddrCmd,
 		setAddrSelMsgNumCmd,
 		setFeeParamsCmd,
+		listTopUpCmd,
+		discoverAddrCmd,
+		pausedAddrCmd,
+		resumeAddrCmd,
+		invalidateSignerCacheCmd,
 	},
 }
 
@@ -265,3 +275,185 @@ var setFeeParamsCmd = &cli.Command{
 		return client.SetFeeParams(ctx.Context, params)
 	},
 }
+
+var listTopUpCmd = &cli.Command{
+	Name:      "list-top-up",
+	Usage:     "list the top up records, list all if address is not specified",
+	ArgsUsage: "[address]",
+	Action: func(ctx *cli.Context) error {
+		client, closer, err := getAPI(ctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		addr := address.Undef
+		if ctx.Args().Present() {
+			addr, err = address.NewFromString(ctx.Args().First())
+			if err != nil {
+				return err
+			}
+		}
+
+		records, err := client.ListTopUpRecord(ctx.Context, addr)
+		if err != nil {
+			return err
+		}
+
+		tw := tablewriter.New(
+			tablewriter.Col("Address"),
+			tablewriter.Col("Treasury"),
+			tablewriter.Col("Value"),
+			tablewriter.Col("Balance"),
+			tablewriter.Col("MsgID"),
+			tablewriter.Col("CreateAt"),
+		)
+		for _, r := range records {
+			tw.Write(map[string]interface{}{
+				"Address":  r.Addr,
+				"Treasury": r.Treasury,
+				"Value":    venusTypes.FIL(r.Value),
+				"Balance":  venusTypes.FIL(r.Balance),
+				"MsgID":    r.MsgID,
+				"CreateAt": r.CreatedAt.Format("2006-01-02 15:04:05"),
+			})
+		}
+
+		return tw.Flush(os.Stdout)
+	},
+}
+
+var discoverAddrCmd = &cli.Command{
+	Name:  "discover",
+	Usage: "list the signer addresses of the wallets connected to gateways",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "register",
+			Usage: "add the addresses not in messager with the default fee params, the deleted addresses are not added",
+		},
+	},
+	Action: func(ctx *cli.Context) error {
+		client, closer, err := getAPI(ctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		addrs, err := client.DiscoverAddress(ctx.Context, ctx.Bool("register"))
+		if err != nil {
+			return err
+		}
+
+		tw := tablewriter.New(
+			tablewriter.Col("Address"),
+			tablewriter.Col("Registered"),
+			tablewriter.Col("Deleted"),
+			tablewriter.Col("Wallets"),
+			tablewriter.NewLineCol("Accounts"),
+			tablewriter.NewLineCol("Error"),
+		)
+		for _, a := range addrs {
+			tw.Write(map[string]interface{}{
+				"Address":    a.Addr,
+				"Registered": a.Registered,
+				"Deleted":    a.Deleted,
+				"Wallets":    strings.Join(a.Wallets, ","),
+				"Accounts":   strings.Join(a.Accounts, ","),
+				"Error":      a.Error,
+			})
+		}
+
+		return tw.Flush(os.Stdout)
+	},
+}
+
+var pausedAddrCmd = &cli.Command{
+	Name:  "paused",
+	Usage: "list the addresses whose signing is paused for repeated signing failures",
+	Action: func(ctx *cli.Context) error {
+		client, closer, err := getAPI(ctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		addrs, err := client.ListPausedAddress(ctx.Context)
+		if err != nil {
+			return err
+		}
+
+		tw := tablewriter.New(
+			tablewriter.Col("Address"),
+			tablewriter.Col("Failures"),
+			tablewriter.Col("PausedAt"),
+			tablewriter.Col("ResumeAt"),
+			tablewriter.NewLineCol("Reason"),
+		)
+		for _, a := range addrs {
+			tw.Write(map[string]interface{}{
+				"Address":  a.Addr,
+				"Failures": a.Failures,
+				"PausedAt": a.PausedAt.Format("2006-01-02 15:04:05"),
+				"ResumeAt": a.ResumeAt.Format("2006-01-02 15:04:05"),
+				"Reason":   a.Reason,
+			})
+		}
+
+		return tw.Flush(os.Stdout)
+	},
+}
+
+var resumeAddrCmd = &cli.Command{
+	Name:      "resume",
+	Usage:     "resume signing of the paused address immediately",
+	ArgsUsage: "<address>",
+	Action: func(ctx *cli.Context) error {
+		client, closer, err := getAPI(ctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		if !ctx.Args().Present() {
+			return fmt.Errorf("must pass address")
+		}
+		addr, err := address.NewFromString(ctx.Args().First())
+		if err != nil {
+			return err
+		}
+
+		if err := client.ResumeAddress(ctx.Context, addr); err != nil {
+			return err
+		}
+		fmt.Printf("resume signing of %s\n", addr)
+		return nil
+	},
+}
+
+var invalidateSignerCacheCmd = &cli.Command{
+	Name:      "invalidate-cache",
+	Usage:     "remove the cached users of addresses, so they are fetched from the auth service next time, all are removed when no address is passed",
+	ArgsUsage: "[address]...",
+	Action: func(ctx *cli.Context) error {
+		client, closer, err := getAPI(ctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		addrs := make([]address.Address, 0, ctx.NArg())
+		for _, arg := range ctx.Args().Slice() {
+			addr, err := address.NewFromString(arg)
+			if err != nil {
+				return err
+			}
+			addrs = append(addrs, addr)
+		}
+
+		if err := client.InvalidateSignerCache(ctx.Context, addrs); err != nil {
+			return err
+		}
+		fmt.Println("invalidate cache success")
+		return nil
+	},
+}
@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-jsonrpc"
+	"github.com/urfave/cli/v2"
+
+	"github.com/filecoin-project/venus-messager/api/extend"
+	"github.com/filecoin-project/venus-messager/cli/tablewriter"
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+)
+
+var ApprovalCmds = &cli.Command{
+	Name:  "approval",
+	Usage: "approve or reject the messages which need a second approver",
+	Subcommands: []*cli.Command{
+		listApprovalCmd,
+		approveMsgCmd,
+		rejectMsgCmd,
+	},
+}
+
+var approverTokenFlag = &cli.StringFlag{
+	Name:  "token",
+	Usage: "token of the approver, default to use the local token",
+}
+
+var approvalCommentFlag = &cli.StringFlag{
+	Name:  "comment",
+	Usage: "comment of the decision",
+}
+
+func getApprovalAPI(ctx *cli.Context) (extend.IMessagerExt, jsonrpc.ClientCloser, error) {
+	if ctx.IsSet(approverTokenFlag.Name) {
+		return getAPIWithToken(ctx, ctx.String(approverTokenFlag.Name))
+	}
+	return getAPI(ctx)
+}
+
+var listApprovalCmd = &cli.Command{
+	Name:  "list",
+	Usage: "list message approvals",
+	Flags: []cli.Flag{
+		FromFlag,
+		&cli.IntFlag{
+			Name:  "state",
+			Usage: "approval state, 0: Pending, 1: Approved, 2: Rejected",
+			Value: int(mtypes.ApprovalPending),
+		},
+	},
+	Action: func(ctx *cli.Context) error {
+		client, closer, err := getAPI(ctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		from := address.Undef
+		if ctx.IsSet(FromFlag.Name) {
+			from, err = address.NewFromString(ctx.String(FromFlag.Name))
+			if err != nil {
+				return err
+			}
+		}
+
+		approvals, err := client.ListMessageApproval(ctx.Context, from, mtypes.ApprovalState(ctx.Int("state")))
+		if err != nil {
+			return err
+		}
+
+		tw := tablewriter.New(
+			tablewriter.Col("ID"),
+			tablewriter.Col("From"),
+			tablewriter.Col("State"),
+			tablewriter.Col("Reason"),
+			tablewriter.Col("Requester"),
+			tablewriter.Col("Approver"),
+			tablewriter.Col("Comment"),
+			tablewriter.Col("CreateAt"),
+		)
+		for _, approval := range approvals {
+			tw.Write(map[string]interface{}{
+				"ID":        approval.MsgID,
+				"From":      approval.From,
+				"State":     approval.State,
+				"Reason":    approval.Reason,
+				"Requester": approval.Requester,
+				"Approver":  approval.Approver,
+				"Comment":   approval.Comment,
+				"CreateAt":  approval.CreatedAt.Format("2006-01-02 15:04:05"),
+			})
+		}
+
+		return tw.Flush(os.Stdout)
+	},
+}
+
+var approveMsgCmd = &cli.Command{
+	Name:      "approve",
+	Usage:     "approve a message, the approver must be different from the one who pushed it",
+	ArgsUsage: "<id>",
+	Flags: []cli.Flag{
+		approverTokenFlag,
+		approvalCommentFlag,
+	},
+	Action: func(ctx *cli.Context) error {
+		if ctx.NArg() != 1 {
+			return fmt.Errorf("must pass message id")
+		}
+
+		client, closer, err := getApprovalAPI(ctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		id := ctx.Args().First()
+		if err := client.ApproveMessage(ctx.Context, id, ctx.String(approvalCommentFlag.Name)); err != nil {
+			return err
+		}
+		fmt.Printf("approve message %s success\n", id)
+
+		return nil
+	},
+}
+
+var rejectMsgCmd = &cli.Command{
+	Name:      "reject",
+	Usage:     "reject a message, the message will be marked as failed",
+	ArgsUsage: "<id>",
+	Flags: []cli.Flag{
+		approverTokenFlag,
+		approvalCommentFlag,
+	},
+	Action: func(ctx *cli.Context) error {
+		if ctx.NArg() != 1 {
+			return fmt.Errorf("must pass message id")
+		}
+
+		client, closer, err := getApprovalAPI(ctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		id := ctx.Args().First()
+		if err := client.RejectMessage(ctx.Context, id, ctx.String(approvalCommentFlag.Name)); err != nil {
+			return err
+		}
+		fmt.Printf("reject message %s success\n", id)
+
+		return nil
+	},
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/filecoin-project/venus-messager/cli/tablewriter"
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+)
+
+const auditTimeLayout = "2006-01-02 15:04:05"
+
+var AuditCmds = &cli.Command{
+	Name:  "audit",
+	Usage: "audit log of mutating api calls",
+	Subcommands: []*cli.Command{
+		listAuditCmd,
+	},
+}
+
+var listAuditCmd = &cli.Command{
+	Name:  "list",
+	Usage: "list audit logs, the latest first",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "account",
+			Usage: "filter by the account which called the api",
+		},
+		&cli.StringFlag{
+			Name:  "method",
+			Usage: "filter by the api method, eg. UpdateNonce",
+		},
+		&cli.StringFlag{
+			Name:  "start",
+			Usage: "filter logs created after the time, format: " + auditTimeLayout,
+		},
+		&cli.StringFlag{
+			Name:  "end",
+			Usage: "filter logs created before the time, format: " + auditTimeLayout,
+		},
+		&cli.IntFlag{
+			Name:  "limit",
+			Usage: "max number of logs to show",
+			Value: 100,
+		},
+		&cli.BoolFlag{
+			Name:  "verbose",
+			Usage: "show params, previous value and result",
+		},
+	},
+	Action: func(ctx *cli.Context) error {
+		client, closer, err := getAPI(ctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		query := &mtypes.AuditQuery{
+			Account: ctx.String("account"),
+			Method:  ctx.String("method"),
+			Limit:   ctx.Int("limit"),
+		}
+		if ctx.IsSet("start") {
+			query.Start, err = time.ParseInLocation(auditTimeLayout, ctx.String("start"), time.Local)
+			if err != nil {
+				return fmt.Errorf("parse start time failed: %v", err)
+			}
+		}
+		if ctx.IsSet("end") {
+			query.End, err = time.ParseInLocation(auditTimeLayout, ctx.String("end"), time.Local)
+			if err != nil {
+				return fmt.Errorf("parse end time failed: %v", err)
+			}
+		}
+
+		logs, err := client.ListAuditLog(ctx.Context, query)
+		if err != nil {
+			return err
+		}
+
+		verbose := ctx.Bool("verbose")
+		cols := []tablewriter.Column{
+			tablewriter.Col("Time"),
+			tablewriter.Col("Account"),
+			tablewriter.Col("Method"),
+			tablewriter.Col("Error"),
+		}
+		if verbose {
+			cols = append(cols, tablewriter.Col("Params"), tablewriter.Col("Previous"), tablewriter.Col("Result"))
+		}
+		tw := tablewriter.New(cols...)
+		for _, l := range logs {
+			row := map[string]interface{}{
+				"Time":    l.CreatedAt.Format(auditTimeLayout),
+				"Account": l.Account,
+				"Method":  l.Method,
+				"Error":   l.Error,
+			}
+			if verbose {
+				row["Params"] = l.Params
+				row["Previous"] = l.Previous
+				row["Result"] = l.Result
+			}
+			tw.Write(row)
+		}
+
+		return tw.Flush(os.Stdout)
+	},
+}
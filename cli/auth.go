@@ -0,0 +1,295 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/urfave/cli/v2"
+
+	"github.com/filecoin-project/venus-messager/cli/tablewriter"
+)
+
+var AuthCmds = &cli.Command{
+	Name:  "auth",
+	Usage: "manage the users, tokens and signer bindings of local auth, which is used when `jwt.type` is `local`",
+	Subcommands: []*cli.Command{
+		authUserCmds,
+		authTokenCmds,
+		authSignerCmds,
+	},
+}
+
+var authUserCmds = &cli.Command{
+	Name:  "user",
+	Usage: "manage users",
+	Subcommands: []*cli.Command{
+		addAuthUserCmd,
+		listAuthUserCmd,
+		removeAuthUserCmd,
+	},
+}
+
+var addAuthUserCmd = &cli.Command{
+	Name:      "add",
+	Usage:     "add a user",
+	ArgsUsage: "<name>",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "comment",
+			Usage: "comment of the user",
+		},
+	},
+	Action: func(ctx *cli.Context) error {
+		if !ctx.Args().Present() {
+			return fmt.Errorf("must pass name")
+		}
+
+		client, closer, err := getAPI(ctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		name := ctx.Args().First()
+		if err := client.AuthCreateUser(ctx.Context, name, ctx.String("comment")); err != nil {
+			return err
+		}
+		fmt.Printf("add user %s success\n", name)
+		return nil
+	},
+}
+
+var listAuthUserCmd = &cli.Command{
+	Name:  "list",
+	Usage: "list users with their signers",
+	Action: func(ctx *cli.Context) error {
+		client, closer, err := getAPI(ctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		users, err := client.AuthListUsers(ctx.Context)
+		if err != nil {
+			return err
+		}
+
+		tw := tablewriter.New(
+			tablewriter.Col("Name"),
+			tablewriter.Col("Comment"),
+			tablewriter.Col("CreatedAt"),
+			tablewriter.NewLineCol("Signers"),
+		)
+		for _, u := range users {
+			signers := make([]string, 0, len(u.Signers))
+			for _, signer := range u.Signers {
+				signers = append(signers, signer.String())
+			}
+			tw.Write(map[string]interface{}{
+				"Name":      u.Name,
+				"Comment":   u.Comment,
+				"CreatedAt": u.CreatedAt.Format("2006-01-02 15:04:05"),
+				"Signers":   strings.Join(signers, ","),
+			})
+		}
+
+		return tw.Flush(os.Stdout)
+	},
+}
+
+var removeAuthUserCmd = &cli.Command{
+	Name:      "remove",
+	Usage:     "remove a user with its tokens and signers",
+	ArgsUsage: "<name>",
+	Action: func(ctx *cli.Context) error {
+		if !ctx.Args().Present() {
+			return fmt.Errorf("must pass name")
+		}
+
+		client, closer, err := getAPI(ctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		name := ctx.Args().First()
+		if err := client.AuthDeleteUser(ctx.Context, name); err != nil {
+			return err
+		}
+		fmt.Printf("remove user %s success\n", name)
+		return nil
+	},
+}
+
+var authTokenCmds = &cli.Command{
+	Name:  "token",
+	Usage: "manage tokens",
+	Subcommands: []*cli.Command{
+		genAuthTokenCmd,
+		listAuthTokenCmd,
+		removeAuthTokenCmd,
+	},
+}
+
+var genAuthTokenCmd = &cli.Command{
+	Name:      "gen",
+	Usage:     "generate a token for the user, the token is only shown once",
+	ArgsUsage: "<name>",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "perm",
+			Usage: "permission of the token, one of read, write, sign and admin",
+			Value: "read",
+		},
+	},
+	Action: func(ctx *cli.Context) error {
+		if !ctx.Args().Present() {
+			return fmt.Errorf("must pass name")
+		}
+
+		client, closer, err := getAPI(ctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		token, err := client.AuthCreateToken(ctx.Context, ctx.Args().First(), ctx.String("perm"))
+		if err != nil {
+			return err
+		}
+		fmt.Println(token)
+		return nil
+	},
+}
+
+var listAuthTokenCmd = &cli.Command{
+	Name:      "list",
+	Usage:     "list the hashes of tokens, list all when user is not specified",
+	ArgsUsage: "[name]",
+	Action: func(ctx *cli.Context) error {
+		client, closer, err := getAPI(ctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		tokens, err := client.AuthListTokens(ctx.Context, ctx.Args().First())
+		if err != nil {
+			return err
+		}
+
+		tw := tablewriter.New(
+			tablewriter.Col("Name"),
+			tablewriter.Col("Perm"),
+			tablewriter.Col("CreatedAt"),
+			tablewriter.Col("TokenHash"),
+		)
+		for _, t := range tokens {
+			tw.Write(map[string]interface{}{
+				"Name":      t.Name,
+				"Perm":      t.Perm,
+				"CreatedAt": t.CreatedAt.Format("2006-01-02 15:04:05"),
+				"TokenHash": t.TokenHash,
+			})
+		}
+
+		return tw.Flush(os.Stdout)
+	},
+}
+
+var removeAuthTokenCmd = &cli.Command{
+	Name:      "remove",
+	Usage:     "remove a token by the token or its hash",
+	ArgsUsage: "<token|hash>",
+	Action: func(ctx *cli.Context) error {
+		if !ctx.Args().Present() {
+			return fmt.Errorf("must pass token or its hash")
+		}
+
+		client, closer, err := getAPI(ctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		if err := client.AuthRemoveToken(ctx.Context, ctx.Args().First()); err != nil {
+			return err
+		}
+		fmt.Println("remove token success")
+		return nil
+	},
+}
+
+var authSignerCmds = &cli.Command{
+	Name:  "signer",
+	Usage: "manage the signers bound to users, the messages of a signer can be pushed by the users bound to it",
+	Subcommands: []*cli.Command{
+		bindAuthSignerCmd,
+		unbindAuthSignerCmd,
+	},
+}
+
+var bindAuthSignerCmd = &cli.Command{
+	Name:      "bind",
+	Usage:     "bind signers to the user",
+	ArgsUsage: "<name> <address>...",
+	Action: func(ctx *cli.Context) error {
+		name, signers, err := parseUserSigners(ctx)
+		if err != nil {
+			return err
+		}
+
+		client, closer, err := getAPI(ctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		if err := client.AuthBindSigner(ctx.Context, name, signers); err != nil {
+			return err
+		}
+		fmt.Printf("bind %d signers to %s success\n", len(signers), name)
+		return nil
+	},
+}
+
+var unbindAuthSignerCmd = &cli.Command{
+	Name:      "unbind",
+	Usage:     "unbind signers from the user",
+	ArgsUsage: "<name> <address>...",
+	Action: func(ctx *cli.Context) error {
+		name, signers, err := parseUserSigners(ctx)
+		if err != nil {
+			return err
+		}
+
+		client, closer, err := getAPI(ctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		if err := client.AuthUnbindSigner(ctx.Context, name, signers); err != nil {
+			return err
+		}
+		fmt.Printf("unbind %d signers from %s success\n", len(signers), name)
+		return nil
+	},
+}
+
+func parseUserSigners(ctx *cli.Context) (string, []address.Address, error) {
+	if ctx.NArg() < 2 {
+		return "", nil, fmt.Errorf("must pass name and addresses")
+	}
+	signers := make([]address.Address, 0, ctx.NArg()-1)
+	for _, arg := range ctx.Args().Slice()[1:] {
+		addr, err := address.NewFromString(arg)
+		if err != nil {
+			return "", nil, err
+		}
+		signers = append(signers, addr)
+	}
+	return ctx.Args().First(), signers, nil
+}
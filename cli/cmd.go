@@ -10,12 +10,11 @@ import (
 	"github.com/mitchellh/go-homedir"
 	"github.com/urfave/cli/v2"
 
+	"github.com/filecoin-project/venus-messager/api/extend"
 	"github.com/filecoin-project/venus-messager/config"
-
-	"github.com/filecoin-project/venus/venus-shared/api/messager"
 )
 
-func getAPI(ctx *cli.Context) (messager.IMessager, jsonrpc.ClientCloser, error) {
+func getAPI(ctx *cli.Context) (extend.IMessagerExt, jsonrpc.ClientCloser, error) {
 	repo, err := getRepo(ctx)
 	if err != nil {
 		return nil, func() {}, err
@@ -27,7 +26,17 @@ func getAPI(ctx *cli.Context) (messager.IMessager, jsonrpc.ClientCloser, error)
 
 	cfg := repo.Config()
 
-	return messager.DialIMessagerRPC(ctx.Context, cfg.API.Address, string(token), nil)
+	return extend.DialIMessagerExtRPC(ctx.Context, cfg.API.Address, string(token), nil)
+}
+
+// getAPIWithToken connect to messager as the user of `token` instead of the local token
+func getAPIWithToken(ctx *cli.Context, token string) (extend.IMessagerExt, jsonrpc.ClientCloser, error) {
+	cfg, err := getConfig(ctx)
+	if err != nil {
+		return nil, func() {}, err
+	}
+
+	return extend.DialIMessagerExtRPC(ctx.Context, cfg.API.Address, token, nil)
 }
 
 func getNodeAPI(ctx *cli.Context) (v1.FullNode, jsonrpc.ClientCloser, error) {
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/filecoin-project/venus-messager/cli/tablewriter"
+)
+
+var GatewayCmds = &cli.Command{
+	Name:  "gateway",
+	Usage: "wallet gateways used to sign messages",
+	Subcommands: []*cli.Command{
+		listGatewayCmd,
+	},
+}
+
+var listGatewayCmd = &cli.Command{
+	Name:  "list",
+	Usage: "list the health, latency and errors of gateways",
+	Action: func(ctx *cli.Context) error {
+		client, closer, err := getAPI(ctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		status, err := client.ListGatewayStatus(ctx.Context)
+		if err != nil {
+			return err
+		}
+
+		tw := tablewriter.New(
+			tablewriter.Col("Url"),
+			tablewriter.Col("Weight"),
+			tablewriter.Col("Healthy"),
+			tablewriter.Col("Latency"),
+			tablewriter.Col("Requests"),
+			tablewriter.Col("Errors"),
+			tablewriter.Col("Failures"),
+			tablewriter.Col("Cached"),
+			tablewriter.Col("LastSuccess"),
+			tablewriter.NewLineCol("LastError"),
+		)
+		for _, s := range status {
+			lastSuccess := ""
+			if !s.LastSuccess.IsZero() {
+				lastSuccess = s.LastSuccess.Format(auditTimeLayout)
+			}
+			tw.Write(map[string]interface{}{
+				"Url":         s.Url,
+				"Weight":      s.Weight,
+				"Healthy":     s.Healthy,
+				"Latency":     s.Latency.String(),
+				"Requests":    s.Requests,
+				"Errors":      s.Errors,
+				"Failures":    s.ConsecutiveFailures,
+				"Cached":      s.CachedAddresses,
+				"LastSuccess": lastSuccess,
+				"LastError":   s.LastError,
+			})
+		}
+
+		return tw.Flush(os.Stdout)
+	},
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"strconv"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/urfave/cli/v2"
 
 	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/venus-messager/cli/tablewriter"
 	"github.com/filecoin-project/venus-messager/utils"
 
 	"github.com/filecoin-project/venus/pkg/constants"
@@ -34,6 +36,11 @@ var MsgCmds = &cli.Command{
 		markBadCmd,
 		clearUnFillMessageCmd,
 		recoverFailedMsgCmd,
+		historyCmd,
+		versionsCmd,
+		publishResultCmd,
+		publishRouteCmd,
+		propagationCmd,
 	},
 }
 
@@ -713,3 +720,230 @@ var clearUnFillMessageCmd = &cli.Command{
 		return nil
 	},
 }
+
+var historyCmd = &cli.Command{
+	Name:      "history",
+	Usage:     "show the state transition history of message",
+	ArgsUsage: "<id>",
+	Action: func(ctx *cli.Context) error {
+		if ctx.NArg() != 1 {
+			return fmt.Errorf("must pass message id")
+		}
+
+		client, closer, err := getAPI(ctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		history, err := client.ListMessageHistory(ctx.Context, ctx.Args().First())
+		if err != nil {
+			return err
+		}
+
+		tw := tablewriter.New(
+			tablewriter.Col("Time"),
+			tablewriter.Col("Trigger"),
+			tablewriter.Col("Height"),
+			tablewriter.Col("State"),
+			tablewriter.Col("GasLimit"),
+			tablewriter.Col("GasFeeCap"),
+			tablewriter.Col("GasPremium"),
+			tablewriter.Col("SignedCid"),
+			tablewriter.NewLineCol("Error"),
+		)
+		for _, h := range history {
+			signedCid := ""
+			if h.SignedCid != nil {
+				signedCid = h.SignedCid.String()
+			}
+			tw.Write(map[string]interface{}{
+				"Time":       h.CreatedAt.Format("2006-01-02 15:04:05"),
+				"Trigger":    h.Trigger,
+				"Height":     h.Height,
+				"State":      fmt.Sprintf("%s -> %s", h.OldState, h.NewState),
+				"GasLimit":   h.GasLimit,
+				"GasFeeCap":  h.GasFeeCap,
+				"GasPremium": h.GasPremium,
+				"SignedCid":  signedCid,
+				"Error":      h.Error,
+			})
+		}
+
+		return tw.Flush(os.Stdout)
+	},
+}
+
+var publishResultCmd = &cli.Command{
+	Name:      "publish-result",
+	Usage:     "show the results of pushing the latest signed version of message to every node",
+	ArgsUsage: "<id>",
+	Action: func(ctx *cli.Context) error {
+		if ctx.NArg() != 1 {
+			return fmt.Errorf("must pass message id")
+		}
+
+		client, closer, err := getAPI(ctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		results, err := client.ListPublishResult(ctx.Context, ctx.Args().First())
+		if err != nil {
+			return err
+		}
+
+		tw := tablewriter.New(
+			tablewriter.Col("Node"),
+			tablewriter.Col("Success"),
+			tablewriter.Col("Attempts"),
+			tablewriter.Col("Rejections"),
+			tablewriter.Col("UpdatedAt"),
+			tablewriter.Col("ErrorClass"),
+			tablewriter.NewLineCol("Error"),
+		)
+		for _, r := range results {
+			tw.Write(map[string]interface{}{
+				"Node":       r.Node,
+				"Success":    r.Success,
+				"Attempts":   r.Attempts,
+				"Rejections": r.Rejections,
+				"UpdatedAt":  r.UpdatedAt.Format("2006-01-02 15:04:05"),
+				"ErrorClass": r.ErrorClass,
+				"Error":      r.Error,
+			})
+		}
+
+		return tw.Flush(os.Stdout)
+	},
+}
+
+var publishRouteCmd = &cli.Command{
+	Name:      "publish-route",
+	Usage:     "show the nodes which message would be published to by the routing rules, without publishing it",
+	ArgsUsage: "<id>",
+	Action: func(ctx *cli.Context) error {
+		if ctx.NArg() != 1 {
+			return fmt.Errorf("must pass message id")
+		}
+
+		client, closer, err := getAPI(ctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		route, err := client.GetPublishRoute(ctx.Context, ctx.Args().First())
+		if err != nil {
+			return err
+		}
+
+		group := route.Group
+		if len(group) == 0 {
+			group = "-"
+		}
+		fmt.Printf("from: %s\ngroup: %s\ntags: %v\npubsub: %v\n\n", route.From, group, route.Tags, route.Pubsub)
+
+		tw := tablewriter.New(
+			tablewriter.Col("Node"),
+			tablewriter.Col("Selected"),
+			tablewriter.NewLineCol("Reason"),
+		)
+		for _, n := range route.Nodes {
+			tw.Write(map[string]interface{}{
+				"Node":     n.Name,
+				"Selected": n.Selected,
+				"Reason":   n.Reason,
+			})
+		}
+
+		return tw.Flush(os.Stdout)
+	},
+}
+
+var propagationCmd = &cli.Command{
+	Name:      "propagation",
+	Usage:     "show when the latest signed version of message is seen by network",
+	ArgsUsage: "<id>",
+	Action: func(ctx *cli.Context) error {
+		if ctx.NArg() != 1 {
+			return fmt.Errorf("must pass message id")
+		}
+
+		client, closer, err := getAPI(ctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		propagation, err := client.GetMessagePropagation(ctx.Context, ctx.Args().First())
+		if err != nil {
+			return err
+		}
+		if propagation == nil {
+			fmt.Println("message is not published recently")
+			return nil
+		}
+
+		bytes, err := json.MarshalIndent(propagation, " ", "\t")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(bytes))
+		return nil
+	},
+}
+
+var versionsCmd = &cli.Command{
+	Name:      "versions",
+	Usage:     "show all signed versions of message, the version marked with * is included by chain",
+	ArgsUsage: "<id>",
+	Action: func(ctx *cli.Context) error {
+		if ctx.NArg() != 1 {
+			return fmt.Errorf("must pass message id")
+		}
+
+		client, closer, err := getAPI(ctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		versions, err := client.ListMessageVersion(ctx.Context, ctx.Args().First())
+		if err != nil {
+			return err
+		}
+
+		tw := tablewriter.New(
+			tablewriter.Col("SignedCid"),
+			tablewriter.Col("Included"),
+			tablewriter.Col("Conflict"),
+			tablewriter.Col("GasLimit"),
+			tablewriter.Col("GasFeeCap"),
+			tablewriter.Col("GasPremium"),
+			tablewriter.Col("CreateAt"),
+		)
+		for _, v := range versions {
+			included := ""
+			if v.Included {
+				included = "*"
+			}
+			conflict := ""
+			if v.Conflict {
+				conflict = "*"
+			}
+			tw.Write(map[string]interface{}{
+				"SignedCid":  v.SignedCid,
+				"Included":   included,
+				"Conflict":   conflict,
+				"GasLimit":   v.GasLimit,
+				"GasFeeCap":  v.GasFeeCap,
+				"GasPremium": v.GasPremium,
+				"CreateAt":   v.CreatedAt.Format("2006-01-02 15:04:05"),
+			})
+		}
+
+		return tw.Flush(os.Stdout)
+	},
+}
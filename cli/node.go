@@ -8,6 +8,8 @@ import (
 	"github.com/urfave/cli/v2"
 
 	types "github.com/filecoin-project/venus/venus-shared/types/messager"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
 )
 
 var NodeCmds = &cli.Command{
@@ -112,11 +114,28 @@ var listNodeCmd = &cli.Command{
 		}
 		defer closer()
 
-		w, err := client.ListNode(ctx.Context)
+		nodes, err := client.ListNode(ctx.Context)
+		if err != nil {
+			return err
+		}
+		healths, err := client.ListNodeHealth(ctx.Context)
 		if err != nil {
 			return err
 		}
 
+		healthMap := make(map[string]*mtypes.PublishNodeHealth, len(healths))
+		for _, health := range healths {
+			healthMap[health.Name] = health
+		}
+		type nodeWithHealth struct {
+			*types.Node
+			Health *mtypes.PublishNodeHealth
+		}
+		w := make([]*nodeWithHealth, 0, len(nodes))
+		for _, node := range nodes {
+			w = append(w, &nodeWithHealth{Node: node, Health: healthMap[node.Name]})
+		}
+
 		bytes, err := json.MarshalIndent(w, " ", "\t")
 		if err != nil {
 			return err
@@ -55,6 +55,10 @@ var SendCmd = &cli.Command{
 			Usage:    "optionally specify the account to send",
 			Required: false,
 		},
+		&cli.BoolFlag{
+			Name:  "confirm",
+			Usage: "specify this flag to confirm sending funds when method is 0",
+		},
 	},
 	Action: func(ctx *cli.Context) error {
 		if ctx.Args().Len() != 2 {
@@ -112,6 +116,9 @@ var SendCmd = &cli.Command{
 		}
 
 		params.Method = abi.MethodNum(ctx.Uint64("method"))
+		if params.Method == builtin.MethodSend && !ctx.Bool("confirm") {
+			return fmt.Errorf("about to send %s from %s to %s, specify --confirm to continue", val, params.From, params.To)
+		}
 
 		if ctx.IsSet("params-json") {
 			params.Params = ctx.String("params-json")
@@ -2,10 +2,17 @@ package cli
 
 import (
 	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/filecoin-project/venus/pkg/net"
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/urfave/cli/v2"
+
+	"github.com/filecoin-project/venus-messager/api/extend"
+	"github.com/filecoin-project/venus-messager/cli/tablewriter"
 )
 
 var SwarmCmds = &cli.Command{
@@ -16,6 +23,10 @@ var SwarmCmds = &cli.Command{
 		connectByIdCmd,
 		connectByMutiAddrCmd,
 		peersCmd,
+		disconnectCmd,
+		banCmd,
+		unbanCmd,
+		bannedCmd,
 	},
 }
 
@@ -87,6 +98,12 @@ var connectByMutiAddrCmd = &cli.Command{
 var peersCmd = &cli.Command{
 	Name:  "peers",
 	Usage: "list swarm peers",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "protocols",
+			Usage: "output the protocols supported by peers",
+		},
+	},
 	Action: func(ctx *cli.Context) error {
 		api, closer, err := getAPI(ctx)
 		if err != nil {
@@ -94,22 +111,130 @@ var peersCmd = &cli.Command{
 		}
 		defer closer()
 
-		peers, err := api.NetPeers(ctx.Context)
+		peers, err := api.NetPeersInfo(ctx.Context)
 		if err != nil {
 			return err
 		}
+		sort.Slice(peers, func(i, j int) bool {
+			return peers[i].ID < peers[j].ID
+		})
 
+		cols := []tablewriter.Column{
+			tablewriter.Col("ID"),
+			tablewriter.Col("Agent"),
+			tablewriter.Col("Latency"),
+			tablewriter.Col("Protected"),
+			tablewriter.Col("Topics"),
+			tablewriter.Col("Addrs"),
+		}
+		if ctx.Bool("protocols") {
+			cols = append(cols, tablewriter.NewLineCol("Protocols"))
+		}
+		tw := tablewriter.New(cols...)
 		for _, p := range peers {
-			fmt.Printf("%s, %s\n", p.ID, p.Addrs)
+			row := map[string]interface{}{
+				"ID":        p.ID,
+				"Agent":     p.Agent,
+				"Latency":   p.Latency.Round(time.Millisecond),
+				"Protected": p.Protected,
+				"Topics":    strings.Join(p.Topics, ","),
+				"Addrs":     p.Addrs,
+			}
+			if ctx.Bool("protocols") {
+				row["Protocols"] = strings.Join(p.Protocols, " ")
+			}
+			tw.Write(row)
 		}
 
-		return nil
+		return tw.Flush(os.Stdout)
+	},
+}
+
+var disconnectCmd = &cli.Command{
+	Name:      "disconnect",
+	Usage:     "close the connections to peers",
+	ArgsUsage: "[peerIds]",
+	Action: func(ctx *cli.Context) error {
+		return forEachPeer(ctx, func(client extend.IMessagerExt, id peer.ID) error {
+			return client.NetDisconnect(ctx.Context, id)
+		})
+	},
+}
+
+var banCmd = &cli.Command{
+	Name:      "ban",
+	Usage:     "disconnect peers and reject the connections from and to them, the banned peers are persisted",
+	ArgsUsage: "[peerIds]",
+	Action: func(ctx *cli.Context) error {
+		return forEachPeer(ctx, func(client extend.IMessagerExt, id peer.ID) error {
+			return client.NetBanPeer(ctx.Context, id)
+		})
+	},
+}
+
+var unbanCmd = &cli.Command{
+	Name:      "unban",
+	Usage:     "allow the banned peers to connect",
+	ArgsUsage: "[peerIds]",
+	Action: func(ctx *cli.Context) error {
+		return forEachPeer(ctx, func(client extend.IMessagerExt, id peer.ID) error {
+			return client.NetUnbanPeer(ctx.Context, id)
+		})
+	},
+}
+
+var bannedCmd = &cli.Command{
+	Name:  "banned",
+	Usage: "list the banned peers",
+	Action: func(ctx *cli.Context) error {
+		client, closer, err := getAPI(ctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		banned, err := client.NetListBannedPeers(ctx.Context)
+		if err != nil {
+			return err
+		}
+
+		tw := tablewriter.New(tablewriter.Col("ID"), tablewriter.Col("BannedAt"))
+		for _, p := range banned {
+			tw.Write(map[string]interface{}{
+				"ID":       p.ID,
+				"BannedAt": p.BannedAt.Format("2006-01-02 15:04:05"),
+			})
+		}
+		return tw.Flush(os.Stdout)
 	},
 }
 
+func forEachPeer(ctx *cli.Context, f func(client extend.IMessagerExt, id peer.ID) error) error {
+	if ctx.Args().Len() < 1 {
+		return fmt.Errorf("must specify peer id")
+	}
+
+	client, closer, err := getAPI(ctx)
+	if err != nil {
+		return err
+	}
+	defer closer()
+
+	for _, p := range ctx.Args().Slice() {
+		id, err := peer.Decode(p)
+		if err != nil {
+			return fmt.Errorf("invalid peer id: %s", p)
+		}
+		if err := f(client, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 var addressListenCmd = &cli.Command{
 	Name:  "listen",
-	Usage: "output the listen addresses",
+	Usage: "output the listen and announced addresses, and the NAT traversal options",
 	Action: func(ctx *cli.Context) error {
 		api, closer, err := getAPI(ctx)
 		if err != nil {
@@ -117,12 +242,26 @@ var addressListenCmd = &cli.Command{
 		}
 		defer closer()
 
-		peerInfo, err := api.NetAddrsListen(ctx.Context)
+		info, err := api.NetListenInfo(ctx.Context)
 		if err != nil {
 			return err
 		}
 
-		fmt.Printf("%s, %s\n", peerInfo.ID, peerInfo.Addrs)
+		fmt.Println("ID:", info.ID)
+		fmt.Println("Listen addresses:")
+		for _, addr := range info.ListenAddrs {
+			fmt.Println("\t", addr)
+		}
+		fmt.Println("Announced addresses:")
+		for _, addr := range info.Addrs {
+			fmt.Println("\t", addr)
+		}
+		fmt.Println("Relay:", info.EnableRelay)
+		if len(info.StaticRelays) > 0 {
+			fmt.Println("Static relays:", info.StaticRelays)
+		}
+		fmt.Println("Hole punching:", info.EnableHolePunching)
+		fmt.Println("NAT port map:", info.EnableNATPortMap)
 		return nil
 	},
 }
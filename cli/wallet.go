@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/venus/pkg/crypto"
+	"github.com/urfave/cli/v2"
+
+	"github.com/filecoin-project/venus-messager/cli/tablewriter"
+	"github.com/filecoin-project/venus-messager/keystore"
+)
+
+var WalletCmds = &cli.Command{
+	Name:  "wallet",
+	Usage: "manage the keys of local keystore, which is used when `wallet.type` is `local`",
+	Subcommands: []*cli.Command{
+		importWalletCmd,
+		listWalletCmd,
+		exportWalletCmd,
+	},
+}
+
+var importWalletCmd = &cli.Command{
+	Name:      "import",
+	Usage:     "import a key exported by lotus, venus wallet or `wallet export`, read from stdin if file is not specified",
+	ArgsUsage: "[file]",
+	Action: func(ctx *cli.Context) error {
+		ks, err := openKeystore(ctx)
+		if err != nil {
+			return err
+		}
+
+		var data []byte
+		if ctx.Args().Present() {
+			data, err = os.ReadFile(ctx.Args().First())
+		} else {
+			data, err = io.ReadAll(os.Stdin)
+		}
+		if err != nil {
+			return err
+		}
+
+		ki, err := decodeKeyInfo(data)
+		if err != nil {
+			return err
+		}
+		addr, err := ks.Import(ki)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("imported key %s\n", addr)
+		return nil
+	},
+}
+
+var listWalletCmd = &cli.Command{
+	Name:  "list",
+	Usage: "list the addresses of local keystore",
+	Action: func(ctx *cli.Context) error {
+		ks, err := openKeystore(ctx)
+		if err != nil {
+			return err
+		}
+
+		addrs, err := ks.List()
+		if err != nil {
+			return err
+		}
+
+		tw := tablewriter.New(tablewriter.Col("Address"), tablewriter.Col("Type"))
+		for _, addr := range addrs {
+			ki, err := ks.Get(addr)
+			if err != nil {
+				return err
+			}
+			sigType, err := ki.SigType.Name()
+			if err != nil {
+				return err
+			}
+			tw.Write(map[string]interface{}{
+				"Address": addr,
+				"Type":    sigType,
+			})
+		}
+
+		return tw.Flush(os.Stdout)
+	},
+}
+
+var exportWalletCmd = &cli.Command{
+	Name:      "export",
+	Usage:     "export the key of address as hex, which can be imported by lotus and venus wallet",
+	ArgsUsage: "<address>",
+	Action: func(ctx *cli.Context) error {
+		if !ctx.Args().Present() {
+			return fmt.Errorf("must pass address")
+		}
+		addr, err := address.NewFromString(ctx.Args().First())
+		if err != nil {
+			return err
+		}
+
+		ks, err := openKeystore(ctx)
+		if err != nil {
+			return err
+		}
+		ki, err := ks.Get(addr)
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(ki)
+		if err != nil {
+			return err
+		}
+		fmt.Println(hex.EncodeToString(data))
+		return nil
+	},
+}
+
+func openKeystore(ctx *cli.Context) (*keystore.Keystore, error) {
+	repo, err := getRepo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	passphrase, err := keystore.Passphrase(&repo.Config().Wallet)
+	if err != nil {
+		return nil, err
+	}
+	return keystore.Open(repo.KeystoreDir(), passphrase)
+}
+
+// decodeKeyInfo decodes the key info in hex encoded json, or json
+func decodeKeyInfo(data []byte) (*crypto.KeyInfo, error) {
+	str := strings.TrimSpace(string(data))
+	if decoded, err := hex.DecodeString(str); err == nil {
+		str = string(decoded)
+	}
+	var ki crypto.KeyInfo
+	if err := json.Unmarshal([]byte(str), &ki); err != nil {
+		return nil, fmt.Errorf("decode key info failed: %w", err)
+	}
+	return &ki, nil
+}
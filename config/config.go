@@ -14,16 +14,30 @@ type Config struct {
 	Node           NodeConfig             `toml:"node"`
 	MessageService MessageServiceConfig   `toml:"messageService"`
 	Gateway        GatewayConfig          `toml:"gateway"`
+	Wallet         WalletConfig           `toml:"wallet"`
 	RateLimit      RateLimitConfig        `toml:"rateLimit"`
 	Trace          *metrics.TraceConfig   `toml:"tracing"`
 	Metrics        *metrics.MetricsConfig `toml:"metrics"`
 	Libp2pNet      *Libp2pNetConfig       `toml:"libp2p"`
 	Publisher      *PublisherConfig       `toml:"publisher"`
+	Approval       *ApprovalConfig        `toml:"approval"`
+	Transfer       *TransferConfig        `toml:"transfer"`
+	TopUp          *TopUpConfig           `toml:"topUp"`
+	HA             *HAConfig              `toml:"ha"`
+	Sharding       *ShardingConfig        `toml:"sharding"`
 }
 
 type NodeConfig struct {
 	Url   string `toml:"url"`
 	Token string `toml:"token"`
+
+	// EnableFailover follows chain head from the healthiest one of the main node and the nodes added by `node add`,
+	// and switches to another node when the active one disconnected or fell behind.
+	EnableFailover bool `toml:"enableFailover"`
+	// HealthCheckInterval is how often to check the head of nodes
+	HealthCheckInterval time.Duration `toml:"healthCheckInterval"`
+	// MaxLag is how many epochs a node can fall behind the highest node before it is considered unhealthy
+	MaxLag int64 `toml:"maxLag"`
 }
 
 type LogConfig struct {
@@ -54,15 +68,46 @@ type MySqlConfig struct {
 }
 
 type JWTConfig struct {
+	// Type is the auth provider, `remote` or `local`
+	Type    string `toml:"type"`
 	AuthURL string `toml:"authURL"`
+	// SignerCache caches the users bound to signers, which are queried for each address in every selecting round
+	SignerCache SignerCacheConfig `toml:"signerCache"`
+}
+
+type SignerCacheConfig struct {
+	// TTL is the duration the users of a signer are cached, 0 means not cache
+	TTL time.Duration `toml:"ttl"`
+	// StalePolicy decides whether to keep using the expired users when the auth service fails, `keep` or `reject`
+	StalePolicy string `toml:"stalePolicy"`
+	// MaxStale is the longest duration the expired users are kept using by `keep` policy, 0 means not limit
+	MaxStale time.Duration `toml:"maxStale"`
 }
 
+const (
+	// AuthRemote verifies tokens and finds the users of signers by venus-auth at AuthURL
+	AuthRemote = "remote"
+	// AuthLocal keeps users, tokens and signer bindings in the db of messager, which are managed by `auth` commands
+	AuthLocal = "local"
+)
+
+const (
+	// StaleKeep keeps signing with the expired users of signers during an outage of the auth service
+	StaleKeep = "keep"
+	// StaleReject stops signing once the users of signers expire and can't be refreshed
+	StaleReject = "reject"
+)
+
 const (
 	MinWaitingChainHeadStableDuration = time.Second * 2
 	MaxWaitingChainHeadStableDuration = time.Second * 25
 	DefWaitingChainHeadStableDuration = time.Second * 8
 )
 
+// DefMaxHeadDelayEpoch is the default MaxHeadDelayEpoch, selecting pauses when the head falls behind more than
+// a few blocks
+const DefMaxHeadDelayEpoch = 5
+
 const (
 	DefaultTimeout         = time.Second
 	SignMessageTimeout     = time.Second * 3
@@ -78,11 +123,29 @@ type MessageServiceConfig struct {
 
 	SkipProcessHead bool `toml:"skipProcessHead"`
 	SkipPushMessage bool `toml:"skipPushMessage"`
+
+	// MaxHeadDelayEpoch pause selecting messages when the chain head is older than MaxHeadDelayEpoch block delays,
+	// or the node is syncing a target higher than that when CheckSyncState is enabled. Default is
+	// DefMaxHeadDelayEpoch, set 0 to disable the check.
+	MaxHeadDelayEpoch int64 `toml:"maxHeadDelayEpoch"`
+	// CheckSyncState also check the SyncState of node before selecting messages
+	CheckSyncState bool `toml:"checkSyncState"`
+
+	// SignFailureThreshold pause signing messages of an address after the number of consecutive signing failures,
+	// 0 means never pause. The address is tried again after SignPauseCooldown, or earlier when the wallet has it.
+	SignFailureThreshold int           `toml:"signFailureThreshold"`
+	SignPauseCooldown    time.Duration `toml:"signPauseCooldown"`
 }
 
 type Libp2pNetConfig struct {
-	ListenAddress      string   `toml:"listenAddresses"`
-	BootstrapAddresses []string `toml:"bootstrapAddresses"`
+	// ListenAddress is the addresses to listen on, multiple addresses are separated by comma,
+	// eg. "/ip4/0.0.0.0/tcp/0,/ip6/::/tcp/0".
+	ListenAddress string `toml:"listenAddresses"`
+	// AnnounceAddresses replace the listen addresses announced to peers if not empty,
+	// NoAnnounceAddresses are never announced to peers.
+	AnnounceAddresses   []string `toml:"announceAddresses"`
+	NoAnnounceAddresses []string `toml:"noAnnounceAddresses"`
+	BootstrapAddresses  []string `toml:"bootstrapAddresses"`
 
 	// MinPeerThreshold determine when to expand peers.
 	// default set to 0 which means use network default config.
@@ -92,7 +155,53 @@ type Libp2pNetConfig struct {
 	// default set to "0s" which means use network default config.
 	// otherwise, it should be a duration string like "5s", "30s".
 	ExpandPeriod time.Duration `toml:"expandPeriod"`
-	// TODO: EnableRelay
+
+	// EnableRelay enables the circuit relay v2 client, which dials and accepts connections through relays.
+	EnableRelay bool `toml:"enableRelay"`
+	// StaticRelays are the relays to reserve slots on when the host is not reachable publicly,
+	// the addresses must contain peer id, it requires EnableRelay.
+	StaticRelays []string `toml:"staticRelays"`
+	// EnableHolePunching tries to upgrade the relayed connections to direct connections.
+	EnableHolePunching bool `toml:"enableHolePunching"`
+	// EnableNATPortMap tries to open a port in the NAT device by UPnP or NAT-PMP.
+	EnableNATPortMap bool `toml:"enableNATPortMap"`
+
+	// ConnMgrLow and ConnMgrHigh are the watermarks of connection manager, the connections are pruned to ConnMgrLow
+	// when they exceed ConnMgrHigh, and the connections opened in ConnMgrGrace are not pruned.
+	// ConnMgrHigh set to 0 means no limit.
+	ConnMgrLow   int           `toml:"connMgrLow"`
+	ConnMgrHigh  int           `toml:"connMgrHigh"`
+	ConnMgrGrace time.Duration `toml:"connMgrGrace"`
+	// ProtectedPeers are never pruned by connection manager, it's a peer id or an address with peer id,
+	// eg. "/ip4/1.2.3.4/tcp/1234/p2p/12D3KooW...", the peers with address are connected at startup.
+	ProtectedPeers []string `toml:"protectedPeers"`
+
+	GossipSub GossipSubConfig `toml:"gossipsub"`
+}
+
+// GossipSubConfig is the parameters of gossipsub router, they are validated at startup.
+type GossipSubConfig struct {
+	HeartbeatInterval time.Duration `toml:"heartbeatInterval"`
+	// FloodPublish publishes messages to all the peers above PublishThreshold rather than the mesh peers only.
+	FloodPublish bool `toml:"floodPublish"`
+	// D is the desired degree of the topic mesh, which is kept between Dlo and Dhi.
+	D   int `toml:"d"`
+	Dlo int `toml:"dlo"`
+	Dhi int `toml:"dhi"`
+	// DirectPeers are the addresses with peer id, messages are always forwarded to them and they are reconnected
+	// when disconnected, eg. our own lotus/venus nodes. Direct peers must configure us as direct peer too.
+	DirectPeers []string `toml:"directPeers"`
+
+	// EnablePeerScore scores peers by their behaviour in the messages topic, the peers scored below the thresholds
+	// are not gossiped to, published to or even ignored.
+	EnablePeerScore bool `toml:"enablePeerScore"`
+	// MessageTopicWeight is the weight of the messages topic score.
+	MessageTopicWeight          float64 `toml:"messageTopicWeight"`
+	GossipThreshold             float64 `toml:"gossipThreshold"`
+	PublishThreshold            float64 `toml:"publishThreshold"`
+	GraylistThreshold           float64 `toml:"graylistThreshold"`
+	AcceptPXThreshold           float64 `toml:"acceptPXThreshold"`
+	OpportunisticGraftThreshold float64 `toml:"opportunisticGraftThreshold"`
 }
 
 type PublisherConfig struct {
@@ -108,6 +217,135 @@ type PublisherConfig struct {
 
 	EnableP2P       bool `toml:"enablePubsub"`
 	EnableMultiNode bool `toml:"enableMultiNode"`
+
+	// NodeFailureThreshold is the number of consecutive failures after which a node is excluded from publishing.
+	// 0 means never exclude nodes.
+	NodeFailureThreshold int `toml:"nodeFailureThreshold"`
+	// NodeRetryBackoff is how long to wait before retrying an excluded node, it doubles on every failure up to 10 minutes.
+	NodeRetryBackoff time.Duration `toml:"nodeRetryBackoff"`
+	// NodeProbeInterval is how often to check the head of nodes, 0 means not probe.
+	NodeProbeInterval time.Duration `toml:"nodeProbeInterval"`
+	// NodeWeights is the weight of nodes keyed by node name, default to 1. Messages are pushed to the nodes with higher
+	// weight first, the nodes with weight 0 are backups which are used only when no other node is healthy.
+	NodeWeights map[string]int `toml:"nodeWeights"`
+	// MaxPublishNodes is the max number of nodes messages are pushed to besides the main node, 0 means all nodes.
+	MaxPublishNodes int `toml:"maxPublishNodes"`
+	// RejectThreshold is the number of consecutive rejections by a node after which the error is recorded in the
+	// message, 0 means not record.
+	RejectThreshold int `toml:"rejectThreshold"`
+
+	// ConfirmPropagation subscribes the messages topic to confirm the messages published are seen by network,
+	// the node relays all messages of network when it is enabled.
+	ConfirmPropagation bool `toml:"confirmPropagation"`
+	// PropagationTimeout is how long to wait for a message to be seen by network before republishing it by rpc nodes
+	PropagationTimeout time.Duration `toml:"propagationTimeout"`
+
+	Routing RoutingConfig `toml:"routing"`
+}
+
+// RoutingConfig restricts the nodes which messages of some addresses are published to. The addresses without rule
+// are published to all nodes.
+type RoutingConfig struct {
+	// NodeTags are the tags of nodes keyed by node name or url, the main node is named "mainNode". When the main
+	// node is a node pool, the tags of its active node are checked.
+	NodeTags map[string][]string `toml:"nodeTags"`
+	// AddressGroups are the addresses keyed by group name, an address matches both its ID and robust form. The
+	// messages of an address which can't be resolved are not published.
+	AddressGroups map[string][]string `toml:"addressGroups"`
+	// Rules are matched in order, the first rule containing the address takes effect
+	Rules []RoutingRule `toml:"rules"`
+}
+
+type RoutingRule struct {
+	// Group is the name of address group the rule applies to
+	Group string `toml:"group"`
+	// Tags are the tags of nodes which the messages are published to, a node having any of the tags is used
+	Tags []string `toml:"tags"`
+	// EnablePubsub allows publishing the messages by pubsub when it is enabled
+	EnablePubsub bool `toml:"enablePubsub"`
+}
+
+type ApprovalConfig struct {
+	// Enable require a second account to approve high-value or sensitive messages before they are selected.
+	Enable bool `toml:"enable"`
+
+	// ValueThreshold is the value in FIL, messages whose value is greater than or equal to it need approval.
+	// empty or zero means value is not checked.
+	ValueThreshold string `toml:"valueThreshold"`
+
+	// Methods is the name of actor methods that need approval, eg. "ChangeOwnerAddress", "WithdrawBalance".
+	Methods []string `toml:"methods"`
+}
+
+type TransferConfig struct {
+	// Enable allow to send funds through `Send`
+	Enable bool `toml:"enable"`
+
+	// MaxValue is the max value in FIL of a single transfer, it applies to the address not in AddressMaxValue.
+	// empty or zero means no limit.
+	MaxValue string `toml:"maxValue"`
+
+	// AddressMaxValue is the max value in FIL of a single transfer for the specified from address.
+	AddressMaxValue map[string]string `toml:"addressMaxValue"`
+
+	// AllowedTo is the destination allowlist, empty means all destinations are allowed.
+	AllowedTo []string `toml:"allowedTo"`
+}
+
+type TopUpConfig struct {
+	Enable bool `toml:"enable"`
+
+	// CheckInterval is how often to check the balance of addresses.
+	CheckInterval time.Duration `toml:"checkInterval"`
+
+	// MinInterval is the minimum interval between two top ups of the same address.
+	MinInterval time.Duration `toml:"minInterval"`
+
+	// DailyCap is the max value in FIL that one treasury address can pay within 24 hours.
+	// empty or zero means no limit.
+	DailyCap string `toml:"dailyCap"`
+
+	Rules []TopUpRule `toml:"rules"`
+}
+
+// TopUpRule keep the balance of Address at least MinBalance, if not, top up to TargetBalance from Treasury
+type TopUpRule struct {
+	Address       string `toml:"address"`
+	Treasury      string `toml:"treasury"`
+	MinBalance    string `toml:"minBalance"`
+	TargetBalance string `toml:"targetBalance"`
+}
+
+// HAConfig run several instances on one database, only the one holding the leader lease selects and publishes
+// messages, the others are standby which serve read apis and take over when the lease expires.
+type HAConfig struct {
+	Enable bool `toml:"enable"`
+
+	// ID identifies this instance in the lease, default to hostname and pid.
+	ID string `toml:"id"`
+
+	// LeaseDuration is how long the lease is valid after renewed.
+	LeaseDuration time.Duration `toml:"leaseDuration"`
+
+	// RenewInterval is how often to renew or try to acquire the lease, it should be much smaller than LeaseDuration.
+	RenewInterval time.Duration `toml:"renewInterval"`
+}
+
+// ShardingConfig run several active instances on one database, every address is owned by exactly one instance
+// which selects messages and refreshes message state for it. Addresses are spread over the live instances by
+// rendezvous hashing and rebalanced when an instance joins or disappears. It can't be enabled with HA.
+type ShardingConfig struct {
+	Enable bool `toml:"enable"`
+
+	// ID identifies this instance, default to hostname and pid.
+	ID string `toml:"id"`
+
+	// LeaseDuration is how long the instance and address leases are valid after renewed, addresses of a dead
+	// instance are taken over after it.
+	LeaseDuration time.Duration `toml:"leaseDuration"`
+
+	// RenewInterval is how often to renew leases and rebalance addresses, it should be much smaller than LeaseDuration.
+	RenewInterval time.Duration `toml:"renewInterval"`
 }
 
 type MessageStateConfig struct {
@@ -119,6 +357,33 @@ type MessageStateConfig struct {
 type GatewayConfig struct {
 	Token string   `toml:"token"`
 	Url   []string `toml:"url"`
+
+	// Weights are the weights of gateways by url, default is 1, the gateway with the lowest latency divided by
+	// weight is preferred.
+	Weights map[string]int `toml:"weights"`
+	// FailureThreshold is the number of consecutive failures to mark a gateway unhealthy, the addresses cached on
+	// an unhealthy gateway are evicted and reselected, 0 means never.
+	FailureThreshold int `toml:"failureThreshold"`
+	// ProbeInterval is the interval to probe gateways, the unhealthy gateways recover once probed successfully.
+	ProbeInterval time.Duration `toml:"probeInterval"`
+	// SelectTimeout is the timeout of asking a gateway whether it owns an address, so a hanging gateway doesn't
+	// block selecting, 0 means no timeout.
+	SelectTimeout time.Duration `toml:"selectTimeout"`
+}
+
+const (
+	// WalletGateway signs messages by the wallets connected to venus-gateway
+	WalletGateway = "gateway"
+	// WalletLocal signs messages by the keys in the keystore of repo, which are managed by `wallet` commands
+	WalletLocal = "local"
+)
+
+type WalletConfig struct {
+	// Type is the signer backend, `gateway` or `local`
+	Type string `toml:"type"`
+	// PassphraseFile is the file containing the passphrase which encrypts the keys of the local keystore, it must not
+	// be readable by group or others. It is overridden by env MESSAGER_KEYSTORE_PASSPHRASE.
+	PassphraseFile string `toml:"passphraseFile"`
 }
 
 type RateLimitConfig struct {
@@ -138,7 +403,13 @@ func DefaultConfig() *Config {
 			},
 		},
 		JWT: JWTConfig{
+			Type:    AuthRemote,
 			AuthURL: "http://127.0.0.1:8989",
+			SignerCache: SignerCacheConfig{
+				TTL:         time.Minute,
+				StalePolicy: StaleKeep,
+				MaxStale:    time.Hour,
+			},
 		},
 		Log: LogConfig{
 			Path:  "",
@@ -150,6 +421,10 @@ func DefaultConfig() *Config {
 		Node: NodeConfig{
 			Url:   "/ip4/127.0.0.1/tcp/3453",
 			Token: "",
+
+			EnableFailover:      false,
+			HealthCheckInterval: 10 * time.Second,
+			MaxLag:              3,
 		},
 		MessageService: MessageServiceConfig{
 			WaitingChainHeadStableDuration: DefWaitingChainHeadStableDuration,
@@ -160,25 +435,107 @@ func DefaultConfig() *Config {
 
 			SkipProcessHead: false,
 			SkipPushMessage: false,
+
+			MaxHeadDelayEpoch: DefMaxHeadDelayEpoch,
+			CheckSyncState:    false,
+
+			SignFailureThreshold: 3,
+			SignPauseCooldown:    10 * time.Minute,
 		},
 		Gateway: GatewayConfig{
 			Token: "",
 			Url:   []string{"/ip4/127.0.0.1/tcp/45132"},
+
+			Weights:          map[string]int{},
+			FailureThreshold: 3,
+			ProbeInterval:    30 * time.Second,
+			SelectTimeout:    10 * time.Second,
+		},
+		Wallet: WalletConfig{
+			Type: WalletGateway,
 		},
 		RateLimit: RateLimitConfig{Redis: ""},
 		Trace:     metrics.DefaultTraceConfig(),
 		Metrics:   metrics.DefaultMetricsConfig(),
 		Libp2pNet: &Libp2pNetConfig{
-			ListenAddress:      "/ip4/0.0.0.0/tcp/0",
-			BootstrapAddresses: []string{},
-			MinPeerThreshold:   0,
-			ExpandPeriod:       0 * time.Second,
+			ListenAddress:       "/ip4/0.0.0.0/tcp/0",
+			AnnounceAddresses:   []string{},
+			NoAnnounceAddresses: []string{},
+			BootstrapAddresses:  []string{},
+			MinPeerThreshold:    0,
+			ExpandPeriod:        0 * time.Second,
+			EnableRelay:         false,
+			StaticRelays:        []string{},
+			EnableHolePunching:  false,
+			EnableNATPortMap:    false,
+			ConnMgrLow:          50,
+			ConnMgrHigh:         100,
+			ConnMgrGrace:        20 * time.Second,
+			ProtectedPeers:      []string{},
+			GossipSub: GossipSubConfig{
+				HeartbeatInterval:           100 * time.Millisecond,
+				FloodPublish:                true,
+				D:                           8,
+				Dlo:                         6,
+				Dhi:                         12,
+				DirectPeers:                 []string{},
+				EnablePeerScore:             false,
+				MessageTopicWeight:          0.1,
+				GossipThreshold:             -500,
+				PublishThreshold:            -1000,
+				GraylistThreshold:           -2500,
+				AcceptPXThreshold:           1000,
+				OpportunisticGraftThreshold: 3.5,
+			},
 		},
 		Publisher: &PublisherConfig{
 			Concurrency:        5,
 			CacheReleasePeriod: 0,
 			EnableP2P:          false,
 			EnableMultiNode:    true,
+
+			NodeFailureThreshold: 3,
+			NodeRetryBackoff:     30 * time.Second,
+			NodeProbeInterval:    30 * time.Second,
+			NodeWeights:          map[string]int{},
+			MaxPublishNodes:      0,
+			RejectThreshold:      3,
+			ConfirmPropagation:   false,
+			PropagationTimeout:   time.Minute,
+			Routing: RoutingConfig{
+				NodeTags:      map[string][]string{},
+				AddressGroups: map[string][]string{},
+			},
+		},
+		Approval: &ApprovalConfig{
+			Enable:         false,
+			ValueThreshold: "",
+			Methods:        []string{"ChangeOwnerAddress", "ChangeWorkerAddress", "WithdrawBalance"},
+		},
+		Transfer: &TransferConfig{
+			Enable:          false,
+			MaxValue:        "",
+			AddressMaxValue: map[string]string{},
+			AllowedTo:       []string{},
+		},
+		TopUp: &TopUpConfig{
+			Enable:        false,
+			CheckInterval: time.Minute,
+			MinInterval:   time.Hour,
+			DailyCap:      "",
+			Rules:         []TopUpRule{},
+		},
+		HA: &HAConfig{
+			Enable:        false,
+			ID:            "",
+			LeaseDuration: 30 * time.Second,
+			RenewInterval: 10 * time.Second,
+		},
+		Sharding: &ShardingConfig{
+			Enable:        false,
+			ID:            "",
+			LeaseDuration: 30 * time.Second,
+			RenewInterval: 10 * time.Second,
 		},
 	}
 }
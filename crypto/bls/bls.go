@@ -0,0 +1,98 @@
+// Package bls registers the bls signature of venus crypto by blst. The bls package of venus links filecoin-ffi,
+// which is not built with messager, so import this package instead:
+//
+//	import _ "github.com/filecoin-project/venus-messager/crypto/bls"
+package bls
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/crypto"
+	blst "github.com/supranational/blst/bindings/go"
+
+	vcrypto "github.com/filecoin-project/venus/pkg/crypto"
+)
+
+// DST is the domain separation tag of filecoin bls signatures
+const DST = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_NUL_"
+
+type blsSigner struct{}
+
+func (blsSigner) GenPrivate() ([]byte, error) {
+	return blsSigner{}.GenPrivateFromSeed(rand.Reader)
+}
+
+func (blsSigner) GenPrivateFromSeed(seed io.Reader) ([]byte, error) {
+	var ikm [32]byte
+	if _, err := io.ReadFull(seed, ikm[:]); err != nil {
+		return nil, fmt.Errorf("read seed: %w", err)
+	}
+	// private keys are little-endian in filecoin
+	return blst.KeyGen(ikm[:]).ToLEndian(), nil
+}
+
+func (blsSigner) ToPublic(pk []byte) ([]byte, error) {
+	sk, err := secretKey(pk)
+	if err != nil {
+		return nil, err
+	}
+	return new(blst.P1Affine).From(sk).Compress(), nil
+}
+
+func (blsSigner) Sign(pk []byte, msg []byte) ([]byte, error) {
+	sk, err := secretKey(pk)
+	if err != nil {
+		return nil, err
+	}
+	return new(blst.P2Affine).Sign(sk, msg, []byte(DST)).Compress(), nil
+}
+
+func (blsSigner) Verify(sig []byte, a address.Address, msg []byte) error {
+	if a.Protocol() != address.BLS {
+		return fmt.Errorf("%s is not a bls address", a)
+	}
+	pub := new(blst.P1Affine).Uncompress(a.Payload())
+	if pub == nil {
+		return fmt.Errorf("invalid bls public key of %s", a)
+	}
+	s := new(blst.P2Affine).Uncompress(sig)
+	if s == nil || !s.Verify(true, pub, true, msg, []byte(DST)) {
+		return fmt.Errorf("bls signature failed to verify")
+	}
+	return nil
+}
+
+func (blsSigner) VerifyAggregate(pubKeys, msgs [][]byte, signature []byte) bool {
+	s := new(blst.P2Affine).Uncompress(signature)
+	if s == nil || len(pubKeys) != len(msgs) {
+		return false
+	}
+	pks := make([]*blst.P1Affine, 0, len(pubKeys))
+	for _, pubKey := range pubKeys {
+		pk := new(blst.P1Affine).Uncompress(pubKey)
+		if pk == nil {
+			return false
+		}
+		pks = append(pks, pk)
+	}
+	blstMsgs := make([]blst.Message, 0, len(msgs))
+	for _, msg := range msgs {
+		blstMsgs = append(blstMsgs, msg)
+	}
+	return s.AggregateVerify(true, pks, true, blstMsgs, []byte(DST))
+}
+
+func secretKey(pk []byte) (*blst.SecretKey, error) {
+	sk := new(blst.SecretKey).FromLEndian(pk)
+	if sk == nil || !sk.Valid() {
+		return nil, fmt.Errorf("invalid bls private key")
+	}
+	return sk, nil
+}
+
+func init() {
+	vcrypto.RegisterSignature(crypto.SigTypeBLS, blsSigner{})
+}
@@ -14,6 +14,14 @@ const (
 	TipsetFile = "tipset.json"
 	SqliteFile = "message.db"
 	TokenFile  = "token"
+	// Libp2pKeyFile stores the identity of libp2p host, which keeps the peer id unchanged after restarting
+	Libp2pKeyFile = "libp2p.key"
+	// PeerstoreFile stores the peers connected, which are reconnected at startup
+	PeerstoreFile = "peerstore.json"
+	// BannedPeersFile stores the peers banned by `swarm ban`
+	BannedPeersFile = "banned_peers.json"
+	// KeystoreDir stores the encrypted keys of the local wallet
+	KeystoreDir = "keystore"
 )
 
 type FSRepo interface {
@@ -24,6 +32,11 @@ type FSRepo interface {
 	SqliteFile() string
 	GetToken() ([]byte, error)
 	SaveToken([]byte) error
+	PeerstoreFile() string
+	BannedPeersFile() string
+	KeystoreDir() string
+	GetLibp2pKey() ([]byte, error)
+	SaveLibp2pKey([]byte) error
 }
 
 type fsRepo struct {
@@ -38,6 +51,10 @@ func NewFSRepo(repoPath string) (FSRepo, error) {
 	if err != nil {
 		return nil, err
 	}
+	// HA keeps one active instance while sharding runs several, they can't work together
+	if cfg.HA != nil && cfg.Sharding != nil && cfg.HA.Enable && cfg.Sharding.Enable {
+		return nil, fmt.Errorf("ha and sharding can not be enabled at the same time")
+	}
 	if cfg.MessageService.DefaultTimeout <= 0 {
 		cfg.MessageService.DefaultTimeout = config.DefaultTimeout
 	}
@@ -80,6 +97,18 @@ func (r *fsRepo) SqliteFile() string {
 	return filepath.Join(r.path, SqliteFile)
 }
 
+func (r *fsRepo) PeerstoreFile() string {
+	return filepath.Join(r.path, PeerstoreFile)
+}
+
+func (r *fsRepo) BannedPeersFile() string {
+	return filepath.Join(r.path, BannedPeersFile)
+}
+
+func (r *fsRepo) KeystoreDir() string {
+	return filepath.Join(r.path, KeystoreDir)
+}
+
 func (r *fsRepo) ReplaceConfig(cfg *config.Config) error {
 	if err := utils.WriteConfig(filepath.Join(r.path, ConfigFile), cfg); err != nil {
 		return err
@@ -100,3 +129,15 @@ func (r *fsRepo) SaveToken(token []byte) error {
 func (r *fsRepo) GetToken() ([]byte, error) {
 	return os.ReadFile(filepath.Join(r.path, TokenFile))
 }
+
+func (r *fsRepo) SaveLibp2pKey(key []byte) error {
+	err := os.WriteFile(filepath.Join(r.path, Libp2pKeyFile), key, 0o600)
+	if err != nil {
+		return fmt.Errorf("write libp2p key to key file failed: %v", err)
+	}
+	return nil
+}
+
+func (r *fsRepo) GetLibp2pKey() ([]byte, error) {
+	return os.ReadFile(filepath.Join(r.path, Libp2pKeyFile))
+}
@@ -1,6 +1,7 @@
 package filestore
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -30,6 +31,16 @@ func TestNewFSRepo(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, token, token2)
 
+	assert.Equal(t, filepath.Join(path, PeerstoreFile), fsRepo.PeerstoreFile())
+	assert.Equal(t, filepath.Join(path, BannedPeersFile), fsRepo.BannedPeersFile())
+	_, err = fsRepo.GetLibp2pKey()
+	assert.True(t, os.IsNotExist(err))
+	key := []byte("test-key")
+	assert.NoError(t, fsRepo.SaveLibp2pKey(key))
+	key2, err := fsRepo.GetLibp2pKey()
+	assert.NoError(t, err)
+	assert.Equal(t, key, key2)
+
 	t.Run("use default value when timeout is zero", func(t *testing.T) {
 		cfgCopy := *config.DefaultConfig()
 		cfgCopy.MessageService.DefaultTimeout = 0
@@ -44,6 +55,17 @@ func TestNewFSRepo(t *testing.T) {
 		fsRepo.Config().MessageService.SignMessageTimeout = config.SignMessageTimeout
 		fsRepo.Config().MessageService.EstimateMessageTimeout = config.EstimateMessageTimeout
 	})
+
+	t.Run("ha and sharding are both enabled", func(t *testing.T) {
+		cfg := config.DefaultConfig()
+		cfg.HA.Enable = true
+		cfg.Sharding.Enable = true
+
+		repoPath := t.TempDir()
+		assert.Nil(t, utils.WriteConfig(filepath.Join(repoPath, ConfigFile), cfg))
+		_, err := NewFSRepo(repoPath)
+		assert.Error(t, err)
+	})
 }
 
 func TestInitFSRepo(t *testing.T) {
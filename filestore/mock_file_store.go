@@ -2,6 +2,7 @@ package filestore
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 
 	"github.com/filecoin-project/venus-messager/config"
@@ -11,6 +12,7 @@ type mockFileStore struct {
 	path  string
 	cfg   *config.Config
 	token []byte
+	key   []byte
 }
 
 func NewMockFileStore(path string) FSRepo {
@@ -38,6 +40,18 @@ func (mfs *mockFileStore) TipsetFile() string {
 	return filepath.Join(mfs.Path(), TipsetFile)
 }
 
+func (mfs *mockFileStore) PeerstoreFile() string {
+	return filepath.Join(mfs.Path(), PeerstoreFile)
+}
+
+func (mfs *mockFileStore) BannedPeersFile() string {
+	return filepath.Join(mfs.Path(), BannedPeersFile)
+}
+
+func (mfs *mockFileStore) KeystoreDir() string {
+	return filepath.Join(mfs.Path(), KeystoreDir)
+}
+
 func (mfs *mockFileStore) SqliteFile() string {
 	// SQLite In-Memory
 	return ":memory:"
@@ -55,4 +69,16 @@ func (mfs *mockFileStore) SaveToken(token []byte) error {
 	return nil
 }
 
+func (mfs *mockFileStore) GetLibp2pKey() ([]byte, error) {
+	if mfs.key != nil {
+		return mfs.key, nil
+	}
+	return nil, os.ErrNotExist
+}
+
+func (mfs *mockFileStore) SaveLibp2pKey(key []byte) error {
+	mfs.key = key
+	return nil
+}
+
 var _ FSRepo = (*mockFileStore)(nil)
@@ -2,8 +2,11 @@ package gateway
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-jsonrpc"
@@ -13,26 +16,81 @@ import (
 	venusTypes "github.com/filecoin-project/venus/venus-shared/types"
 	gtypes "github.com/filecoin-project/venus/venus-shared/types/gateway"
 	logging "github.com/ipfs/go-log/v2"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
 
 	"github.com/filecoin-project/venus-messager/config"
+	"github.com/filecoin-project/venus-messager/metrics"
+	"github.com/filecoin-project/venus-messager/models/mtypes"
 )
 
 var log = logging.Logger("wallet-proxy")
 
+// ErrWalletNotFound means no gateway has a wallet owning the address
+var ErrWalletNotFound = errors.New("can't find a wallet")
+
 type cacheKey string
 
 func newCacheKey(addr address.Address) cacheKey {
 	return cacheKey("walletClientCache:" + addr.String())
 }
 
+// IGatewayStatus lists the health of the wallet gateways
+type IGatewayStatus interface {
+	ListGatewayStatus(ctx context.Context) ([]*mtypes.GatewayStatus, error)
+}
+
+// NoGateway is the IGatewayStatus of the signer backend without gateway
+type NoGateway struct{}
+
+func (NoGateway) ListGatewayStatus(context.Context) ([]*mtypes.GatewayStatus, error) {
+	return []*mtypes.GatewayStatus{}, nil
+}
+
+var (
+	_ IGatewayStatus = (*WalletProxy)(nil)
+	_ IGatewayStatus = NoGateway{}
+)
+
+// gatewayClient is the gateway api used by WalletProxy, Version is used to probe gateway
+type gatewayClient interface {
+	gatewayAPI.IWalletClient
+	Version(ctx context.Context) (venusTypes.Version, error)
+}
+
+type poolClient struct {
+	url    string
+	client gatewayClient
+	health *gatewayHealth
+}
+
+// WalletProxy signs by a pool of gateways, the gateway owning an address is selected by the health, latency and
+// weight of gateways, and cached until it fails `FailureThreshold` times in a row.
 type WalletProxy struct {
-	clients map[string]gatewayAPI.IWalletClient
+	cfg     *config.GatewayConfig
+	clients map[string]*poolClient
 
 	mutx                sync.RWMutex
-	avaliabeClientCache map[cacheKey]gatewayAPI.IWalletClient
+	avaliabeClientCache map[cacheKey]*poolClient
 }
 
-func (w *WalletProxy) putCache(addr address.Address, client gatewayAPI.IWalletClient) {
+func newWalletProxy(cfg *config.GatewayConfig, clients map[string]gatewayClient) *WalletProxy {
+	proxy := &WalletProxy{
+		cfg:                 cfg,
+		clients:             make(map[string]*poolClient, len(clients)),
+		avaliabeClientCache: make(map[cacheKey]*poolClient),
+	}
+	for url, c := range clients {
+		proxy.clients[url] = &poolClient{
+			url:    url,
+			client: c,
+			health: newGatewayHealth(url, cfg.Weights[url], cfg.FailureThreshold),
+		}
+	}
+	return proxy
+}
+
+func (w *WalletProxy) putCache(addr address.Address, client *poolClient) {
 	w.mutx.Lock()
 	defer w.mutx.Unlock()
 	w.avaliabeClientCache[newCacheKey(addr)] = client
@@ -49,44 +107,113 @@ func (w *WalletProxy) delCache(addr address.Address) bool {
 	return exist
 }
 
-func (w *WalletProxy) getCachedClient(addr address.Address) gatewayAPI.IWalletClient {
+// getCachedClient returns the cached client of addr, nil if it is not cached or unhealthy
+func (w *WalletProxy) getCachedClient(addr address.Address) *poolClient {
 	w.mutx.RLock()
 	defer w.mutx.RUnlock()
 
 	key := newCacheKey(addr)
-	return w.avaliabeClientCache[key]
+	c := w.avaliabeClientCache[key]
+	if c == nil || !c.health.healthy() {
+		return nil
+	}
+	return c
 }
 
-// todo: think about 'fastSelectAvaGatewayClient' was called parallelly
-func (w *WalletProxy) fastSelectAvaGatewayClient(ctx context.Context, addr address.Address, accounts []string) (gatewayAPI.IWalletClient, error) {
-	var g = &sync.WaitGroup{}
-	var ch = make(chan gatewayAPI.IWalletClient, 1)
-	for url, c := range w.clients {
-		g.Add(1)
-		go func(url string, c gatewayAPI.IWalletClient) {
-			has, err := c.WalletHas(ctx, addr, accounts)
-			if err != nil {
-				log.Errorf("fastSelectAvaClient, call %s:'WalletHas' failed:%s", url, err)
-			}
-			if has {
-				ch <- c
-			}
-			g.Done()
-		}(url, c)
+// evict removes the addresses cached on the gateway
+func (w *WalletProxy) evict(url string) {
+	w.mutx.Lock()
+	defer w.mutx.Unlock()
+	for key, c := range w.avaliabeClientCache {
+		if c.url == url {
+			delete(w.avaliabeClientCache, key)
+		}
 	}
+}
 
-	go func() {
-		g.Wait()
-		close(ch)
-	}()
+// record updates the health and metrics of the gateway by the result of a request, ctx is the context of caller, the
+// request canceled by caller is not a failure of gateway.
+func (w *WalletProxy) record(ctx context.Context, c *poolClient, start time.Time, err error) {
+	if err != nil && errors.Is(ctx.Err(), context.Canceled) {
+		return
+	}
+	latency := time.Since(start)
+	ctx, _ = tag.New(ctx, tag.Upsert(metrics.GatewayURL, c.url))
+	if err != nil {
+		stats.Record(ctx, metrics.GatewayRequestErrors.M(1))
+		if c.health.failure(err) {
+			w.evict(c.url)
+		}
+	} else {
+		stats.Record(ctx, metrics.GatewayRequestLatency.M(float64(latency.Milliseconds())))
+		c.health.success(latency)
+	}
+	var healthy int64
+	if c.health.healthy() {
+		healthy = 1
+	}
+	stats.Record(ctx, metrics.GatewayHealthy.M(healthy))
+}
+
+// rankedClients returns the healthy gateways from the best to the worst, all gateways are returned if none is healthy
+func (w *WalletProxy) rankedClients() []*poolClient {
+	clients := make([]*poolClient, 0, len(w.clients))
+	for _, c := range w.clients {
+		if c.health.healthy() {
+			clients = append(clients, c)
+		}
+	}
+	if len(clients) == 0 {
+		for _, c := range w.clients {
+			clients = append(clients, c)
+		}
+	}
+	sort.Slice(clients, func(i, j int) bool {
+		si, sj := clients[i].health.score(), clients[j].health.score()
+		if si != sj {
+			return si < sj
+		}
+		return clients[i].url < clients[j].url
+	})
+	return clients
+}
 
-	c, isok := <-ch
-	if !isok || c == nil {
-		return nil, fmt.Errorf("can't find a wallet, address: %s", addr.String())
+func (w *WalletProxy) walletHas(ctx context.Context, c *poolClient, addr address.Address, accounts []string) bool {
+	reqCtx := ctx
+	if w.cfg.SelectTimeout > 0 {
+		var cancel context.CancelFunc
+		reqCtx, cancel = context.WithTimeout(ctx, w.cfg.SelectTimeout)
+		defer cancel()
 	}
 
-	w.putCache(addr, c)
-	return c, nil
+	start := time.Now()
+	has, err := c.client.WalletHas(reqCtx, addr, accounts)
+	w.record(ctx, c, start, err)
+	if err != nil {
+		log.Errorf("selectGatewayClient, call %s:'WalletHas' failed:%s", c.url, err)
+	}
+	return has
+}
+
+// selectGatewayClient asks all gateways whether they own addr in parallel, and selects the best ranked one which owns
+// addr, the better ranked gateways are waited even if a worse one responds first.
+func (w *WalletProxy) selectGatewayClient(ctx context.Context, addr address.Address, accounts []string) (*poolClient, error) {
+	clients := w.rankedClients()
+	results := make([]chan bool, len(clients))
+	for i, c := range clients {
+		results[i] = make(chan bool, 1)
+		go func(c *poolClient, ch chan<- bool) {
+			ch <- w.walletHas(ctx, c, addr, accounts)
+		}(c, results[i])
+	}
+
+	for i, ch := range results {
+		if <-ch {
+			w.putCache(addr, clients[i])
+			return clients[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w, address: %s", ErrWalletNotFound, addr.String())
 }
 
 func (w *WalletProxy) WalletHas(ctx context.Context, addr address.Address, accounts []string) (bool, error) {
@@ -94,10 +221,17 @@ func (w *WalletProxy) WalletHas(ctx context.Context, addr address.Address, accou
 	if c != nil {
 		return true, nil
 	}
-	c, err := w.fastSelectAvaGatewayClient(ctx, addr, accounts)
+	c, err := w.selectGatewayClient(ctx, addr, accounts)
 	return c != nil, err
 }
 
+func (w *WalletProxy) walletSign(ctx context.Context, c *poolClient, addr address.Address, accounts []string, toSign []byte, meta venusTypes.MsgMeta) (*crypto.Signature, error) {
+	start := time.Now()
+	s, err := c.client.WalletSign(ctx, addr, accounts, toSign, meta)
+	w.record(ctx, c, start, err)
+	return s, err
+}
+
 func (w *WalletProxy) WalletSign(ctx context.Context, addr address.Address, accounts []string, toSign []byte, meta venusTypes.MsgMeta) (*crypto.Signature, error) {
 	var err error
 	var useCachedClient bool
@@ -105,7 +239,7 @@ func (w *WalletProxy) WalletSign(ctx context.Context, addr address.Address, acco
 	c := w.getCachedClient(addr)
 
 	if c == nil {
-		if c, err = w.fastSelectAvaGatewayClient(ctx, addr, accounts); err != nil {
+		if c, err = w.selectGatewayClient(ctx, addr, accounts); err != nil {
 			return nil, err
 		}
 	} else {
@@ -113,39 +247,170 @@ func (w *WalletProxy) WalletSign(ctx context.Context, addr address.Address, acco
 	}
 
 	var s *crypto.Signature
-	if s, err = c.WalletSign(ctx, addr, accounts, toSign, meta); err != nil {
+	if s, err = w.walletSign(ctx, c, addr, accounts, toSign, meta); err != nil {
 		if useCachedClient {
 			log.Warnf("sign with cached client failed:%s, will re-SelectAvaliableClient, and retry",
 				err.Error())
 
 			w.delCache(addr)
 
-			if c, err = w.fastSelectAvaGatewayClient(ctx, addr, accounts); err != nil {
+			if c, err = w.selectGatewayClient(ctx, addr, accounts); err != nil {
 				return nil, err
 			}
-			s, err = c.WalletSign(ctx, addr, accounts, toSign, meta)
+			s, err = w.walletSign(ctx, c, addr, accounts, toSign, meta)
 		}
 	}
 
 	return s, err
 }
 
-func (w *WalletProxy) ListWalletInfo(context.Context) ([]*gtypes.WalletDetail, error) {
-	panic("implement me")
+// probe requests the version of all gateways to update their health
+func (w *WalletProxy) probe(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, c := range w.clients {
+		wg.Add(1)
+		go func(c *poolClient) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, w.cfg.ProbeInterval)
+			defer cancel()
+			start := time.Now()
+			_, err := c.client.Version(ctx)
+			if err != nil {
+				log.Debugf("probe gateway %s failed: %v", c.url, err)
+			}
+			w.record(ctx, c, start, err)
+		}(c)
+	}
+	wg.Wait()
+}
+
+func (w *WalletProxy) probeLoop(ctx context.Context) {
+	if w.cfg.ProbeInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(w.cfg.ProbeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.probe(ctx)
+		}
+	}
+}
+
+// ListGatewayStatus returns the health of gateways sorted by url
+func (w *WalletProxy) ListGatewayStatus(ctx context.Context) ([]*mtypes.GatewayStatus, error) {
+	cached := make(map[string]int)
+	w.mutx.RLock()
+	for _, c := range w.avaliabeClientCache {
+		cached[c.url]++
+	}
+	w.mutx.RUnlock()
+
+	status := make([]*mtypes.GatewayStatus, 0, len(w.clients))
+	for url, c := range w.clients {
+		info := c.health.info()
+		info.CachedAddresses = cached[url]
+		status = append(status, info)
+	}
+	sort.Slice(status, func(i, j int) bool {
+		return status[i].Url < status[j].Url
+	})
+	return status, nil
+}
+
+// ListWalletInfo lists the wallets connected to all gateways, the wallet connected to several gateways is merged by
+// account. It fails only when none of the gateways responds.
+func (w *WalletProxy) ListWalletInfo(ctx context.Context) ([]*gtypes.WalletDetail, error) {
+	type result struct {
+		url     string
+		details []*gtypes.WalletDetail
+		err     error
+	}
+	results := make(chan result, len(w.clients))
+	for _, c := range w.clients {
+		go func(c *poolClient) {
+			start := time.Now()
+			details, err := c.client.ListWalletInfo(ctx)
+			w.record(ctx, c, start, err)
+			results <- result{url: c.url, details: details, err: err}
+		}(c)
+	}
+
+	merged := make(map[string]*gtypes.WalletDetail)
+	var lastErr error
+	succeed := false
+	for range w.clients {
+		res := <-results
+		if res.err != nil {
+			log.Warnf("list wallet info from %s failed: %v", res.url, res.err)
+			lastErr = res.err
+			continue
+		}
+		succeed = true
+		for _, detail := range res.details {
+			mergeWalletDetail(merged, detail)
+		}
+	}
+	if !succeed && lastErr != nil {
+		return nil, fmt.Errorf("list wallet info from all gateways failed: %w", lastErr)
+	}
+
+	details := make([]*gtypes.WalletDetail, 0, len(merged))
+	for _, detail := range merged {
+		details = append(details, detail)
+	}
+	sort.Slice(details, func(i, j int) bool {
+		return details[i].Account < details[j].Account
+	})
+	return details, nil
+}
+
+// ListWalletInfoByWallet returns the wallet merged from all gateways
+func (w *WalletProxy) ListWalletInfoByWallet(ctx context.Context, wallet string) (*gtypes.WalletDetail, error) {
+	details, err := w.ListWalletInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, detail := range details {
+		if detail.Account == wallet {
+			return detail, nil
+		}
+	}
+	return nil, fmt.Errorf("wallet %s not found", wallet)
 }
 
-func (w *WalletProxy) ListWalletInfoByWallet(context.Context, string) (*gtypes.WalletDetail, error) {
-	panic("implement me")
+// mergeWalletDetail merges detail into the wallet of the same account, the support accounts are deduplicated
+func mergeWalletDetail(merged map[string]*gtypes.WalletDetail, detail *gtypes.WalletDetail) {
+	if detail == nil {
+		return
+	}
+	dst, ok := merged[detail.Account]
+	if !ok {
+		dst = &gtypes.WalletDetail{Account: detail.Account}
+		merged[detail.Account] = dst
+	}
+	for _, account := range detail.SupportAccounts {
+		exist := false
+		for _, a := range dst.SupportAccounts {
+			if a == account {
+				exist = true
+				break
+			}
+		}
+		if !exist {
+			dst.SupportAccounts = append(dst.SupportAccounts, account)
+		}
+	}
+	dst.ConnectStates = append(dst.ConnectStates, detail.ConnectStates...)
 }
 
 func NewWalletClient(ctx context.Context,
 	cfg *config.GatewayConfig,
 ) (*WalletProxy, jsonrpc.ClientCloser, error) {
-	var proxy = &WalletProxy{
-		clients:             make(map[string]gatewayAPI.IWalletClient),
-		avaliabeClientCache: make(map[cacheKey]gatewayAPI.IWalletClient),
-	}
-
+	clients := make(map[string]gatewayClient, len(cfg.Url))
 	var closers []jsonrpc.ClientCloser
 	for _, url := range cfg.Url {
 		c, cls, err := gatewayAPI.DialIGatewayRPC(ctx, url, cfg.Token, nil)
@@ -154,14 +419,17 @@ func NewWalletClient(ctx context.Context,
 			return nil, nil, fmt.Errorf("create geteway client with url:%s failed: %w", url, err)
 		}
 
-		proxy.clients[url] = c
+		clients[url] = c
 		closers = append(closers, cls)
 	}
 
-	if len(proxy.clients) == 0 {
+	if len(clients) == 0 {
 		return nil, nil, fmt.Errorf("can't create any gateway client, please check 'GatewayConfig'")
 	}
 
+	proxy := newWalletProxy(cfg, clients)
+	go proxy.probeLoop(ctx)
+
 	totalCloser := func() {
 		for _, closer := range closers {
 			closer()
@@ -0,0 +1,268 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/venus/venus-shared/types"
+	gtypes "github.com/filecoin-project/venus/venus-shared/types/gateway"
+
+	"github.com/filecoin-project/venus-messager/config"
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/testhelper"
+)
+
+// faultyGateway wraps MockWalletProxy to inject latency and errors
+type faultyGateway struct {
+	*MockWalletProxy
+
+	lk    sync.Mutex
+	delay time.Duration
+	err   error
+}
+
+func newFaultyGateway(account string, addrs []address.Address, delay time.Duration) (*faultyGateway, error) {
+	mock := NewMockWalletProxy()
+	if err := mock.AddAddress(account, addrs); err != nil {
+		return nil, err
+	}
+	return &faultyGateway{MockWalletProxy: mock, delay: delay}, nil
+}
+
+func (f *faultyGateway) setErr(err error) {
+	f.lk.Lock()
+	defer f.lk.Unlock()
+	f.err = err
+}
+
+func (f *faultyGateway) wait(ctx context.Context) error {
+	f.lk.Lock()
+	delay, err := f.delay, f.err
+	f.lk.Unlock()
+	select {
+	case <-time.After(delay):
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (f *faultyGateway) WalletHas(ctx context.Context, addr address.Address, accounts []string) (bool, error) {
+	if err := f.wait(ctx); err != nil {
+		return false, err
+	}
+	return f.MockWalletProxy.WalletHas(ctx, addr, accounts)
+}
+
+func (f *faultyGateway) WalletSign(ctx context.Context, addr address.Address, accounts []string, toSign []byte, meta types.MsgMeta) (*crypto.Signature, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	return f.MockWalletProxy.WalletSign(ctx, addr, accounts, toSign, meta)
+}
+
+func (f *faultyGateway) ListWalletInfo(ctx context.Context) ([]*gtypes.WalletDetail, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	return f.MockWalletProxy.ListWalletInfo(ctx)
+}
+
+func (f *faultyGateway) Version(ctx context.Context) (types.Version, error) {
+	if err := f.wait(ctx); err != nil {
+		return types.Version{}, err
+	}
+	return f.MockWalletProxy.Version(ctx)
+}
+
+func statusByUrl(t *testing.T, proxy *WalletProxy) map[string]*mtypes.GatewayStatus {
+	status, err := proxy.ListGatewayStatus(context.Background())
+	require.NoError(t, err)
+	res := make(map[string]*mtypes.GatewayStatus, len(status))
+	for _, s := range status {
+		res[s.Url] = s
+	}
+	return res
+}
+
+func TestSelectGateway(t *testing.T) {
+	ctx := context.Background()
+	account := "test"
+	addrs := testhelper.ResolveAddrs(t, testhelper.RandAddresses(t, 2))[:2]
+
+	slow, err := newFaultyGateway(account, addrs, 30*time.Millisecond)
+	require.NoError(t, err)
+	fast, err := newFaultyGateway(account, addrs, 5*time.Millisecond)
+	require.NoError(t, err)
+
+	newProxy := func(weights map[string]int) *WalletProxy {
+		cfg := config.DefaultConfig().Gateway
+		cfg.Weights = weights
+		return newWalletProxy(&cfg, map[string]gatewayClient{"a": slow, "b": fast})
+	}
+
+	t.Run("prefer lowest latency", func(t *testing.T) {
+		proxy := newProxy(nil)
+		// no latency is recorded, select by url
+		has, err := proxy.WalletHas(ctx, addrs[0], []string{account})
+		require.NoError(t, err)
+		assert.True(t, has)
+		assert.Equal(t, 1, statusByUrl(t, proxy)["a"].CachedAddresses)
+
+		has, err = proxy.WalletHas(ctx, addrs[1], []string{account})
+		require.NoError(t, err)
+		assert.True(t, has)
+		status := statusByUrl(t, proxy)
+		assert.Equal(t, 1, status["a"].CachedAddresses)
+		assert.Equal(t, 1, status["b"].CachedAddresses)
+		assert.Less(t, status["b"].Latency, status["a"].Latency)
+	})
+
+	t.Run("weights", func(t *testing.T) {
+		proxy := newProxy(map[string]int{"a": 100})
+		_, err := proxy.WalletHas(ctx, addrs[0], []string{account})
+		require.NoError(t, err)
+		_, err = proxy.WalletHas(ctx, addrs[1], []string{account})
+		require.NoError(t, err)
+
+		status := statusByUrl(t, proxy)
+		assert.Equal(t, 100, status["a"].Weight)
+		assert.Equal(t, 1, status["b"].Weight)
+		assert.Equal(t, 2, status["a"].CachedAddresses)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		proxy := newProxy(nil)
+		has, err := proxy.WalletHas(ctx, testhelper.RandAddresses(t, 1)[0], []string{account})
+		assert.Error(t, err)
+		assert.False(t, has)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		hanging, err := newFaultyGateway(account, addrs, time.Hour)
+		require.NoError(t, err)
+		cfg := config.DefaultConfig().Gateway
+		cfg.SelectTimeout = 50 * time.Millisecond
+		proxy := newWalletProxy(&cfg, map[string]gatewayClient{"a": hanging, "b": fast})
+
+		has, err := proxy.WalletHas(ctx, addrs[0], []string{account})
+		require.NoError(t, err)
+		assert.True(t, has)
+		status := statusByUrl(t, proxy)
+		assert.Equal(t, 1, status["a"].ConsecutiveFailures)
+		assert.Equal(t, 1, status["b"].CachedAddresses)
+	})
+
+	t.Run("canceled by caller", func(t *testing.T) {
+		proxy := newProxy(nil)
+		cctx, cancel := context.WithCancel(ctx)
+		cancel()
+		has, err := proxy.WalletHas(cctx, addrs[0], []string{account})
+		assert.Error(t, err)
+		assert.False(t, has)
+		for _, status := range statusByUrl(t, proxy) {
+			assert.Equal(t, 0, status.ConsecutiveFailures)
+			assert.Equal(t, int64(0), status.Errors)
+		}
+	})
+}
+
+func TestGatewayEviction(t *testing.T) {
+	ctx := context.Background()
+	account := "test"
+	addrs := testhelper.ResolveAddrs(t, testhelper.RandAddresses(t, 2))[:2]
+
+	gwA, err := newFaultyGateway(account, addrs, 0)
+	require.NoError(t, err)
+	gwB, err := newFaultyGateway(account, addrs, 5*time.Millisecond)
+	require.NoError(t, err)
+
+	cfg := config.DefaultConfig().Gateway
+	cfg.FailureThreshold = 3
+	proxy := newWalletProxy(&cfg, map[string]gatewayClient{"a": gwA, "b": gwB})
+	// measure the latency of gateways, a gateway without latency is ranked first
+	proxy.probe(ctx)
+
+	for _, addr := range addrs {
+		_, err := proxy.WalletSign(ctx, addr, []string{account}, []byte("msg"), types.MsgMeta{})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, statusByUrl(t, proxy)["a"].CachedAddresses)
+
+	// signing and re-selecting both fail on gateway a, the cache of the other address is kept
+	gwA.setErr(errors.New("mock error"))
+	_, err = proxy.WalletSign(ctx, addrs[0], []string{account}, []byte("msg"), types.MsgMeta{})
+	require.NoError(t, err)
+	status := statusByUrl(t, proxy)
+	assert.True(t, status["a"].Healthy)
+	assert.Equal(t, 2, status["a"].ConsecutiveFailures)
+	assert.Equal(t, "mock error", status["a"].LastError)
+	assert.Equal(t, 1, status["a"].CachedAddresses)
+
+	// gateway a turns unhealthy and all addresses cached on it are evicted
+	proxy.probe(ctx)
+	status = statusByUrl(t, proxy)
+	assert.False(t, status["a"].Healthy)
+	assert.Equal(t, 0, status["a"].CachedAddresses)
+
+	_, err = proxy.WalletSign(ctx, addrs[1], []string{account}, []byte("msg"), types.MsgMeta{})
+	require.NoError(t, err)
+	status = statusByUrl(t, proxy)
+	assert.Equal(t, 2, status["b"].CachedAddresses)
+	assert.Equal(t, int64(3), status["a"].Errors)
+
+	// gateway a recovers after a successful probe
+	gwA.setErr(nil)
+	proxy.probe(ctx)
+	status = statusByUrl(t, proxy)
+	assert.True(t, status["a"].Healthy)
+	assert.Equal(t, 0, status["a"].ConsecutiveFailures)
+	assert.Empty(t, status["a"].LastError)
+}
+
+func TestListWalletInfo(t *testing.T) {
+	ctx := context.Background()
+	addrs := testhelper.ResolveAddrs(t, testhelper.RandAddresses(t, 4))
+
+	gwA, err := newFaultyGateway("acc1", addrs[:2], 0)
+	require.NoError(t, err)
+	gwB, err := newFaultyGateway("acc1", addrs[2:3], 0)
+	require.NoError(t, err)
+	require.NoError(t, gwB.AddAddress("acc2", addrs[3:]))
+
+	cfg := config.DefaultConfig().Gateway
+	proxy := newWalletProxy(&cfg, map[string]gatewayClient{"a": gwA, "b": gwB})
+
+	details, err := proxy.ListWalletInfo(ctx)
+	require.NoError(t, err)
+	require.Len(t, details, 2)
+	assert.Equal(t, "acc1", details[0].Account)
+	assert.Equal(t, []string{"acc1"}, details[0].SupportAccounts)
+	assert.Len(t, details[0].ConnectStates, 2)
+	assert.Equal(t, "acc2", details[1].Account)
+
+	detail, err := proxy.ListWalletInfoByWallet(ctx, "acc2")
+	require.NoError(t, err)
+	assert.Equal(t, addrs[3:], detail.ConnectStates[0].Addrs)
+	_, err = proxy.ListWalletInfoByWallet(ctx, "acc3")
+	assert.Error(t, err)
+
+	// the wallets of the failed gateway are skipped
+	gwB.setErr(errors.New("mock error"))
+	details, err = proxy.ListWalletInfo(ctx)
+	require.NoError(t, err)
+	require.Len(t, details, 1)
+	assert.Len(t, details[0].ConnectStates, 1)
+
+	gwA.setErr(errors.New("mock error"))
+	_, err = proxy.ListWalletInfo(ctx)
+	assert.Error(t, err)
+}
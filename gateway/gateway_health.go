@@ -0,0 +1,104 @@
+package gateway
+
+import (
+	"sync"
+	"time"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+)
+
+// latencyAlpha is the weight of the latest latency in the moving average
+const latencyAlpha = 0.3
+
+// gatewayHealth records the results of requests to and probes of a gateway, the gateway is unhealthy after
+// `threshold` consecutive failures until it succeeds again.
+type gatewayHealth struct {
+	lk        sync.Mutex
+	url       string
+	weight    int
+	threshold int
+
+	lastSuccess time.Time
+	failures    int
+	lastErr     string
+	latency     time.Duration
+	requests    int64
+	errors      int64
+}
+
+func newGatewayHealth(url string, weight, threshold int) *gatewayHealth {
+	if weight <= 0 {
+		weight = 1
+	}
+	return &gatewayHealth{
+		url:       url,
+		weight:    weight,
+		threshold: threshold,
+	}
+}
+
+func (h *gatewayHealth) healthyLocked() bool {
+	return h.threshold <= 0 || h.failures < h.threshold
+}
+
+func (h *gatewayHealth) healthy() bool {
+	h.lk.Lock()
+	defer h.lk.Unlock()
+	return h.healthyLocked()
+}
+
+func (h *gatewayHealth) success(latency time.Duration) {
+	h.lk.Lock()
+	defer h.lk.Unlock()
+	if !h.healthyLocked() {
+		log.Infof("gateway %s recovered", h.url)
+	}
+	h.requests++
+	h.lastSuccess = time.Now()
+	h.failures = 0
+	h.lastErr = ""
+	if h.latency == 0 {
+		h.latency = latency
+	} else {
+		h.latency = time.Duration(latencyAlpha*float64(latency) + (1-latencyAlpha)*float64(h.latency))
+	}
+}
+
+// failure returns true when the gateway turns unhealthy by this failure
+func (h *gatewayHealth) failure(err error) bool {
+	h.lk.Lock()
+	defer h.lk.Unlock()
+	wasHealthy := h.healthyLocked()
+	h.requests++
+	h.errors++
+	h.failures++
+	h.lastErr = err.Error()
+	if wasHealthy && !h.healthyLocked() {
+		log.Warnf("gateway %s is unhealthy after %d consecutive failures: %v", h.url, h.failures, err)
+		return true
+	}
+	return false
+}
+
+// score is used to rank gateways, the lower the better
+func (h *gatewayHealth) score() float64 {
+	h.lk.Lock()
+	defer h.lk.Unlock()
+	return float64(h.latency) / float64(h.weight)
+}
+
+func (h *gatewayHealth) info() *mtypes.GatewayStatus {
+	h.lk.Lock()
+	defer h.lk.Unlock()
+	return &mtypes.GatewayStatus{
+		Url:                 h.url,
+		Weight:              h.weight,
+		Healthy:             h.healthyLocked(),
+		Latency:             h.latency,
+		Requests:            h.requests,
+		Errors:              h.errors,
+		ConsecutiveFailures: h.failures,
+		LastError:           h.lastErr,
+		LastSuccess:         h.lastSuccess,
+	}
+}
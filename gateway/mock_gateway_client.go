@@ -1,8 +1,10 @@
 package gateway
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/filecoin-project/go-address"
@@ -12,6 +14,8 @@ import (
 	"github.com/filecoin-project/venus/venus-shared/types"
 	gtypes "github.com/filecoin-project/venus/venus-shared/types/gateway"
 
+	"github.com/filecoin-project/venus-messager/config"
+	"github.com/filecoin-project/venus-messager/models/mtypes"
 	"github.com/filecoin-project/venus-messager/testhelper"
 )
 
@@ -27,6 +31,15 @@ func NewMockWalletProxy() *MockWalletProxy {
 	}
 }
 
+// NewMockWalletPool returns a WalletProxy over the mock gateways keyed by url
+func NewMockWalletPool(cfg *config.GatewayConfig, gateways map[string]*MockWalletProxy) *WalletProxy {
+	clients := make(map[string]gatewayClient, len(gateways))
+	for url, gw := range gateways {
+		clients[url] = gw
+	}
+	return newWalletProxy(cfg, clients)
+}
+
 func (m *MockWalletProxy) AddAddress(account string, addrs []address.Address) error {
 	m.l.Lock()
 	defer m.l.Unlock()
@@ -104,12 +117,66 @@ func (m *MockWalletProxy) WalletSign(ctx context.Context, addr address.Address,
 	}, nil
 }
 
+// Verify checks the signature is made by WalletSign of the mock
+func (m *MockWalletProxy) Verify(sig *crypto.Signature, addr address.Address, msg []byte) error {
+	if sig.Type != testhelper.AddressProtocolToSignType(addr.Protocol()) {
+		return fmt.Errorf("signature type %d not match address %s", sig.Type, addr)
+	}
+	if !bytes.Equal(sig.Data, append(append([]byte{}, msg...), addr.Bytes()...)) {
+		return fmt.Errorf("signature not signed by %s", addr)
+	}
+	return nil
+}
+
+// ListWalletInfo returns a wallet for each account, which supports the account and owns its addresses
 func (m *MockWalletProxy) ListWalletInfo(ctx context.Context) ([]*gtypes.WalletDetail, error) {
-	panic("implement me")
+	m.l.Lock()
+	defer m.l.Unlock()
+
+	details := make([]*gtypes.WalletDetail, 0, len(m.accountAddrs))
+	for account, currAddrs := range m.accountAddrs {
+		addrs := make([]address.Address, 0, len(currAddrs))
+		for addr := range currAddrs {
+			addrs = append(addrs, addr)
+		}
+		sort.Slice(addrs, func(i, j int) bool {
+			return addrs[i].String() < addrs[j].String()
+		})
+		details = append(details, &gtypes.WalletDetail{
+			Account:         account,
+			SupportAccounts: []string{account},
+			ConnectStates:   []gtypes.ConnectState{{Addrs: addrs}},
+		})
+	}
+	sort.Slice(details, func(i, j int) bool {
+		return details[i].Account < details[j].Account
+	})
+
+	return details, nil
 }
 
 func (m *MockWalletProxy) ListWalletInfoByWallet(ctx context.Context, wallet string) (*gtypes.WalletDetail, error) {
-	panic("implement me")
+	details, err := m.ListWalletInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, detail := range details {
+		if detail.Account == wallet {
+			return detail, nil
+		}
+	}
+	return nil, fmt.Errorf("wallet %s not found", wallet)
+}
+
+func (m *MockWalletProxy) Version(ctx context.Context) (types.Version, error) {
+	return types.Version{Version: "mock"}, nil
 }
 
-var _ gatewayAPI.IWalletClient = (*MockWalletProxy)(nil)
+func (m *MockWalletProxy) ListGatewayStatus(ctx context.Context) ([]*mtypes.GatewayStatus, error) {
+	return []*mtypes.GatewayStatus{}, nil
+}
+
+var (
+	_ gatewayAPI.IWalletClient = (*MockWalletProxy)(nil)
+	_ IGatewayStatus           = (*MockWalletProxy)(nil)
+)
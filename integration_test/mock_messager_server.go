@@ -97,10 +97,13 @@ func mockMessagerServer(ctx context.Context, repoPath string, cfg *config.Config
 	}
 	lst := manet.NetListener(apiListener)
 
+	nodePool := service.NewNodePool(&cfg.Node, fullNode)
+
 	provider := fx.Options(
 		// prover
 		fx.Supply(cfg, &cfg.DB, &cfg.API, &cfg.JWT, &cfg.Node, &cfg.Log, &cfg.MessageService, cfg.Libp2pNet,
-			&cfg.Gateway, &cfg.RateLimit, cfg.Trace, cfg.Metrics, cfg.Publisher),
+			&cfg.Gateway, &cfg.RateLimit, cfg.Trace, cfg.Metrics, cfg.Publisher, cfg.Approval, cfg.HA,
+			cfg.Sharding),
 		fx.Supply(fullNode),
 		fx.Supply(networkParams.NetworkName),
 		fx.Supply(remoteAuthClient),
@@ -109,11 +112,21 @@ func mockMessagerServer(ctx context.Context, repoPath string, cfg *config.Config
 		fx.Provide(func() gatewayAPI.IWalletClient {
 			return walletCli
 		}),
+		fx.Provide(func() gateway.IGatewayStatus {
+			return walletCli
+		}),
+		fx.Provide(func() service.SigVerifier {
+			return walletCli
+		}),
 		fx.Provide(func() jwtclient.IAuthClient {
 			return authClient
 		}),
+		fx.Provide(func() jwtclient.IJwtAuthClient {
+			return jwtclient.WarpIJwtAuthClient(remoteAuthClient)
+		}),
+		fx.Supply(nodePool),
 		fx.Provide(func() v1.FullNode {
-			return fullNode
+			return nodePool
 		}),
 		fx.Provide(func() filestore.FSRepo {
 			return fsRepo
@@ -137,7 +150,9 @@ func mockMessagerServer(ctx context.Context, repoPath string, cfg *config.Config
 
 	invoker := fx.Options(
 		// invoke
+		fx.Invoke(service.StartNodePool),
 		fx.Invoke(service.StartNodeEvents),
+		fx.Invoke(service.WatchPublishRejection),
 		fx.Invoke(metrics.SetupJaeger),
 		fx.Invoke(metrics.SetupMetrics),
 	)
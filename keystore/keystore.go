@@ -0,0 +1,284 @@
+package keystore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/filecoin-project/go-address"
+	logging "github.com/ipfs/go-log/v2"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/filecoin-project/venus/pkg/crypto"
+
+	"github.com/filecoin-project/venus-messager/config"
+)
+
+var log = logging.Logger("keystore")
+
+// PassphraseEnv overrides the passphrase file in config
+const PassphraseEnv = "MESSAGER_KEYSTORE_PASSPHRASE"
+
+const (
+	keyFileExt = ".key"
+
+	// scrypt parameters recommended for interactive logins
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+	saltLen      = 32
+)
+
+var ErrKeyNotFound = errors.New("key not found")
+
+// encryptedKey is the content of key file, the key info in the format of lotus and venus wallet is encrypted by
+// AES-256-GCM with a key derived by scrypt
+type encryptedKey struct {
+	Salt       []byte
+	Nonce      []byte
+	Ciphertext []byte
+}
+
+// Passphrase returns the passphrase set by env or the passphrase file in config, the file readable by group or others
+// is rejected
+func Passphrase(cfg *config.WalletConfig) (string, error) {
+	if passphrase, ok := os.LookupEnv(PassphraseEnv); ok {
+		return passphrase, nil
+	}
+	if len(cfg.PassphraseFile) == 0 {
+		return "", nil
+	}
+	info, err := os.Stat(cfg.PassphraseFile)
+	if err != nil {
+		return "", fmt.Errorf("read passphrase file: %w", err)
+	}
+	if info.Mode().Perm()&0o077 != 0 {
+		return "", fmt.Errorf("passphrase file %s is accessible by group or others, its mode must be 0600", cfg.PassphraseFile)
+	}
+	data, err := os.ReadFile(cfg.PassphraseFile)
+	if err != nil {
+		return "", fmt.Errorf("read passphrase file: %w", err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// Keystore stores the keys in a directory, one encrypted file named by the address for each key. The keys are loaded
+// when opening, and the key files added by another process are loaded when looked up or listed.
+type Keystore struct {
+	dir        string
+	passphrase []byte
+
+	lk   sync.RWMutex
+	keys map[address.Address]*crypto.KeyInfo
+}
+
+// Open loads all keys in dir, the directory is created if not exists
+func Open(dir string, passphrase string) (*Keystore, error) {
+	if len(passphrase) == 0 {
+		return nil, fmt.Errorf("keystore passphrase is empty, set it by the passphrase file in config or env %s", PassphraseEnv)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	ks := &Keystore{
+		dir:        dir,
+		passphrase: []byte(passphrase),
+		keys:       make(map[address.Address]*crypto.KeyInfo),
+	}
+	if err := ks.load(); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+// load reads the key files which are not loaded yet
+func (ks *Keystore) load() error {
+	entries, err := os.ReadDir(ks.dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), keyFileExt) {
+			continue
+		}
+		addr, err := address.NewFromString(strings.TrimSuffix(entry.Name(), keyFileExt))
+		if err != nil {
+			log.Warnf("skip key file %s: %v", entry.Name(), err)
+			continue
+		}
+		if _, err := ks.loadKey(addr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadKey reads the key file of addr if it is not loaded yet
+func (ks *Keystore) loadKey(addr address.Address) (*crypto.KeyInfo, error) {
+	ks.lk.Lock()
+	defer ks.lk.Unlock()
+	if ki, ok := ks.keys[addr]; ok {
+		return ki, nil
+	}
+
+	file := ks.keyFile(addr)
+	ki, err := ks.readKey(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%s: %w", addr, ErrKeyNotFound)
+		}
+		return nil, fmt.Errorf("read key %s: %w", file, err)
+	}
+	keyAddr, err := ki.Address()
+	if err != nil {
+		return nil, fmt.Errorf("read key %s: %w", file, err)
+	}
+	if keyAddr != addr {
+		return nil, fmt.Errorf("key file %s contains the key of %s", file, keyAddr)
+	}
+	ks.keys[addr] = ki
+	return ki, nil
+}
+
+// Get returns the key of addr
+func (ks *Keystore) Get(addr address.Address) (*crypto.KeyInfo, error) {
+	ks.lk.RLock()
+	ki, ok := ks.keys[addr]
+	ks.lk.RUnlock()
+	if ok {
+		return ki, nil
+	}
+	return ks.loadKey(addr)
+}
+
+// Has returns whether the key of addr exists
+func (ks *Keystore) Has(addr address.Address) (bool, error) {
+	_, err := ks.Get(addr)
+	if errors.Is(err, ErrKeyNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// List returns the addresses of all keys, sorted by address
+func (ks *Keystore) List() ([]address.Address, error) {
+	if err := ks.load(); err != nil {
+		return nil, err
+	}
+	ks.lk.RLock()
+	defer ks.lk.RUnlock()
+	addrs := make([]address.Address, 0, len(ks.keys))
+	for addr := range ks.keys {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return addrs[i].String() < addrs[j].String()
+	})
+	return addrs, nil
+}
+
+// Import saves the key, it fails if the key of the same address exists
+func (ks *Keystore) Import(ki *crypto.KeyInfo) (address.Address, error) {
+	addr, err := ki.Address()
+	if err != nil {
+		return address.Undef, err
+	}
+
+	ks.lk.Lock()
+	defer ks.lk.Unlock()
+	file := ks.keyFile(addr)
+	if _, err := os.Stat(file); err == nil {
+		return address.Undef, fmt.Errorf("key of %s already exists", addr)
+	}
+	if err := ks.writeKey(file, ki); err != nil {
+		return address.Undef, err
+	}
+	ks.keys[addr] = ki
+	return addr, nil
+}
+
+func (ks *Keystore) keyFile(addr address.Address) string {
+	return filepath.Join(ks.dir, addr.String()+keyFileExt)
+}
+
+func (ks *Keystore) gcm(salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key(ks.passphrase, salt, scryptN, scryptR, scryptP, scryptKeyLen)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (ks *Keystore) writeKey(file string, ki *crypto.KeyInfo) error {
+	plaintext, err := json.Marshal(ki)
+	if err != nil {
+		return err
+	}
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	gcm, err := ks.gcm(salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	data, err := json.Marshal(&encryptedKey{
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plaintext, nil),
+	})
+	if err != nil {
+		return err
+	}
+
+	// write to a temp file first, the key file is not broken if the process exits when writing
+	tmp := file + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
+
+func (ks *Keystore) readKey(file string) (*crypto.KeyInfo, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var ek encryptedKey
+	if err := json.Unmarshal(data, &ek); err != nil {
+		return nil, err
+	}
+	gcm, err := ks.gcm(ek.Salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(ek.Nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("invalid nonce length %d", len(ek.Nonce))
+	}
+	plaintext, err := gcm.Open(nil, ek.Nonce, ek.Ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt failed, wrong passphrase? %w", err)
+	}
+	var ki crypto.KeyInfo
+	if err := json.Unmarshal(plaintext, &ki); err != nil {
+		return nil, err
+	}
+	return &ki, nil
+}
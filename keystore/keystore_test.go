@@ -0,0 +1,159 @@
+package keystore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	vcrypto "github.com/filecoin-project/venus/pkg/crypto"
+	"github.com/filecoin-project/venus/venus-shared/types"
+
+	"github.com/filecoin-project/venus-messager/config"
+)
+
+func newKeyInfo(t *testing.T, sigType crypto.SigType) *vcrypto.KeyInfo {
+	priv, err := vcrypto.Generate(sigType)
+	require.NoError(t, err)
+	ki := &vcrypto.KeyInfo{SigType: sigType}
+	ki.SetPrivateKey(priv)
+	return ki
+}
+
+func newAddress(t *testing.T, sigType crypto.SigType) address.Address {
+	addr, err := newKeyInfo(t, sigType).Address()
+	require.NoError(t, err)
+	return addr
+}
+
+func TestKeystore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "keystore")
+	passphrase := "passphrase"
+
+	_, err := Open(dir, "")
+	assert.Error(t, err)
+
+	ks, err := Open(dir, passphrase)
+	require.NoError(t, err)
+
+	secpKey := newKeyInfo(t, crypto.SigTypeSecp256k1)
+	secpAddr, err := ks.Import(secpKey)
+	require.NoError(t, err)
+	_, err = ks.Import(secpKey)
+	assert.Error(t, err)
+
+	blsKey := newKeyInfo(t, crypto.SigTypeBLS)
+	blsAddr, err := ks.Import(blsKey)
+	require.NoError(t, err)
+
+	unknownKey := &vcrypto.KeyInfo{SigType: crypto.SigTypeUnknown}
+	unknownKey.SetPrivateKey(secpKey.Key())
+	_, err = ks.Import(unknownKey)
+	assert.Error(t, err)
+
+	// the key file is encrypted
+	data, err := os.ReadFile(filepath.Join(dir, secpAddr.String()+keyFileExt))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "secp256k1")
+
+	unknown := newAddress(t, crypto.SigTypeSecp256k1)
+	has, err := ks.Has(unknown)
+	require.NoError(t, err)
+	assert.False(t, has)
+	_, err = ks.Get(unknown)
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+
+	// the keys imported by another process are loaded
+	other, err := Open(dir, passphrase)
+	require.NoError(t, err)
+	addrs, err := other.List()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []address.Address{secpAddr, blsAddr}, addrs)
+
+	newKey := newKeyInfo(t, crypto.SigTypeSecp256k1)
+	newAddr, err := other.Import(newKey)
+	require.NoError(t, err)
+	ki, err := ks.Get(newAddr)
+	require.NoError(t, err)
+	assert.True(t, newKey.Equals(ki))
+
+	ki, err = other.Get(blsAddr)
+	require.NoError(t, err)
+	assert.True(t, blsKey.Equals(ki))
+
+	_, err = Open(dir, "wrong passphrase")
+	assert.Error(t, err)
+}
+
+func TestLocalWallet(t *testing.T) {
+	ctx := context.Background()
+	ks, err := Open(t.TempDir(), "passphrase")
+	require.NoError(t, err)
+	wallet := NewLocalWallet(ks)
+
+	var addrs []address.Address
+	for _, sigType := range []crypto.SigType{crypto.SigTypeSecp256k1, crypto.SigTypeBLS} {
+		addr, err := ks.Import(newKeyInfo(t, sigType))
+		require.NoError(t, err)
+		addrs = append(addrs, addr)
+
+		has, err := wallet.WalletHas(ctx, addr, nil)
+		require.NoError(t, err)
+		assert.True(t, has)
+
+		msg := []byte("message cid")
+		sig, err := wallet.WalletSign(ctx, addr, nil, msg, types.MsgMeta{Type: types.MTChainMsg})
+		require.NoError(t, err)
+		assert.Equal(t, sigType, sig.Type)
+		assert.NoError(t, vcrypto.Verify(sig, addr, msg))
+	}
+
+	details, err := wallet.ListWalletInfo(ctx)
+	require.NoError(t, err)
+	require.Len(t, details, 1)
+	assert.Equal(t, LocalWalletName, details[0].Account)
+	assert.ElementsMatch(t, addrs, details[0].ConnectStates[0].Addrs)
+
+	_, err = wallet.ListWalletInfoByWallet(ctx, "unknown")
+	assert.Error(t, err)
+
+	unknown := newAddress(t, crypto.SigTypeBLS)
+	has, err := wallet.WalletHas(ctx, unknown, nil)
+	require.NoError(t, err)
+	assert.False(t, has)
+	_, err = wallet.WalletSign(ctx, unknown, nil, []byte("msg"), types.MsgMeta{})
+	assert.Error(t, err)
+}
+
+func TestPassphrase(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "passphrase")
+	cfg := &config.WalletConfig{PassphraseFile: file}
+
+	require.NoError(t, os.WriteFile(file, []byte("passphrase\n"), 0o600))
+	passphrase, err := Passphrase(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "passphrase", passphrase)
+
+	// env overrides the file
+	t.Setenv(PassphraseEnv, "from env")
+	passphrase, err = Passphrase(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "from env", passphrase)
+	require.NoError(t, os.Unsetenv(PassphraseEnv))
+
+	// the file readable by others is rejected
+	require.NoError(t, os.Chmod(file, 0o644))
+	_, err = Passphrase(cfg)
+	assert.Error(t, err)
+
+	_, err = Passphrase(&config.WalletConfig{PassphraseFile: file + ".missing"})
+	assert.Error(t, err)
+	passphrase, err = Passphrase(&config.WalletConfig{})
+	require.NoError(t, err)
+	assert.Empty(t, passphrase)
+}
@@ -0,0 +1,19 @@
+package keystore
+
+import (
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/crypto"
+
+	vcrypto "github.com/filecoin-project/venus/pkg/crypto"
+	// register the signatures of venus crypto
+	_ "github.com/filecoin-project/venus/pkg/crypto/secp"
+
+	_ "github.com/filecoin-project/venus-messager/crypto/bls"
+)
+
+// Verifier verifies signatures by venus crypto
+type Verifier struct{}
+
+func (Verifier) Verify(sig *crypto.Signature, addr address.Address, msg []byte) error {
+	return vcrypto.Verify(sig, addr, msg)
+}
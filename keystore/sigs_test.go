@@ -0,0 +1,69 @@
+package keystore
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	vcrypto "github.com/filecoin-project/venus/pkg/crypto"
+)
+
+// TestInteropVectors checks the keys exported by lotus and venus wallet derive the same addresses and signatures.
+// The private keys are 1, so the public keys are the generators of the curves, which are known by any implementation.
+func TestInteropVectors(t *testing.T) {
+	msg := []byte("venus-messager keystore vector")
+	for _, tc := range []struct {
+		exported  string
+		publicKey string
+		addr      string
+		sig       string
+	}{
+		{
+			exported: "7b2254797065223a22736563703235366b31222c22507269766174654b6579223a2241414141414141414141414141414141" +
+				"4141414141414141414141414141414141414141414141414141453d227d",
+			publicKey: "0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798" +
+				"483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8",
+			addr: "f1wcuzrs736zqzbbjjdgl2wvyyufuk4pefbymzf2i",
+			sig: "67d0df270cd2d67fb908750f99a1f308541f0718435d177f90b049961123465106209de6937deb15e6d0430dc7a39891ee4d" +
+				"78755dfc04e71f655f5a3526f23001",
+		},
+		{
+			exported: "7b2254797065223a22626c73222c22507269766174654b6579223a224151414141414141414141414141414141414141" +
+				"41414141414141414141414141414141414141414141413d227d",
+			publicKey: "97f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb",
+			addr:      "f3s7y5hjzrs7lzijuvmoge7knmb7bwrdcps52lsbnbjy5d6fy3vrmgyvpih74xugxp7m5pacw3eldlw5rocaha",
+			sig: "af39d59ccaee5a2bd9e0788b21af2265aea29a27a8566b966dd1e1c04873128e9675e6327f87d47806facffb363dbd1e0c1433" +
+				"bacdc65e6c0579e58752d4c221a88736d003222130a391d822a4361f71e5ecfc6368a9844f7bac1d4d65b77c15",
+		},
+	} {
+		data, err := hex.DecodeString(tc.exported)
+		require.NoError(t, err)
+		var ki vcrypto.KeyInfo
+		require.NoError(t, json.Unmarshal(data, &ki))
+
+		pub, err := hex.DecodeString(tc.publicKey)
+		require.NoError(t, err)
+		var expectAddr address.Address
+		if ki.SigType == crypto.SigTypeBLS {
+			expectAddr, err = address.NewBLSAddress(pub)
+		} else {
+			expectAddr, err = address.NewSecp256k1Address(pub)
+		}
+		require.NoError(t, err)
+		assert.Equal(t, tc.addr[1:], expectAddr.String()[1:])
+
+		addr, err := ki.Address()
+		require.NoError(t, err)
+		assert.Equal(t, expectAddr, addr)
+
+		sig, err := vcrypto.Sign(msg, ki.Key(), ki.SigType)
+		require.NoError(t, err)
+		assert.Equal(t, tc.sig, hex.EncodeToString(sig.Data))
+		assert.NoError(t, vcrypto.Verify(sig, addr, msg))
+	}
+}
@@ -0,0 +1,68 @@
+package keystore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/crypto"
+
+	vcrypto "github.com/filecoin-project/venus/pkg/crypto"
+	gatewayAPI "github.com/filecoin-project/venus/venus-shared/api/gateway/v2"
+	"github.com/filecoin-project/venus/venus-shared/types"
+	gtypes "github.com/filecoin-project/venus/venus-shared/types/gateway"
+)
+
+// LocalWalletName is the wallet name of the local keystore in ListWalletInfo
+const LocalWalletName = "local"
+
+// LocalWallet signs by the keys in keystore instead of the wallets connected to venus-gateway, the accounts are
+// ignored as all keys belong to the messager.
+type LocalWallet struct {
+	ks *Keystore
+}
+
+func NewLocalWallet(ks *Keystore) *LocalWallet {
+	return &LocalWallet{ks: ks}
+}
+
+func (w *LocalWallet) WalletHas(ctx context.Context, addr address.Address, accounts []string) (bool, error) {
+	return w.ks.Has(addr)
+}
+
+func (w *LocalWallet) WalletSign(ctx context.Context, addr address.Address, accounts []string, toSign []byte, meta types.MsgMeta) (*crypto.Signature, error) {
+	ki, err := w.ks.Get(addr)
+	if err != nil {
+		return nil, err
+	}
+	var sig *crypto.Signature
+	err = ki.UsePrivateKey(func(privateKey []byte) error {
+		sig, err = vcrypto.Sign(toSign, privateKey, ki.SigType)
+		return err
+	})
+	return sig, err
+}
+
+func (w *LocalWallet) ListWalletInfo(ctx context.Context) ([]*gtypes.WalletDetail, error) {
+	detail, err := w.ListWalletInfoByWallet(ctx, LocalWalletName)
+	if err != nil {
+		return nil, err
+	}
+	return []*gtypes.WalletDetail{detail}, nil
+}
+
+func (w *LocalWallet) ListWalletInfoByWallet(ctx context.Context, wallet string) (*gtypes.WalletDetail, error) {
+	if wallet != LocalWalletName {
+		return nil, fmt.Errorf("wallet %s not found", wallet)
+	}
+	addrs, err := w.ks.List()
+	if err != nil {
+		return nil, err
+	}
+	return &gtypes.WalletDetail{
+		Account:       LocalWalletName,
+		ConnectStates: []gtypes.ConnectState{{Addrs: addrs}},
+	}, nil
+}
+
+var _ gatewayAPI.IWalletClient = (*LocalWallet)(nil)
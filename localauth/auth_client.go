@@ -0,0 +1,329 @@
+package localauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-jsonrpc/auth"
+	venusauth "github.com/filecoin-project/venus-auth/auth"
+	"github.com/filecoin-project/venus-auth/core"
+	"github.com/filecoin-project/venus-auth/jwtclient"
+	jwt3 "github.com/gbrlsnchs/jwt/v3"
+	"gorm.io/gorm"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/models/repo"
+)
+
+var (
+	ErrUserNotFound  = errors.New("user not found")
+	ErrTokenNotFound = errors.New("token not found")
+	errMinerNotFound = errors.New("miners are not supported by local auth")
+)
+
+var (
+	_ jwtclient.IAuthClient    = (*AuthClient)(nil)
+	_ jwtclient.IJwtAuthClient = (*AuthClient)(nil)
+)
+
+// AuthClient keeps the users, tokens and signer bindings in the db of messager, it replaces venus-auth for the
+// deployments which don't want to run one.
+type AuthClient struct {
+	repo repo.AuthRepo
+}
+
+func NewAuthClient(repo repo.Repo) *AuthClient {
+	return &AuthClient{repo: repo.AuthRepo()}
+}
+
+// HashToken returns the hash of token kept in db, tokens are looked up by their hashes, so a leaked db doesn't
+// leak the tokens
+func HashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// isTokenHash returns whether s is the hash of a token, a jwt token is never in this form
+func isTokenHash(s string) bool {
+	if len(s) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+// Verify checks the token is issued by CreateToken and not removed
+func (c *AuthClient) Verify(ctx context.Context, token string) ([]auth.Permission, error) {
+	t, err := c.repo.GetToken(HashToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTokenNotFound
+		}
+		return nil, err
+	}
+
+	jwtPerms := core.AdaptOldStrategy(t.Perm)
+	perms := make([]auth.Permission, len(jwtPerms))
+	copy(perms, jwtPerms)
+	return perms, nil
+}
+
+func (c *AuthClient) CreateUser(name, comment string) error {
+	if len(name) == 0 {
+		return fmt.Errorf("user name is empty")
+	}
+	return c.repo.CreateUser(&mtypes.AuthUser{Name: name, Comment: comment, CreatedAt: time.Now()})
+}
+
+func (c *AuthClient) DeleteUser(name string) error {
+	if err := c.repo.DeleteUser(name); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (c *AuthClient) ListLocalUsers() ([]*mtypes.AuthUser, error) {
+	return c.repo.ListUsers()
+}
+
+func (c *AuthClient) getUser(name string) (*mtypes.AuthUser, error) {
+	user, err := c.repo.GetUser(name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// CreateToken issues a token of perm to the user, the name of user is carried in the token, so the requests
+// with it can be traced to the user. Only the hash of token is saved, the token can't be shown again.
+func (c *AuthClient) CreateToken(name string, perm string) (string, error) {
+	if err := core.ContainsPerm(perm); err != nil {
+		return "", fmt.Errorf("%w: %s", err, perm)
+	}
+	if _, err := c.getUser(name); err != nil {
+		return "", err
+	}
+
+	secret, err := jwtclient.RandSecret()
+	if err != nil {
+		return "", err
+	}
+	token, err := jwt3.Sign(venusauth.JWTPayload{Name: name, Perm: perm}, jwt3.NewHS256(secret))
+	if err != nil {
+		return "", err
+	}
+
+	err = c.repo.CreateToken(&mtypes.AuthToken{
+		TokenHash: HashToken(string(token)),
+		Name:      name,
+		Perm:      perm,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(token), nil
+}
+
+func (c *AuthClient) ListTokens(name string) ([]*mtypes.AuthToken, error) {
+	return c.repo.ListTokens(name)
+}
+
+// RemoveToken removes the token, token can be the token itself or its hash listed by ListTokens
+func (c *AuthClient) RemoveToken(token string) error {
+	if !isTokenHash(token) {
+		token = HashToken(token)
+	}
+	if err := c.repo.DeleteToken(token); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTokenNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (c *AuthClient) BindSigners(name string, signers []address.Address) error {
+	if _, err := c.getUser(name); err != nil {
+		return err
+	}
+	return c.repo.BindSigners(name, signers)
+}
+
+func (c *AuthClient) UnbindSigners(name string, signers []address.Address) error {
+	if _, err := c.getUser(name); err != nil {
+		return err
+	}
+	return c.repo.UnbindSigners(name, signers)
+}
+
+// the methods of jwtclient.IAuthClient
+
+func (c *AuthClient) VerifyUsers(names []string) error {
+	for _, name := range names {
+		if _, err := c.getUser(name); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (c *AuthClient) HasUser(req *venusauth.HasUserRequest) (bool, error) {
+	_, err := c.getUser(req.Name)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *AuthClient) GetUser(req *venusauth.GetUserRequest) (*venusauth.OutputUser, error) {
+	user, err := c.getUser(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	return toOutputUser(user), nil
+}
+
+func (c *AuthClient) GetUserByMiner(req *venusauth.GetUserByMinerRequest) (*venusauth.OutputUser, error) {
+	return nil, errMinerNotFound
+}
+
+func (c *AuthClient) GetUserBySigner(signer string) (venusauth.ListUsersResponse, error) {
+	addr, err := address.NewFromString(signer)
+	if err != nil {
+		return nil, err
+	}
+	names, err := c.repo.ListSignerUsers(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make(venusauth.ListUsersResponse, 0, len(names))
+	for _, name := range names {
+		user, err := c.getUser(name)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, toOutputUser(user))
+	}
+	return users, nil
+}
+
+func (c *AuthClient) ListUsers(req *venusauth.ListUsersRequest) (venusauth.ListUsersResponse, error) {
+	users, err := c.repo.ListUsers()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(venusauth.ListUsersResponse, 0, len(users))
+	for _, user := range users {
+		res = append(res, toOutputUser(user))
+	}
+	return res, nil
+}
+
+func (c *AuthClient) ListUsersWithMiners(req *venusauth.ListUsersRequest) (venusauth.ListUsersResponse, error) {
+	return c.ListUsers(req)
+}
+
+func (c *AuthClient) GetUserRateLimit(name, id string) (venusauth.GetUserRateLimitResponse, error) {
+	return venusauth.GetUserRateLimitResponse{}, nil
+}
+
+func (c *AuthClient) MinerExistInUser(user, miner string) (bool, error) {
+	return false, nil
+}
+
+func (c *AuthClient) SignerExistInUser(user, signer string) (bool, error) {
+	u, err := c.getUser(user)
+	if err != nil {
+		return false, err
+	}
+	for _, s := range u.Signers {
+		if s.String() == signer {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (c *AuthClient) HasMiner(req *venusauth.HasMinerRequest) (bool, error) {
+	return false, nil
+}
+
+func (c *AuthClient) ListMiners(user string) (venusauth.ListMinerResp, error) {
+	return venusauth.ListMinerResp{}, nil
+}
+
+func (c *AuthClient) HasSigner(signer string) (bool, error) {
+	users, err := c.GetUserBySigner(signer)
+	if err != nil {
+		return false, err
+	}
+	return len(users) > 0, nil
+}
+
+func (c *AuthClient) ListSigners(user string) (venusauth.ListSignerResp, error) {
+	u, err := c.getUser(user)
+	if err != nil {
+		return nil, err
+	}
+	res := make(venusauth.ListSignerResp, 0, len(u.Signers))
+	for _, signer := range u.Signers {
+		res = append(res, &venusauth.OutputSigner{Signer: signer.String(), User: u.Name})
+	}
+	return res, nil
+}
+
+func (c *AuthClient) RegisterSigners(user string, addrs []string) error {
+	signers, err := parseAddrs(addrs)
+	if err != nil {
+		return err
+	}
+	return c.BindSigners(user, signers)
+}
+
+func (c *AuthClient) UnregisterSigners(user string, addrs []string) error {
+	signers, err := parseAddrs(addrs)
+	if err != nil {
+		return err
+	}
+	return c.UnbindSigners(user, signers)
+}
+
+func parseAddrs(addrs []string) ([]address.Address, error) {
+	res := make([]address.Address, 0, len(addrs))
+	for _, a := range addrs {
+		addr, err := address.NewFromString(a)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, addr)
+	}
+	return res, nil
+}
+
+func toOutputUser(user *mtypes.AuthUser) *venusauth.OutputUser {
+	return &venusauth.OutputUser{
+		Id:         user.Name,
+		Name:       user.Name,
+		Comment:    user.Comment,
+		State:      core.UserStateEnabled,
+		CreateTime: user.CreatedAt.Unix(),
+		UpdateTime: user.CreatedAt.Unix(),
+	}
+}
@@ -0,0 +1,121 @@
+package localauth
+
+import (
+	"context"
+	"testing"
+
+	venusauth "github.com/filecoin-project/venus-auth/auth"
+	"github.com/filecoin-project/venus-auth/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/venus-messager/config"
+	"github.com/filecoin-project/venus-messager/filestore"
+	"github.com/filecoin-project/venus-messager/models"
+	"github.com/filecoin-project/venus-messager/testhelper"
+)
+
+func TestAuthClient(t *testing.T) {
+	ctx := context.Background()
+
+	fsRepo := filestore.NewMockFileStore(t.TempDir())
+	require.NoError(t, fsRepo.ReplaceConfig(config.DefaultConfig()))
+	repo, err := models.SetDataBase(fsRepo)
+	require.NoError(t, err)
+	require.NoError(t, repo.AutoMigrate())
+
+	cli := NewAuthClient(repo)
+	addrs := testhelper.RandAddresses(t, 3)
+
+	require.NoError(t, cli.CreateUser("alice", "test"))
+	require.NoError(t, cli.CreateUser("bob", ""))
+	assert.Error(t, cli.CreateUser("", ""))
+	assert.NoError(t, cli.VerifyUsers([]string{"alice", "bob"}))
+	assert.ErrorIs(t, cli.VerifyUsers([]string{"alice", "carol"}), ErrUserNotFound)
+
+	t.Run("token", func(t *testing.T) {
+		_, err := cli.CreateToken("alice", "root")
+		assert.ErrorIs(t, err, core.ErrPermIllegal)
+		_, err = cli.CreateToken("carol", core.PermRead)
+		assert.ErrorIs(t, err, ErrUserNotFound)
+
+		token, err := cli.CreateToken("alice", core.PermSign)
+		require.NoError(t, err)
+		perms, err := cli.Verify(ctx, token)
+		require.NoError(t, err)
+		assert.Equal(t, core.AdaptOldStrategy(core.PermSign), perms)
+		// the name of user is carried in the token for auth mux
+		name, err := venusauth.JwtUserFromToken(token)
+		require.NoError(t, err)
+		assert.Equal(t, "alice", name)
+
+		tokens, err := cli.ListTokens("alice")
+		require.NoError(t, err)
+		require.Len(t, tokens, 1)
+		// only the hash of token is saved
+		assert.Equal(t, HashToken(token), tokens[0].TokenHash)
+		assert.NotContains(t, tokens[0].TokenHash, token)
+
+		require.NoError(t, cli.RemoveToken(token))
+		_, err = cli.Verify(ctx, token)
+		assert.ErrorIs(t, err, ErrTokenNotFound)
+		assert.ErrorIs(t, cli.RemoveToken(token), ErrTokenNotFound)
+
+		// remove by the listed hash
+		token, err = cli.CreateToken("alice", core.PermRead)
+		require.NoError(t, err)
+		tokens, err = cli.ListTokens("alice")
+		require.NoError(t, err)
+		require.Len(t, tokens, 1)
+		_, err = cli.Verify(ctx, tokens[0].TokenHash)
+		assert.ErrorIs(t, err, ErrTokenNotFound)
+		require.NoError(t, cli.RemoveToken(tokens[0].TokenHash))
+		_, err = cli.Verify(ctx, token)
+		assert.ErrorIs(t, err, ErrTokenNotFound)
+	})
+
+	t.Run("signer", func(t *testing.T) {
+		require.NoError(t, cli.BindSigners("alice", addrs[:2]))
+		require.NoError(t, cli.RegisterSigners("bob", []string{addrs[1].String()}))
+		assert.ErrorIs(t, cli.BindSigners("carol", addrs[:1]), ErrUserNotFound)
+
+		users, err := cli.GetUserBySigner(addrs[1].String())
+		require.NoError(t, err)
+		require.Len(t, users, 2)
+		assert.Equal(t, "alice", users[0].Name)
+		assert.Equal(t, "bob", users[1].Name)
+
+		users, err = cli.GetUserBySigner(addrs[2].String())
+		require.NoError(t, err)
+		assert.Len(t, users, 0)
+
+		has, err := cli.SignerExistInUser("alice", addrs[0].String())
+		require.NoError(t, err)
+		assert.True(t, has)
+		has, err = cli.HasSigner(addrs[2].String())
+		require.NoError(t, err)
+		assert.False(t, has)
+
+		require.NoError(t, cli.UnbindSigners("alice", addrs[1:2]))
+		users, err = cli.GetUserBySigner(addrs[1].String())
+		require.NoError(t, err)
+		require.Len(t, users, 1)
+		assert.Equal(t, "bob", users[0].Name)
+	})
+
+	t.Run("delete user", func(t *testing.T) {
+		token, err := cli.CreateToken("alice", core.PermRead)
+		require.NoError(t, err)
+		require.NoError(t, cli.DeleteUser("alice"))
+		assert.ErrorIs(t, cli.DeleteUser("alice"), ErrUserNotFound)
+
+		_, err = cli.Verify(ctx, token)
+		assert.ErrorIs(t, err, ErrTokenNotFound)
+		has, err := cli.HasUser(&venusauth.HasUserRequest{Name: "alice"})
+		require.NoError(t, err)
+		assert.False(t, has)
+		users, err := cli.GetUserBySigner(addrs[0].String())
+		require.NoError(t, err)
+		assert.Len(t, users, 0)
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/filecoin-project/venus-messager/publisher"
 	"github.com/filecoin-project/venus-messager/publisher/pubsub"
 
+	"github.com/filecoin-project/go-jsonrpc"
 	"github.com/filecoin-project/venus-auth/jwtclient"
 	"github.com/filecoin-project/venus-messager/metrics"
 	"github.com/filecoin-project/venus-messager/utils"
@@ -30,6 +31,8 @@ import (
 	"github.com/filecoin-project/venus-messager/config"
 	"github.com/filecoin-project/venus-messager/filestore"
 	"github.com/filecoin-project/venus-messager/gateway"
+	"github.com/filecoin-project/venus-messager/keystore"
+	"github.com/filecoin-project/venus-messager/localauth"
 	"github.com/filecoin-project/venus-messager/models"
 	"github.com/filecoin-project/venus-messager/service"
 	"github.com/filecoin-project/venus-messager/version"
@@ -55,6 +58,11 @@ func main() {
 			ccli.LogCmds,
 			ccli.SendCmd,
 			ccli.SwarmCmds,
+			ccli.ApprovalCmds,
+			ccli.AuditCmds,
+			ccli.GatewayCmds,
+			ccli.WalletCmds,
+			ccli.AuthCmds,
 			runCmd,
 		},
 	}
@@ -152,13 +160,13 @@ func runAction(cctx *cli.Context) error {
 	}
 
 	log.Infof("node info url: %s, token: %s\n", cfg.Node.Url, cfg.Node.Token)
-	log.Infof("auth info url: %s\n", cfg.JWT.AuthURL)
+	log.Infof("auth info type: %s, url: %s\n", cfg.JWT.Type, cfg.JWT.AuthURL)
 	log.Infof("gateway info url: %s, token: %s\n", cfg.Gateway.Url, cfg.Gateway.Token)
 	log.Infof("rate limit info: redis: %s \n", cfg.RateLimit.Redis)
 	log.Infof("defalut timeout: %v, sign message timeout: %v, estimate message timeout: %v", cfg.MessageService.DefaultTimeout,
 		cfg.MessageService.SignMessageTimeout, cfg.MessageService.EstimateMessageTimeout)
 
-	remoteAuthCli, err := jwtclient.NewAuthClient(cfg.JWT.AuthURL)
+	authOption, err := newAuthOption(cfg)
 	if err != nil {
 		return err
 	}
@@ -222,7 +230,7 @@ func runAction(cctx *cli.Context) error {
 		return err
 	}
 
-	walletCli, walletCliCloser, err := gateway.NewWalletClient(ctx, &cfg.Gateway)
+	walletCli, gatewayStatus, walletCliCloser, err := newWalletClient(ctx, cfg, fsRepo)
 	if err != nil {
 		return err
 	}
@@ -236,23 +244,30 @@ func runAction(cctx *cli.Context) error {
 	}
 	lst := manet.NetListener(apiListener)
 
+	nodePool := service.NewNodePool(&cfg.Node, client)
+
 	provider := fx.Options(
 		fx.Logger(fxLogger{}),
 		// prover
 		fx.Supply(cfg, &cfg.DB, &cfg.API, &cfg.JWT, &cfg.Node, &cfg.Log, &cfg.MessageService, cfg.Libp2pNet,
-			&cfg.Gateway, &cfg.RateLimit, cfg.Trace, cfg.Metrics, cfg.Publisher),
+			&cfg.Gateway, &cfg.RateLimit, cfg.Trace, cfg.Metrics, cfg.Publisher, cfg.Approval, cfg.HA,
+			cfg.Sharding),
 		fx.Supply(networkParams.NetworkName),
 		fx.Supply(networkParams),
-		fx.Supply(remoteAuthCli),
+		authOption,
 		fx.Supply(localAuthCli),
 		fx.Provide(func() gatewayAPI.IWalletClient {
 			return walletCli
 		}),
-		fx.Provide(func() jwtclient.IAuthClient {
-			return remoteAuthCli
+		fx.Provide(func() gateway.IGatewayStatus {
+			return gatewayStatus
+		}),
+		fx.Provide(func() service.SigVerifier {
+			return keystore.Verifier{}
 		}),
+		fx.Supply(nodePool),
 		fx.Provide(func() v1.FullNode {
-			return client
+			return nodePool
 		}),
 		fx.Provide(func() filestore.FSRepo {
 			return fsRepo
@@ -276,7 +291,9 @@ func runAction(cctx *cli.Context) error {
 
 	invoker := fx.Options(
 		// invoke
+		fx.Invoke(service.StartNodePool),
 		fx.Invoke(service.StartNodeEvents),
+		fx.Invoke(service.WatchPublishRejection),
 		fx.Invoke(metrics.SetupJaeger),
 		fx.Invoke(metrics.SetupMetrics),
 	)
@@ -375,6 +392,68 @@ func (l fxLogger) Printf(str string, args ...interface{}) {
 	log.Infof(str, args...)
 }
 
+// newWalletClient returns the signer backend selected by config
+func newWalletClient(ctx context.Context, cfg *config.Config, fsRepo filestore.FSRepo) (gatewayAPI.IWalletClient, gateway.IGatewayStatus, jsonrpc.ClientCloser, error) {
+	switch cfg.Wallet.Type {
+	case config.WalletLocal:
+		passphrase, err := keystore.Passphrase(&cfg.Wallet)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		ks, err := keystore.Open(fsRepo.KeystoreDir(), passphrase)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("open keystore: %w", err)
+		}
+		log.Infof("sign messages by local keystore %s", fsRepo.KeystoreDir())
+		return keystore.NewLocalWallet(ks), gateway.NoGateway{}, func() {}, nil
+	case config.WalletGateway, "":
+		walletCli, closer, err := gateway.NewWalletClient(ctx, &cfg.Gateway)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return walletCli, walletCli, closer, nil
+	default:
+		return nil, nil, nil, fmt.Errorf("unknown wallet type %s", cfg.Wallet.Type)
+	}
+}
+
+// newAuthOption provides the auth clients selected by config, the remote *jwtclient.AuthClient is nil in local mode,
+// which disables the rate limiter
+func newAuthOption(cfg *config.Config) (fx.Option, error) {
+	switch cfg.JWT.Type {
+	case config.AuthLocal:
+		log.Info("verify tokens and find users of signers by local auth")
+		return fx.Options(
+			fx.Provide(localauth.NewAuthClient),
+			fx.Provide(func(cli *localauth.AuthClient) jwtclient.IAuthClient {
+				return cli
+			}),
+			fx.Provide(func(cli *localauth.AuthClient) jwtclient.IJwtAuthClient {
+				return cli
+			}),
+			fx.Provide(func() *jwtclient.AuthClient {
+				return nil
+			}),
+		), nil
+	case config.AuthRemote, "":
+		remoteAuthCli, err := jwtclient.NewAuthClient(cfg.JWT.AuthURL)
+		if err != nil {
+			return nil, err
+		}
+		return fx.Options(
+			fx.Supply(remoteAuthCli),
+			fx.Provide(func() jwtclient.IAuthClient {
+				return remoteAuthCli
+			}),
+			fx.Provide(func() jwtclient.IJwtAuthClient {
+				return jwtclient.WarpIJwtAuthClient(remoteAuthCli)
+			}),
+		), nil
+	default:
+		return nil, fmt.Errorf("unknown jwt type %s", cfg.JWT.Type)
+	}
+}
+
 func hasFSRepo(repoPath string) (bool, error) {
 	fi, err := os.Stat(repoPath)
 	if err != nil {
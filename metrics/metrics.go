@@ -10,10 +10,13 @@ import (
 // Global Tags
 var (
 	WalletAddress, _ = tag.NewKey("wallet")
+	GatewayURL, _    = tag.NewKey("gateway")
 )
 
 // Distribution
 var defaultSecondsDistribution = view.Distribution(8, 9, 10, 12, 14, 16, 18, 20, 25, 30, 60)
+var propagationSecondsDistribution = view.Distribution(0.1, 0.25, 0.5, 1, 2, 5, 10, 30, 60)
+var requestMillisecondsDistribution = view.Distribution(5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000)
 
 var (
 	WalletBalance    = stats.Float64("wallet_balance", "Wallet balance", stats.UnitDimensionless)
@@ -33,6 +36,24 @@ var (
 
 	ChainHeadStableDelay    = stats.Int64("chain_head_stable_s", "Delay of chain head stabilization", stats.UnitSeconds)
 	ChainHeadStableDuration = stats.Int64("chain_head_stable_dur_s", "Duration of chain head stabilization", stats.UnitSeconds)
+
+	IsLeader = stats.Int64("is_leader", "Whether the instance holds the leader lease, 1 means leader", stats.UnitDimensionless)
+
+	NodeSynced = stats.Int64("node_synced", "Whether the node is synced, selecting message pauses when it is 0", stats.UnitDimensionless)
+
+	InvalidSignature  = stats.Int64("invalid_signature_num", "Number of signatures returned by wallet failed to verify", stats.UnitDimensionless)
+	AddressSignPaused = stats.Int64("address_sign_paused", "Whether signing of the address is paused for repeated failures, 1 means paused", stats.UnitDimensionless)
+
+	MsgPropagationDelay = stats.Float64("msg_propagation_s", "Delay from publishing a message to seeing it from network", stats.UnitSeconds)
+	NumOfUnseenMsg      = stats.Int64("unseen_msg_num", "Number of messages not seen by network in time", stats.UnitDimensionless)
+
+	GatewayRequestLatency = stats.Float64("gateway_request_ms", "Latency of requests to gateway", stats.UnitMilliseconds)
+	GatewayRequestErrors  = stats.Int64("gateway_request_errors", "Number of failed requests to gateway", stats.UnitDimensionless)
+	GatewayHealthy        = stats.Int64("gateway_healthy", "Whether the gateway is healthy, 1 means healthy", stats.UnitDimensionless)
+
+	AuthRequestLatency = stats.Float64("auth_request_ms", "Latency of requests to auth service for the users of signers", stats.UnitMilliseconds)
+	AuthRequestErrors  = stats.Int64("auth_request_errors", "Number of failed requests to auth service for the users of signers", stats.UnitDimensionless)
+	SignerCacheStale   = stats.Int64("signer_cache_stale_num", "Number of times the expired users of signers are used for auth service failures", stats.UnitDimensionless)
 )
 
 var (
@@ -102,6 +123,66 @@ var (
 		Measure:     ChainHeadStableDuration,
 		Aggregation: defaultSecondsDistribution,
 	}
+
+	IsLeaderView = &view.View{
+		Measure:     IsLeader,
+		Aggregation: view.LastValue(),
+	}
+
+	NodeSyncedView = &view.View{
+		Measure:     NodeSynced,
+		Aggregation: view.LastValue(),
+	}
+
+	InvalidSignatureView = &view.View{
+		Measure:     InvalidSignature,
+		Aggregation: view.Sum(),
+		TagKeys:     []tag.Key{WalletAddress},
+	}
+	AddressSignPausedView = &view.View{
+		Measure:     AddressSignPaused,
+		Aggregation: view.LastValue(),
+		TagKeys:     []tag.Key{WalletAddress},
+	}
+
+	MsgPropagationDelayView = &view.View{
+		Measure:     MsgPropagationDelay,
+		Aggregation: propagationSecondsDistribution,
+	}
+	NumOfUnseenMsgView = &view.View{
+		Measure:     NumOfUnseenMsg,
+		Aggregation: view.Sum(),
+	}
+
+	GatewayRequestLatencyView = &view.View{
+		Measure:     GatewayRequestLatency,
+		Aggregation: requestMillisecondsDistribution,
+		TagKeys:     []tag.Key{GatewayURL},
+	}
+	GatewayRequestErrorsView = &view.View{
+		Measure:     GatewayRequestErrors,
+		Aggregation: view.Sum(),
+		TagKeys:     []tag.Key{GatewayURL},
+	}
+	GatewayHealthyView = &view.View{
+		Measure:     GatewayHealthy,
+		Aggregation: view.LastValue(),
+		TagKeys:     []tag.Key{GatewayURL},
+	}
+
+	AuthRequestLatencyView = &view.View{
+		Measure:     AuthRequestLatency,
+		Aggregation: requestMillisecondsDistribution,
+	}
+	AuthRequestErrorsView = &view.View{
+		Measure:     AuthRequestErrors,
+		Aggregation: view.Sum(),
+	}
+	SignerCacheStaleView = &view.View{
+		Measure:     SignerCacheStale,
+		Aggregation: view.Sum(),
+		TagKeys:     []tag.Key{WalletAddress},
+	}
 )
 
 var MessagerNodeViews = append([]*view.View{
@@ -122,4 +203,21 @@ var MessagerNodeViews = append([]*view.View{
 
 	ChainHeadStableDelayView,
 	ChainHeadStableDurationView,
+
+	IsLeaderView,
+	NodeSyncedView,
+
+	InvalidSignatureView,
+	AddressSignPausedView,
+
+	MsgPropagationDelayView,
+	NumOfUnseenMsgView,
+
+	GatewayRequestLatencyView,
+	GatewayRequestErrorsView,
+	GatewayHealthyView,
+
+	AuthRequestLatencyView,
+	AuthRequestErrorsView,
+	SignerCacheStaleView,
 }, metrics.DefaultViews...)
@@ -0,0 +1,42 @@
+package mtypes
+
+import (
+	"time"
+
+	"github.com/filecoin-project/go-address"
+)
+
+type ApprovalState int
+
+const (
+	ApprovalPending ApprovalState = iota
+	ApprovalApproved
+	ApprovalRejected
+)
+
+func (s ApprovalState) String() string {
+	switch s {
+	case ApprovalPending:
+		return "Pending"
+	case ApprovalApproved:
+		return "Approved"
+	case ApprovalRejected:
+		return "Rejected"
+	default:
+		return "Unknown"
+	}
+}
+
+// MessageApproval records why a message needs a second approver, who pushed it and
+// who approved or rejected it, it is kept after the decision as an audit trail.
+type MessageApproval struct {
+	MsgID     string
+	From      address.Address
+	Reason    string
+	Requester string
+	State     ApprovalState
+	Approver  string
+	Comment   string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,25 @@
+package mtypes
+
+import "time"
+
+// AuditLog records a mutating api call
+type AuditLog struct {
+	ID      string
+	Account string
+	Method  string
+	// Params, Previous and Result are json encoded
+	Params    string
+	Previous  string
+	Result    string
+	Error     string
+	CreatedAt time.Time
+}
+
+// AuditQuery is the filter of audit logs, zero value of a field means not filter by it
+type AuditQuery struct {
+	Account string
+	Method  string
+	Start   time.Time
+	End     time.Time
+	Limit   int
+}
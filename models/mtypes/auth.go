@@ -0,0 +1,25 @@
+package mtypes
+
+import (
+	"time"
+
+	"github.com/filecoin-project/go-address"
+)
+
+// AuthUser is a user of the local auth, which replaces venus-auth when `jwt.type` is `local`
+type AuthUser struct {
+	Name    string
+	Comment string
+	// Signers are the addresses bound to the user, the messages of them can be pushed by the user
+	Signers   []address.Address
+	CreatedAt time.Time
+}
+
+// AuthToken is a token issued to a user of the local auth, only the hash of token is kept
+type AuthToken struct {
+	// TokenHash is the hex encoded sha256 hash of token
+	TokenHash string
+	Name      string
+	Perm      string
+	CreatedAt time.Time
+}
@@ -0,0 +1,19 @@
+package mtypes
+
+import "time"
+
+// GatewayStatus is the health of a gateway used to sign messages
+type GatewayStatus struct {
+	Url     string
+	Weight  int
+	Healthy bool
+	// Latency is the moving average latency of the successful requests
+	Latency             time.Duration
+	Requests            int64
+	Errors              int64
+	ConsecutiveFailures int
+	LastError           string
+	LastSuccess         time.Time
+	// CachedAddresses is the number of addresses cached on the gateway
+	CachedAddresses int
+}
@@ -0,0 +1,38 @@
+package mtypes
+
+import (
+	"time"
+
+	"github.com/filecoin-project/go-address"
+)
+
+// Lease is held by the leader instance, it must be renewed before ExpireAt
+type Lease struct {
+	Name      string
+	Holder    string
+	ExpireAt  time.Time
+	UpdatedAt time.Time
+}
+
+// LeaderInfo shows the leadership of instance
+type LeaderInfo struct {
+	// ID is the id of current instance
+	ID string
+	// HAEnabled false means there is only one instance, it is always the leader
+	HAEnabled bool
+	IsLeader  bool
+	// Leader is the holder of lease, empty when HA not enabled
+	Leader   string
+	ExpireAt time.Time
+}
+
+// ShardingInfo shows the live instances and the addresses owned by current instance
+type ShardingInfo struct {
+	// ID is the id of current instance
+	ID string
+	// Enabled false means current instance owns all addresses
+	Enabled bool
+	// Instances are the live instances sharing the database
+	Instances      []string
+	OwnedAddresses []address.Address
+}
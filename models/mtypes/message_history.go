@@ -0,0 +1,42 @@
+package mtypes
+
+import (
+	"time"
+
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/ipfs/go-cid"
+
+	types "github.com/filecoin-project/venus/venus-shared/types/messager"
+)
+
+// Trigger is the component which changed the state of message
+type Trigger string
+
+const (
+	TriggerPush         Trigger = "push"
+	TriggerSelector     Trigger = "selector"
+	TriggerStateRefresh Trigger = "state_refresh"
+	TriggerReplace      Trigger = "replace"
+	TriggerApproval     Trigger = "approval"
+	TriggerAPI          Trigger = "api"
+	TriggerPublisher    Trigger = "publisher"
+)
+
+// MessageHistory records a state transition of message, it is append only
+type MessageHistory struct {
+	ID       string
+	MsgID    string
+	OldState types.MessageState
+	NewState types.MessageState
+
+	GasLimit   int64
+	GasFeeCap  big.Int
+	GasPremium big.Int
+	SignedCid  *cid.Cid
+	Error      string
+	// Height is the tipset height when the transition happened
+	Height  int64
+	Trigger Trigger
+
+	CreatedAt time.Time
+}
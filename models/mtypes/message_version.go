@@ -0,0 +1,25 @@
+package mtypes
+
+import (
+	"time"
+
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/crypto"
+	"github.com/ipfs/go-cid"
+)
+
+// MessageVersion is a signed version of message, a message has more than one version after replaced
+type MessageVersion struct {
+	SignedCid  cid.Cid
+	MsgID      string
+	GasLimit   int64
+	GasFeeCap  big.Int
+	GasPremium big.Int
+	// Signature is nil for the version not signed by messager
+	Signature *crypto.Signature
+	// Included means this version is the one included by chain
+	Included bool
+	// Conflict means this version is not signed by messager, it took the nonce of the message on chain
+	Conflict  bool
+	CreatedAt time.Time
+}
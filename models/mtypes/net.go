@@ -0,0 +1,41 @@
+package mtypes
+
+import (
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+// NetListenInfo is the addresses and the NAT traversal options of the libp2p host
+type NetListenInfo struct {
+	ID peer.ID
+	// ListenAddrs are the addresses the host listens on
+	ListenAddrs []ma.Multiaddr
+	// Addrs are the addresses announced to peers, including the relay addresses
+	Addrs []ma.Multiaddr
+
+	EnableRelay        bool
+	StaticRelays       []string
+	EnableHolePunching bool
+	EnableNATPortMap   bool
+}
+
+// PeerInfo is the connected peer
+type PeerInfo struct {
+	ID        peer.ID
+	Addrs     []ma.Multiaddr
+	Agent     string
+	Latency   time.Duration
+	Protocols []string
+	// Topics are the gossipsub topics the peer joined
+	Topics []string
+	// Protected peers are never pruned by connection manager
+	Protected bool
+}
+
+// BannedPeer is the peer which can't connect to or be connected
+type BannedPeer struct {
+	ID       peer.ID
+	BannedAt time.Time
+}
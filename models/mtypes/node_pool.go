@@ -0,0 +1,20 @@
+package mtypes
+
+import "time"
+
+// NodePoolInfo shows the node which chain head and reads come from
+type NodePoolInfo struct {
+	// FailoverEnabled false means always use the main node
+	FailoverEnabled bool
+	Active          string
+	Nodes           []*NodeHealth
+}
+
+// NodeHealth is the result of the latest health check of a node
+type NodeHealth struct {
+	Name      string
+	Height    int64
+	Healthy   bool
+	Error     string
+	CheckedAt time.Time
+}
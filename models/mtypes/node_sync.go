@@ -0,0 +1,13 @@
+package mtypes
+
+import "time"
+
+// NodeSyncStatus is the result of the latest sync check before selecting messages
+type NodeSyncStatus struct {
+	// Synced false means selecting messages is paused
+	Synced     bool
+	Reason     string
+	HeadHeight int64
+	HeadTime   time.Time
+	CheckedAt  time.Time
+}
@@ -0,0 +1,18 @@
+package mtypes
+
+import (
+	"time"
+
+	"github.com/filecoin-project/go-address"
+)
+
+// PausedAddress is an address whose signing is paused after repeated signing failures
+type PausedAddress struct {
+	Addr address.Address
+	// Reason is the error of the last signing failure
+	Reason   string
+	Failures int
+	PausedAt time.Time
+	// ResumeAt is the time to try signing again, it resumes earlier when the wallet reports having the address
+	ResumeAt time.Time
+}
@@ -0,0 +1,39 @@
+package mtypes
+
+import (
+	"time"
+
+	"github.com/filecoin-project/go-address"
+)
+
+// PublishNodeHealth is the health of a node which messages are pushed to
+type PublishNodeHealth struct {
+	Name string
+	// Weight 0 means the node is a backup
+	Weight int
+	// Healthy false means the node is excluded from publishing until NextRetry
+	Healthy             bool
+	LastSuccess         time.Time
+	ConsecutiveFailures int
+	LastError           string
+	Latency             time.Duration
+	HeadHeight          int64
+	NextRetry           time.Time
+}
+
+// PublishRoute shows where the messages of an address would be published
+type PublishRoute struct {
+	From address.Address
+	// Group is the address group of the routing rule matched, empty means the address is not restricted
+	Group  string
+	Tags   []string
+	Pubsub bool
+	Nodes  []*PublishRouteNode
+}
+
+type PublishRouteNode struct {
+	Name     string
+	Selected bool
+	// Reason is why the node is not selected
+	Reason string
+}
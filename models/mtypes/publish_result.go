@@ -0,0 +1,58 @@
+package mtypes
+
+import (
+	"time"
+
+	"github.com/ipfs/go-cid"
+)
+
+// PublishErrorClass is the category of error returned by node when pushing message
+type PublishErrorClass string
+
+const (
+	PublishErrConnection       PublishErrorClass = "connection"
+	PublishErrNonceTooLow      PublishErrorClass = "nonce_too_low"
+	PublishErrAlreadyInMpool   PublishErrorClass = "already_in_mpool"
+	PublishErrFeeCapTooLow     PublishErrorClass = "fee_cap_too_low"
+	PublishErrReplaceFeeTooLow PublishErrorClass = "replace_fee_too_low"
+	PublishErrNotEnoughFunds   PublishErrorClass = "not_enough_funds"
+	PublishErrGasLimit         PublishErrorClass = "gas_limit"
+	PublishErrSignature        PublishErrorClass = "invalid_signature"
+	PublishErrMpoolLimit       PublishErrorClass = "mpool_limit"
+	PublishErrUnknown          PublishErrorClass = "unknown"
+)
+
+// Rejected returns whether the message is rejected by node, other errors are either caused by the connection or
+// mean the message is already accepted.
+func (c PublishErrorClass) Rejected() bool {
+	switch c {
+	case "", PublishErrConnection, PublishErrNonceTooLow, PublishErrAlreadyInMpool:
+		return false
+	default:
+		return true
+	}
+}
+
+// PublishResult is the result of pushing a signed message to a node
+type PublishResult struct {
+	Node    string
+	Success bool
+	// ErrorClass and Error are the last error, they are empty when the last push succeeded
+	ErrorClass PublishErrorClass
+	Error      string
+	Attempts   int
+	// Rejections is the number of consecutive rejections
+	Rejections int
+	UpdatedAt  time.Time
+}
+
+// MessagePropagation records when a published message is seen from network
+type MessagePropagation struct {
+	SignedCid   cid.Cid
+	PublishedAt time.Time
+	// SeenAt is zero when the message is not seen by network yet
+	SeenAt   time.Time
+	SeenFrom string
+	// Republished is true when the message is republished by rpc nodes as it is not seen in time
+	Republished bool
+}
@@ -0,0 +1,18 @@
+package mtypes
+
+import "github.com/filecoin-project/go-address"
+
+// DiscoveredAddress is a signer address found in the wallets connected to gateways
+type DiscoveredAddress struct {
+	Addr address.Address
+	// Wallets are the wallets owning the address
+	Wallets []string
+	// Accounts are the accounts supported by the wallets
+	Accounts []string
+	// Registered is whether the address exists in messager
+	Registered bool
+	// Deleted is whether the address was deleted from messager, it is not registered again
+	Deleted bool
+	// Error is the reason failed to check or register the address
+	Error string
+}
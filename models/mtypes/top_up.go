@@ -0,0 +1,21 @@
+package mtypes
+
+import (
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/big"
+)
+
+// TopUpRecord records a transfer pushed by messager to fund a low-balance address
+type TopUpRecord struct {
+	ID       string
+	Addr     address.Address
+	Treasury address.Address
+	// MsgID is the id of the transfer message
+	MsgID string
+	Value big.Int
+	// Balance is the balance of Addr when top up was triggered
+	Balance   big.Int
+	CreatedAt time.Time
+}
@@ -0,0 +1,119 @@
+package mysql
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/venus/venus-shared/testutil"
+	venusTypes "github.com/filecoin-project/venus/venus-shared/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/models/repo"
+)
+
+func TestApproval(t *testing.T) {
+	r, mock, sqlDB := setup(t)
+
+	t.Run("mysql test create approval", wrapper(testCreateApproval, r, mock))
+	t.Run("mysql test get approval", wrapper(testGetApproval, r, mock))
+	t.Run("mysql test update approval", wrapper(testUpdateApproval, r, mock))
+	t.Run("mysql test list approval", wrapper(testListApproval, r, mock))
+	t.Run("mysql test list pending message id", wrapper(testListPendingMsgID, r, mock))
+
+	assert.NoError(t, closeDB(mock, sqlDB))
+}
+
+func testCreateApproval(t *testing.T, r repo.Repo, mock sqlmock.Sqlmock) {
+	approval := &mtypes.MessageApproval{
+		MsgID:     venusTypes.NewUUID().String(),
+		From:      testutil.AddressProvider()(t),
+		Reason:    "value exceeds limit",
+		Requester: "admin",
+		State:     mtypes.ApprovalPending,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	insertSql, insertArgs := genInsertSQL(fromApproval(approval))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(insertSql)).
+		WithArgs(insertArgs...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, r.ApprovalRepo().CreateApproval(approval))
+}
+
+func testGetApproval(t *testing.T, r repo.Repo, mock sqlmock.Sqlmock) {
+	msgID := venusTypes.NewUUID().String()
+	from := testutil.AddressProvider()(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `message_approvals` WHERE msg_id = ? LIMIT 1")).
+		WithArgs(msgID).
+		WillReturnRows(sqlmock.NewRows([]string{"msg_id", "from_addr", "state"}).AddRow(msgID, from.String(), mtypes.ApprovalPending))
+
+	approval, err := r.ApprovalRepo().GetApproval(msgID)
+	assert.NoError(t, err)
+	assert.Equal(t, msgID, approval.MsgID)
+	assert.Equal(t, from, approval.From)
+	assert.Equal(t, mtypes.ApprovalPending, approval.State)
+}
+
+func testUpdateApproval(t *testing.T, r repo.Repo, mock sqlmock.Sqlmock) {
+	approval := &mtypes.MessageApproval{
+		MsgID:    venusTypes.NewUUID().String(),
+		State:    mtypes.ApprovalApproved,
+		Approver: "approver",
+		Comment:  "ok",
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `message_approvals` SET `approver`=?,`comment`=?,`state`=?,`updated_at`=? WHERE msg_id = ?")).
+		WithArgs(approval.Approver, approval.Comment, approval.State, anyTime{}, approval.MsgID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, r.ApprovalRepo().UpdateApproval(approval))
+}
+
+func testListApproval(t *testing.T, r repo.Repo, mock sqlmock.Sqlmock) {
+	from := testutil.AddressProvider()(t)
+	ids := []string{venusTypes.NewUUID().String(), venusTypes.NewUUID().String()}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `message_approvals` WHERE state = ? AND from_addr = ? ORDER BY created_at")).
+		WithArgs(mtypes.ApprovalPending, from.String()).
+		WillReturnRows(sqlmock.NewRows([]string{"msg_id", "from_addr"}).AddRow(ids[0], from.String()).AddRow(ids[1], from.String()))
+
+	approvals, err := r.ApprovalRepo().ListApproval(from, mtypes.ApprovalPending)
+	assert.NoError(t, err)
+	assert.Len(t, approvals, 2)
+	for i, approval := range approvals {
+		assert.Equal(t, ids[i], approval.MsgID)
+	}
+
+	// not filter by from
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `message_approvals` WHERE state = ? ORDER BY created_at")).
+		WithArgs(mtypes.ApprovalRejected).
+		WillReturnRows(sqlmock.NewRows([]string{"msg_id", "from_addr"}).AddRow(ids[0], from.String()))
+
+	approvals, err = r.ApprovalRepo().ListApproval(address.Undef, mtypes.ApprovalRejected)
+	assert.NoError(t, err)
+	assert.Len(t, approvals, 1)
+}
+
+func testListPendingMsgID(t *testing.T, r repo.Repo, mock sqlmock.Sqlmock) {
+	from := testutil.AddressProvider()(t)
+	ids := []string{venusTypes.NewUUID().String(), venusTypes.NewUUID().String()}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `msg_id` FROM `message_approvals` WHERE from_addr = ? and state = ?")).
+		WithArgs(from.String(), mtypes.ApprovalPending).
+		WillReturnRows(sqlmock.NewRows([]string{"msg_id"}).AddRow(ids[0]).AddRow(ids[1]))
+
+	res, err := r.ApprovalRepo().ListPendingMsgID(from)
+	assert.NoError(t, err)
+	assert.Equal(t, ids, res)
+}
@@ -0,0 +1,74 @@
+package mysql
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	venusTypes "github.com/filecoin-project/venus/venus-shared/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/models/repo"
+)
+
+func TestAudit(t *testing.T) {
+	r, mock, sqlDB := setup(t)
+
+	t.Run("mysql test create audit log", wrapper(testCreateAuditLog, r, mock))
+	t.Run("mysql test list audit log", wrapper(testListAuditLog, r, mock))
+
+	assert.NoError(t, closeDB(mock, sqlDB))
+}
+
+func testCreateAuditLog(t *testing.T, r repo.Repo, mock sqlmock.Sqlmock) {
+	log := &mtypes.AuditLog{
+		ID:        venusTypes.NewUUID().String(),
+		Account:   "admin",
+		Method:    "SetFeeParams",
+		Params:    `["f01000"]`,
+		Previous:  `{}`,
+		Result:    `null`,
+		CreatedAt: time.Now(),
+	}
+
+	insertSql, insertArgs := genInsertSQL(fromAuditLog(log))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(insertSql)).
+		WithArgs(insertArgs...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, r.AuditRepo().CreateAuditLog(log))
+}
+
+func testListAuditLog(t *testing.T, r repo.Repo, mock sqlmock.Sqlmock) {
+	query := &mtypes.AuditQuery{
+		Account: "admin",
+		Method:  "SetFeeParams",
+		Start:   time.Now().Add(-time.Hour),
+		End:     time.Now(),
+		Limit:   10,
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `audit_logs` WHERE account = ? AND method = ? AND created_at >= ? "+
+		"AND created_at < ? ORDER BY created_at DESC LIMIT 10")).
+		WithArgs(query.Account, query.Method, query.Start, query.End).
+		WillReturnRows(sqlmock.NewRows([]string{"account", "method"}).
+			AddRow(query.Account, query.Method).
+			AddRow(query.Account, query.Method))
+
+	logs, err := r.AuditRepo().ListAuditLog(query)
+	assert.NoError(t, err)
+	assert.Len(t, logs, 2)
+	assert.Equal(t, query.Method, logs[0].Method)
+
+	// zero query lists all
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `audit_logs` ORDER BY created_at DESC")).
+		WillReturnRows(sqlmock.NewRows([]string{"account"}).AddRow(query.Account))
+
+	logs, err = r.AuditRepo().ListAuditLog(&mtypes.AuditQuery{})
+	assert.NoError(t, err)
+	assert.Len(t, logs, 1)
+}
@@ -0,0 +1,220 @@
+package mysql
+
+import (
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/models/repo"
+)
+
+type mysqlAuthUser struct {
+	Name    string `gorm:"column:name;type:varchar(256);primary_key;"`
+	Comment string `gorm:"column:comment;type:varchar(256);NOT NULL"`
+
+	CreatedAt time.Time `gorm:"column:created_at;NOT NULL"`
+}
+
+func (s mysqlAuthUser) TableName() string {
+	return "auth_users"
+}
+
+type mysqlAuthToken struct {
+	// TokenHash is the hex encoded sha256 hash of token, the token itself is not stored
+	TokenHash string `gorm:"column:token_hash;type:varchar(64);primary_key;"`
+	Name      string `gorm:"column:name;type:varchar(256);index;NOT NULL"`
+	Perm      string `gorm:"column:perm;type:varchar(32);NOT NULL"`
+
+	CreatedAt time.Time `gorm:"column:created_at;NOT NULL"`
+}
+
+func (s mysqlAuthToken) TableName() string {
+	return "auth_tokens"
+}
+
+func (s mysqlAuthToken) AuthToken() *mtypes.AuthToken {
+	return &mtypes.AuthToken{
+		TokenHash: s.TokenHash,
+		Name:      s.Name,
+		Perm:      s.Perm,
+		CreatedAt: s.CreatedAt,
+	}
+}
+
+type mysqlAuthSigner struct {
+	Name   string `gorm:"column:name;type:varchar(256);primary_key;"`
+	Signer string `gorm:"column:signer;type:varchar(256);primary_key;index"`
+
+	CreatedAt time.Time `gorm:"column:created_at;NOT NULL"`
+}
+
+func (s mysqlAuthSigner) TableName() string {
+	return "auth_signers"
+}
+
+var _ repo.AuthRepo = (*mysqlAuthRepo)(nil)
+
+type mysqlAuthRepo struct {
+	*gorm.DB
+}
+
+func newMysqlAuthRepo(db *gorm.DB) mysqlAuthRepo {
+	return mysqlAuthRepo{DB: db}
+}
+
+func (s mysqlAuthRepo) CreateUser(user *mtypes.AuthUser) error {
+	return s.DB.Create(&mysqlAuthUser{
+		Name:      user.Name,
+		Comment:   user.Comment,
+		CreatedAt: user.CreatedAt,
+	}).Error
+}
+
+func (s mysqlAuthRepo) GetUser(name string) (*mtypes.AuthUser, error) {
+	var user mysqlAuthUser
+	if err := s.DB.Take(&user, "name = ?", name).Error; err != nil {
+		return nil, err
+	}
+	users, err := s.withSigners([]*mysqlAuthUser{&user})
+	if err != nil {
+		return nil, err
+	}
+	return users[0], nil
+}
+
+func (s mysqlAuthRepo) ListUsers() ([]*mtypes.AuthUser, error) {
+	var users []*mysqlAuthUser
+	if err := s.DB.Order("name").Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return s.withSigners(users)
+}
+
+func (s mysqlAuthRepo) withSigners(users []*mysqlAuthUser) ([]*mtypes.AuthUser, error) {
+	names := make([]string, 0, len(users))
+	for _, u := range users {
+		names = append(names, u.Name)
+	}
+	var signers []*mysqlAuthSigner
+	if err := s.DB.Order("signer").Find(&signers, "name in ?", names).Error; err != nil {
+		return nil, err
+	}
+	userSigners := make(map[string][]address.Address, len(users))
+	for _, signer := range signers {
+		addr, err := address.NewFromString(signer.Signer)
+		if err != nil {
+			return nil, err
+		}
+		userSigners[signer.Name] = append(userSigners[signer.Name], addr)
+	}
+
+	result := make([]*mtypes.AuthUser, 0, len(users))
+	for _, u := range users {
+		result = append(result, &mtypes.AuthUser{
+			Name:      u.Name,
+			Comment:   u.Comment,
+			Signers:   userSigners[u.Name],
+			CreatedAt: u.CreatedAt,
+		})
+	}
+	return result, nil
+}
+
+func (s mysqlAuthRepo) DeleteUser(name string) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&mysqlAuthToken{}, "name = ?", name).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&mysqlAuthSigner{}, "name = ?", name).Error; err != nil {
+			return err
+		}
+		res := tx.Delete(&mysqlAuthUser{}, "name = ?", name)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (s mysqlAuthRepo) CreateToken(token *mtypes.AuthToken) error {
+	return s.DB.Create(&mysqlAuthToken{
+		TokenHash: token.TokenHash,
+		Name:      token.Name,
+		Perm:      token.Perm,
+		CreatedAt: token.CreatedAt,
+	}).Error
+}
+
+func (s mysqlAuthRepo) GetToken(tokenHash string) (*mtypes.AuthToken, error) {
+	var t mysqlAuthToken
+	if err := s.DB.Take(&t, "token_hash = ?", tokenHash).Error; err != nil {
+		return nil, err
+	}
+	return t.AuthToken(), nil
+}
+
+func (s mysqlAuthRepo) ListTokens(name string) ([]*mtypes.AuthToken, error) {
+	query := s.DB
+	if len(name) != 0 {
+		query = query.Where("name = ?", name)
+	}
+
+	var tokens []*mysqlAuthToken
+	if err := query.Order("created_at").Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+	result := make([]*mtypes.AuthToken, 0, len(tokens))
+	for _, t := range tokens {
+		result = append(result, t.AuthToken())
+	}
+	return result, nil
+}
+
+func (s mysqlAuthRepo) DeleteToken(tokenHash string) error {
+	res := s.DB.Delete(&mysqlAuthToken{}, "token_hash = ?", tokenHash)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (s mysqlAuthRepo) BindSigners(name string, signers []address.Address) error {
+	if len(signers) == 0 {
+		return nil
+	}
+	now := time.Now()
+	list := make([]*mysqlAuthSigner, 0, len(signers))
+	for _, signer := range signers {
+		list = append(list, &mysqlAuthSigner{Name: name, Signer: signer.String(), CreatedAt: now})
+	}
+	return s.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(list).Error
+}
+
+func (s mysqlAuthRepo) UnbindSigners(name string, signers []address.Address) error {
+	if len(signers) == 0 {
+		return nil
+	}
+	list := make([]string, 0, len(signers))
+	for _, signer := range signers {
+		list = append(list, signer.String())
+	}
+	return s.DB.Delete(&mysqlAuthSigner{}, "name = ? and signer in ?", name, list).Error
+}
+
+func (s mysqlAuthRepo) ListSignerUsers(signer address.Address) ([]string, error) {
+	var names []string
+	if err := s.DB.Model(&mysqlAuthSigner{}).Where("signer = ?", signer.String()).Order("name").
+		Pluck("name", &names).Error; err != nil {
+		return nil, err
+	}
+	return names, nil
+}
@@ -0,0 +1,199 @@
+package mysql
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/venus/venus-shared/testutil"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/models/repo"
+)
+
+func TestAuth(t *testing.T) {
+	r, mock, sqlDB := setup(t)
+
+	t.Run("mysql test create user", wrapper(testCreateUser, r, mock))
+	t.Run("mysql test get user", wrapper(testGetUser, r, mock))
+	t.Run("mysql test delete user", wrapper(testDeleteUser, r, mock))
+	t.Run("mysql test create token", wrapper(testCreateToken, r, mock))
+	t.Run("mysql test get token", wrapper(testGetToken, r, mock))
+	t.Run("mysql test list tokens", wrapper(testListTokens, r, mock))
+	t.Run("mysql test delete token", wrapper(testDeleteToken, r, mock))
+	t.Run("mysql test bind signers", wrapper(testBindSigners, r, mock))
+	t.Run("mysql test unbind signers", wrapper(testUnbindSigners, r, mock))
+	t.Run("mysql test list signer users", wrapper(testListSignerUsers, r, mock))
+
+	assert.NoError(t, closeDB(mock, sqlDB))
+}
+
+const (
+	authUser      = "user"
+	authTokenHash = "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+)
+
+func testCreateUser(t *testing.T, r repo.Repo, mock sqlmock.Sqlmock) {
+	user := &mtypes.AuthUser{Name: authUser, Comment: "comment", CreatedAt: time.Now()}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `auth_users` (`name`,`comment`,`created_at`) VALUES (?,?,?)")).
+		WithArgs(user.Name, user.Comment, anyTime{}).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, r.AuthRepo().CreateUser(user))
+}
+
+func testGetUser(t *testing.T, r repo.Repo, mock sqlmock.Sqlmock) {
+	signers := []address.Address{testutil.AddressProvider()(t), testutil.AddressProvider()(t)}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `auth_users` WHERE name = ? LIMIT 1")).
+		WithArgs(authUser).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "comment"}).AddRow(authUser, "comment"))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `auth_signers` WHERE name in (?) ORDER BY signer")).
+		WithArgs(authUser).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "signer"}).
+			AddRow(authUser, signers[0].String()).
+			AddRow(authUser, signers[1].String()))
+
+	user, err := r.AuthRepo().GetUser(authUser)
+	assert.NoError(t, err)
+	assert.Equal(t, authUser, user.Name)
+	assert.Equal(t, signers, user.Signers)
+}
+
+func testDeleteUser(t *testing.T, r repo.Repo, mock sqlmock.Sqlmock) {
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `auth_tokens` WHERE name = ?")).
+		WithArgs(authUser).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `auth_signers` WHERE name = ?")).
+		WithArgs(authUser).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `auth_users` WHERE name = ?")).
+		WithArgs(authUser).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, r.AuthRepo().DeleteUser(authUser))
+
+	// user not exist
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `auth_tokens` WHERE name = ?")).
+		WithArgs(authUser).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `auth_signers` WHERE name = ?")).
+		WithArgs(authUser).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `auth_users` WHERE name = ?")).
+		WithArgs(authUser).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	assert.ErrorIs(t, r.AuthRepo().DeleteUser(authUser), gorm.ErrRecordNotFound)
+}
+
+func testCreateToken(t *testing.T, r repo.Repo, mock sqlmock.Sqlmock) {
+	token := &mtypes.AuthToken{TokenHash: authTokenHash, Name: authUser, Perm: "write", CreatedAt: time.Now()}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `auth_tokens` (`token_hash`,`name`,`perm`,`created_at`) VALUES (?,?,?,?)")).
+		WithArgs(token.TokenHash, token.Name, token.Perm, anyTime{}).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, r.AuthRepo().CreateToken(token))
+}
+
+func testGetToken(t *testing.T, r repo.Repo, mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `auth_tokens` WHERE token_hash = ? LIMIT 1")).
+		WithArgs(authTokenHash).
+		WillReturnRows(sqlmock.NewRows([]string{"token_hash", "name", "perm"}).AddRow(authTokenHash, authUser, "write"))
+
+	token, err := r.AuthRepo().GetToken(authTokenHash)
+	assert.NoError(t, err)
+	assert.Equal(t, authTokenHash, token.TokenHash)
+	assert.Equal(t, authUser, token.Name)
+	assert.Equal(t, "write", token.Perm)
+}
+
+func testListTokens(t *testing.T, r repo.Repo, mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `auth_tokens` WHERE name = ? ORDER BY created_at")).
+		WithArgs(authUser).
+		WillReturnRows(sqlmock.NewRows([]string{"token_hash", "name"}).AddRow(authTokenHash, authUser))
+
+	tokens, err := r.AuthRepo().ListTokens(authUser)
+	assert.NoError(t, err)
+	assert.Len(t, tokens, 1)
+	assert.Equal(t, authTokenHash, tokens[0].TokenHash)
+
+	// not filter by user
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `auth_tokens` ORDER BY created_at")).
+		WillReturnRows(sqlmock.NewRows([]string{"token_hash", "name"}).AddRow(authTokenHash, authUser).AddRow("hash", "other"))
+
+	tokens, err = r.AuthRepo().ListTokens("")
+	assert.NoError(t, err)
+	assert.Len(t, tokens, 2)
+}
+
+func testDeleteToken(t *testing.T, r repo.Repo, mock sqlmock.Sqlmock) {
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `auth_tokens` WHERE token_hash = ?")).
+		WithArgs(authTokenHash).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, r.AuthRepo().DeleteToken(authTokenHash))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `auth_tokens` WHERE token_hash = ?")).
+		WithArgs(authTokenHash).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	assert.ErrorIs(t, r.AuthRepo().DeleteToken(authTokenHash), gorm.ErrRecordNotFound)
+}
+
+func testBindSigners(t *testing.T, r repo.Repo, mock sqlmock.Sqlmock) {
+	signers := []address.Address{testutil.AddressProvider()(t), testutil.AddressProvider()(t)}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `auth_signers` (`name`,`signer`,`created_at`) VALUES (?,?,?),(?,?,?) "+
+		"ON DUPLICATE KEY UPDATE `name`=`name`")).
+		WithArgs(authUser, signers[0].String(), anyTime{}, authUser, signers[1].String(), anyTime{}).
+		WillReturnResult(sqlmock.NewResult(2, 2))
+	mock.ExpectCommit()
+
+	assert.NoError(t, r.AuthRepo().BindSigners(authUser, signers))
+	// nothing to bind
+	assert.NoError(t, r.AuthRepo().BindSigners(authUser, nil))
+}
+
+func testUnbindSigners(t *testing.T, r repo.Repo, mock sqlmock.Sqlmock) {
+	signers := []address.Address{testutil.AddressProvider()(t), testutil.AddressProvider()(t)}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `auth_signers` WHERE name = ? and signer in (?,?)")).
+		WithArgs(authUser, signers[0].String(), signers[1].String()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	assert.NoError(t, r.AuthRepo().UnbindSigners(authUser, signers))
+}
+
+func testListSignerUsers(t *testing.T, r repo.Repo, mock sqlmock.Sqlmock) {
+	signer := testutil.AddressProvider()(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `name` FROM `auth_signers` WHERE signer = ? ORDER BY name")).
+		WithArgs(signer.String()).
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow(authUser).AddRow("other"))
+
+	names, err := r.AuthRepo().ListSignerUsers(signer)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{authUser, "other"}, names)
+}
@@ -31,6 +31,34 @@ func (d Repo) NodeRepo() repo.NodeRepo {
 	return newMysqlNodeRepo(d.DB)
 }
 
+func (d Repo) ApprovalRepo() repo.ApprovalRepo {
+	return newMysqlApprovalRepo(d.DB)
+}
+
+func (d Repo) TopUpRepo() repo.TopUpRepo {
+	return newMysqlTopUpRepo(d.DB)
+}
+
+func (d Repo) AuditRepo() repo.AuditRepo {
+	return newMysqlAuditRepo(d.DB)
+}
+
+func (d Repo) MessageHistoryRepo() repo.MessageHistoryRepo {
+	return newMysqlMessageHistoryRepo(d.DB)
+}
+
+func (d Repo) MessageVersionRepo() repo.MessageVersionRepo {
+	return newMysqlMessageVersionRepo(d.DB)
+}
+
+func (d Repo) AuthRepo() repo.AuthRepo {
+	return newMysqlAuthRepo(d.DB)
+}
+
+func (d Repo) LeaseRepo() repo.LeaseRepo {
+	return newMysqlLeaseRepo(d.DB)
+}
+
 func (d Repo) AutoMigrate() error {
 	err := d.GetDb().AutoMigrate(mysqlMessage{})
 	if err != nil {
@@ -45,7 +73,35 @@ func (d Repo) AutoMigrate() error {
 		return err
 	}
 
-	return d.GetDb().AutoMigrate(mysqlNode{})
+	if err := d.GetDb().AutoMigrate(mysqlNode{}); err != nil {
+		return err
+	}
+
+	if err := d.GetDb().AutoMigrate(mysqlApproval{}); err != nil {
+		return err
+	}
+
+	if err := d.GetDb().AutoMigrate(mysqlTopUp{}); err != nil {
+		return err
+	}
+
+	if err := d.GetDb().AutoMigrate(mysqlAuditLog{}); err != nil {
+		return err
+	}
+
+	if err := d.GetDb().AutoMigrate(mysqlMessageHistory{}); err != nil {
+		return err
+	}
+
+	if err := d.GetDb().AutoMigrate(mysqlMessageVersion{}); err != nil {
+		return err
+	}
+
+	if err := d.GetDb().AutoMigrate(mysqlLease{}); err != nil {
+		return err
+	}
+
+	return d.GetDb().AutoMigrate(mysqlAuthUser{}, mysqlAuthToken{}, mysqlAuthSigner{})
 }
 
 func (d Repo) GetDb() *gorm.DB {
@@ -79,6 +135,22 @@ func (t *TxMysqlRepo) AddressRepo() repo.AddressRepo {
 	return newMysqlAddressRepo(t.DB)
 }
 
+func (t *TxMysqlRepo) ApprovalRepo() repo.ApprovalRepo {
+	return newMysqlApprovalRepo(t.DB)
+}
+
+func (t *TxMysqlRepo) TopUpRepo() repo.TopUpRepo {
+	return newMysqlTopUpRepo(t.DB)
+}
+
+func (t *TxMysqlRepo) MessageHistoryRepo() repo.MessageHistoryRepo {
+	return newMysqlMessageHistoryRepo(t.DB)
+}
+
+func (t *TxMysqlRepo) MessageVersionRepo() repo.MessageVersionRepo {
+	return newMysqlMessageVersionRepo(t.DB)
+}
+
 func OpenMysql(cfg *config.MySqlConfig) (repo.Repo, error) {
 	db, err := gorm.Open(mysql.Open(cfg.ConnectionString), &gorm.Config{
 		// Logger: logger.Default.LogMode(logger.Info), // 日志配置
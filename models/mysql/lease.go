@@ -0,0 +1,114 @@
+package mysql
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/models/repo"
+)
+
+type mysqlLease struct {
+	Name     string    `gorm:"column:name;type:varchar(256);primary_key;"` // 主键
+	Holder   string    `gorm:"column:holder;type:varchar(256);NOT NULL"`
+	ExpireAt time.Time `gorm:"column:expire_at;NOT NULL"`
+
+	UpdatedAt time.Time `gorm:"column:updated_at;NOT NULL"` // 更新时间
+}
+
+func (s mysqlLease) Lease() *mtypes.Lease {
+	return &mtypes.Lease{
+		Name:      s.Name,
+		Holder:    s.Holder,
+		ExpireAt:  s.ExpireAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+}
+
+func (s mysqlLease) TableName() string {
+	return "leases"
+}
+
+var _ repo.LeaseRepo = (*mysqlLeaseRepo)(nil)
+
+type mysqlLeaseRepo struct {
+	*gorm.DB
+}
+
+func newMysqlLeaseRepo(db *gorm.DB) mysqlLeaseRepo {
+	return mysqlLeaseRepo{DB: db}
+}
+
+func (s mysqlLeaseRepo) Now() (time.Time, error) {
+	var seconds float64
+	if err := s.DB.Raw("select unix_timestamp(now(6))").Scan(&seconds).Error; err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(0, int64(seconds*float64(time.Second))), nil
+}
+
+func (s mysqlLeaseRepo) AcquireLease(name, holder string, duration time.Duration) (bool, error) {
+	now, err := s.Now()
+	if err != nil {
+		return false, err
+	}
+	expireAt := now.Add(duration)
+	res := s.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&mysqlLease{
+		Name:      name,
+		Holder:    holder,
+		ExpireAt:  expireAt,
+		UpdatedAt: now,
+	})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	if res.RowsAffected > 0 {
+		return true, nil
+	}
+
+	// renew the lease held by self or take over the expired one
+	res = s.DB.Model(&mysqlLease{}).Where("name = ? and (holder = ? or expire_at < ?)", name, holder, now).
+		UpdateColumns(map[string]interface{}{
+			"holder":     holder,
+			"expire_at":  expireAt,
+			"updated_at": now,
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (s mysqlLeaseRepo) ReleaseLease(name, holder string) error {
+	now, err := s.Now()
+	if err != nil {
+		return err
+	}
+	return s.DB.Model(&mysqlLease{}).Where("name = ? and holder = ?", name, holder).
+		UpdateColumns(map[string]interface{}{
+			"expire_at":  now,
+			"updated_at": now,
+		}).Error
+}
+
+func (s mysqlLeaseRepo) GetLease(name string) (*mtypes.Lease, error) {
+	var lease mysqlLease
+	if err := s.DB.Take(&lease, "name = ?", name).Error; err != nil {
+		return nil, err
+	}
+	return lease.Lease(), nil
+}
+
+func (s mysqlLeaseRepo) ListLease(prefix string) ([]*mtypes.Lease, error) {
+	var leases []*mysqlLease
+	if err := s.DB.Find(&leases, "name like ?", prefix+"%").Error; err != nil {
+		return nil, err
+	}
+	result := make([]*mtypes.Lease, 0, len(leases))
+	for _, lease := range leases {
+		result = append(result, lease.Lease())
+	}
+	return result, nil
+}
@@ -0,0 +1,115 @@
+package mysql
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/filecoin-project/venus-messager/models/repo"
+)
+
+func TestLease(t *testing.T) {
+	r, mock, sqlDB := setup(t)
+
+	t.Run("mysql test acquire lease", wrapper(testAcquireLease, r, mock))
+	t.Run("mysql test renew lease", wrapper(testRenewLease, r, mock))
+	t.Run("mysql test acquire lease held by other", wrapper(testAcquireLeaseHeldByOther, r, mock))
+	t.Run("mysql test release lease", wrapper(testReleaseLease, r, mock))
+	t.Run("mysql test get lease", wrapper(testGetLease, r, mock))
+	t.Run("mysql test list lease", wrapper(testListLease, r, mock))
+
+	assert.NoError(t, closeDB(mock, sqlDB))
+}
+
+const (
+	leaseName   = "address/f01000"
+	leaseHolder = "messager-1"
+)
+
+func expectNow(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(regexp.QuoteMeta("select unix_timestamp(now(6))")).
+		WillReturnRows(sqlmock.NewRows([]string{"unix_timestamp(now(6))"}).AddRow(1700000000.5))
+}
+
+func expectInsertLease(mock sqlmock.Sqlmock, rowsAffected int64) {
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `leases` (`name`,`holder`,`expire_at`,`updated_at`) VALUES (?,?,?,?) "+
+		"ON DUPLICATE KEY UPDATE `name`=`name`")).
+		WithArgs(leaseName, leaseHolder, anyTime{}, anyTime{}).
+		WillReturnResult(sqlmock.NewResult(rowsAffected, rowsAffected))
+	mock.ExpectCommit()
+}
+
+func expectRenewLease(mock sqlmock.Sqlmock, rowsAffected int64) {
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `leases` SET `expire_at`=?,`holder`=?,`updated_at`=? "+
+		"WHERE name = ? and (holder = ? or expire_at < ?)")).
+		WithArgs(anyTime{}, leaseHolder, anyTime{}, leaseName, leaseHolder, anyTime{}).
+		WillReturnResult(sqlmock.NewResult(0, rowsAffected))
+	mock.ExpectCommit()
+}
+
+func testAcquireLease(t *testing.T, r repo.Repo, mock sqlmock.Sqlmock) {
+	expectNow(mock)
+	expectInsertLease(mock, 1)
+
+	acquired, err := r.LeaseRepo().AcquireLease(leaseName, leaseHolder, time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+}
+
+func testRenewLease(t *testing.T, r repo.Repo, mock sqlmock.Sqlmock) {
+	expectNow(mock)
+	expectInsertLease(mock, 0)
+	expectRenewLease(mock, 1)
+
+	acquired, err := r.LeaseRepo().AcquireLease(leaseName, leaseHolder, time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+}
+
+func testAcquireLeaseHeldByOther(t *testing.T, r repo.Repo, mock sqlmock.Sqlmock) {
+	expectNow(mock)
+	expectInsertLease(mock, 0)
+	expectRenewLease(mock, 0)
+
+	acquired, err := r.LeaseRepo().AcquireLease(leaseName, leaseHolder, time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, acquired)
+}
+
+func testReleaseLease(t *testing.T, r repo.Repo, mock sqlmock.Sqlmock) {
+	expectNow(mock)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `leases` SET `expire_at`=?,`updated_at`=? WHERE name = ? and holder = ?")).
+		WithArgs(anyTime{}, anyTime{}, leaseName, leaseHolder).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, r.LeaseRepo().ReleaseLease(leaseName, leaseHolder))
+}
+
+func testGetLease(t *testing.T, r repo.Repo, mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `leases` WHERE name = ? LIMIT 1")).
+		WithArgs(leaseName).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "holder"}).AddRow(leaseName, leaseHolder))
+
+	lease, err := r.LeaseRepo().GetLease(leaseName)
+	assert.NoError(t, err)
+	assert.Equal(t, leaseName, lease.Name)
+	assert.Equal(t, leaseHolder, lease.Holder)
+}
+
+func testListLease(t *testing.T, r repo.Repo, mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `leases` WHERE name like ?")).
+		WithArgs("address/%").
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow(leaseName).AddRow("address/f01001"))
+
+	leases, err := r.LeaseRepo().ListLease("address/")
+	assert.NoError(t, err)
+	assert.Len(t, leases, 2)
+	assert.Equal(t, leaseName, leases[0].Name)
+}
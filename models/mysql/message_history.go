@@ -0,0 +1,117 @@
+package mysql
+
+import (
+	"time"
+
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/ipfs/go-cid"
+	"gorm.io/gorm"
+
+	types "github.com/filecoin-project/venus/venus-shared/types/messager"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/models/repo"
+)
+
+type mysqlMessageHistory struct {
+	ID       string             `gorm:"column:id;type:varchar(256);primary_key;"` // 主键
+	MsgID    string             `gorm:"column:msg_id;type:varchar(256);index;NOT NULL"`
+	OldState types.MessageState `gorm:"column:old_state;type:int;NOT NULL"`
+	NewState types.MessageState `gorm:"column:new_state;type:int;NOT NULL"`
+
+	GasLimit   int64          `gorm:"column:gas_limit;type:bigint;NOT NULL"`
+	GasFeeCap  mtypes.Int     `gorm:"column:gas_fee_cap;type:varchar(256);NOT NULL"`
+	GasPremium mtypes.Int     `gorm:"column:gas_premium;type:varchar(256);NOT NULL"`
+	SignedCid  string         `gorm:"column:signed_cid;type:varchar(256)"`
+	Error      string         `gorm:"column:error;type:text"`
+	Height     int64          `gorm:"column:height;type:bigint;NOT NULL"`
+	Trigger    mtypes.Trigger `gorm:"column:trigger_by;type:varchar(64);NOT NULL"`
+
+	CreatedAt time.Time `gorm:"column:created_at;index;NOT NULL"` // 创建时间
+}
+
+func fromMessageHistory(history *mtypes.MessageHistory) *mysqlMessageHistory {
+	s := &mysqlMessageHistory{
+		ID:         history.ID,
+		MsgID:      history.MsgID,
+		OldState:   history.OldState,
+		NewState:   history.NewState,
+		GasLimit:   history.GasLimit,
+		GasFeeCap:  mtypes.SafeFromGo(history.GasFeeCap.Int),
+		GasPremium: mtypes.SafeFromGo(history.GasPremium.Int),
+		Error:      history.Error,
+		Height:     history.Height,
+		Trigger:    history.Trigger,
+		CreatedAt:  history.CreatedAt,
+	}
+	if history.SignedCid != nil {
+		s.SignedCid = history.SignedCid.String()
+	}
+	return s
+}
+
+func (s mysqlMessageHistory) MessageHistory() (*mtypes.MessageHistory, error) {
+	history := &mtypes.MessageHistory{
+		ID:         s.ID,
+		MsgID:      s.MsgID,
+		OldState:   s.OldState,
+		NewState:   s.NewState,
+		GasLimit:   s.GasLimit,
+		GasFeeCap:  big.Int(mtypes.SafeFromGo(s.GasFeeCap.Int)),
+		GasPremium: big.Int(mtypes.SafeFromGo(s.GasPremium.Int)),
+		Error:      s.Error,
+		Height:     s.Height,
+		Trigger:    s.Trigger,
+		CreatedAt:  s.CreatedAt,
+	}
+	if len(s.SignedCid) != 0 {
+		signedCid, err := cid.Decode(s.SignedCid)
+		if err != nil {
+			return nil, err
+		}
+		history.SignedCid = &signedCid
+	}
+	return history, nil
+}
+
+func (s mysqlMessageHistory) TableName() string {
+	return "message_histories"
+}
+
+var _ repo.MessageHistoryRepo = (*mysqlMessageHistoryRepo)(nil)
+
+type mysqlMessageHistoryRepo struct {
+	*gorm.DB
+}
+
+func newMysqlMessageHistoryRepo(db *gorm.DB) mysqlMessageHistoryRepo {
+	return mysqlMessageHistoryRepo{DB: db}
+}
+
+func (s mysqlMessageHistoryRepo) CreateMessageHistory(history ...*mtypes.MessageHistory) error {
+	if len(history) == 0 {
+		return nil
+	}
+	list := make([]*mysqlMessageHistory, 0, len(history))
+	for _, h := range history {
+		list = append(list, fromMessageHistory(h))
+	}
+	return s.DB.Create(list).Error
+}
+
+func (s mysqlMessageHistoryRepo) ListMessageHistory(msgID string) ([]*mtypes.MessageHistory, error) {
+	var internalHistory []*mysqlMessageHistory
+	if err := s.DB.Where("msg_id = ?", msgID).Order("created_at").Find(&internalHistory).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]*mtypes.MessageHistory, 0, len(internalHistory))
+	for _, h := range internalHistory {
+		history, err := h.MessageHistory()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, history)
+	}
+	return result, nil
+}
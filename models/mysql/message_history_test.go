@@ -0,0 +1,71 @@
+package mysql
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/venus/venus-shared/testutil"
+	venusTypes "github.com/filecoin-project/venus/venus-shared/types"
+	types "github.com/filecoin-project/venus/venus-shared/types/messager"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/models/repo"
+)
+
+func TestMessageHistory(t *testing.T) {
+	r, mock, sqlDB := setup(t)
+
+	t.Run("mysql test create message history", wrapper(testCreateMessageHistory, r, mock))
+	t.Run("mysql test list message history", wrapper(testListMessageHistory, r, mock))
+
+	assert.NoError(t, closeDB(mock, sqlDB))
+}
+
+func testCreateMessageHistory(t *testing.T, r repo.Repo, mock sqlmock.Sqlmock) {
+	signedCid := testutil.CidProvider(32)(t)
+	history := &mtypes.MessageHistory{
+		ID:         venusTypes.NewUUID().String(),
+		MsgID:      venusTypes.NewUUID().String(),
+		OldState:   types.UnFillMsg,
+		NewState:   types.FillMsg,
+		GasLimit:   10000,
+		GasFeeCap:  big.NewInt(100),
+		GasPremium: big.NewInt(10),
+		SignedCid:  &signedCid,
+		Height:     100,
+		Trigger:    mtypes.TriggerSelector,
+		CreatedAt:  time.Now(),
+	}
+
+	insertSql, insertArgs := genInsertSQL(fromMessageHistory(history))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(insertSql)).
+		WithArgs(insertArgs...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, r.MessageHistoryRepo().CreateMessageHistory(history))
+	// nothing to create
+	assert.NoError(t, r.MessageHistoryRepo().CreateMessageHistory())
+}
+
+func testListMessageHistory(t *testing.T, r repo.Repo, mock sqlmock.Sqlmock) {
+	msgID := venusTypes.NewUUID().String()
+	signedCid := testutil.CidProvider(32)(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `message_histories` WHERE msg_id = ? ORDER BY created_at")).
+		WithArgs(msgID).
+		WillReturnRows(sqlmock.NewRows([]string{"msg_id", "old_state", "new_state", "signed_cid"}).
+			AddRow(msgID, types.UnFillMsg, types.FillMsg, signedCid.String()).
+			AddRow(msgID, types.FillMsg, types.OnChainMsg, signedCid.String()))
+
+	histories, err := r.MessageHistoryRepo().ListMessageHistory(msgID)
+	assert.NoError(t, err)
+	assert.Len(t, histories, 2)
+	assert.Equal(t, types.OnChainMsg, histories[1].NewState)
+	assert.Equal(t, signedCid, *histories[1].SignedCid)
+}
@@ -0,0 +1,122 @@
+package mysql
+
+import (
+	"time"
+
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/crypto"
+	"github.com/ipfs/go-cid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/models/repo"
+)
+
+type mysqlMessageVersion struct {
+	SignedCid  string             `gorm:"column:signed_cid;type:varchar(256);primary_key;"` // 主键
+	MsgID      string             `gorm:"column:msg_id;type:varchar(256);index;NOT NULL"`
+	GasLimit   int64              `gorm:"column:gas_limit;type:bigint;NOT NULL"`
+	GasFeeCap  mtypes.Int         `gorm:"column:gas_fee_cap;type:varchar(256);NOT NULL"`
+	GasPremium mtypes.Int         `gorm:"column:gas_premium;type:varchar(256);NOT NULL"`
+	Signature  *repo.SqlSignature `gorm:"column:signed_data;type:blob;"`
+	Included   bool               `gorm:"column:included;NOT NULL"`
+	Conflict   bool               `gorm:"column:conflict;NOT NULL;default:false"`
+
+	CreatedAt time.Time `gorm:"column:created_at;index;NOT NULL"` // 创建时间
+}
+
+func fromMessageVersion(version *mtypes.MessageVersion) *mysqlMessageVersion {
+	return &mysqlMessageVersion{
+		SignedCid:  version.SignedCid.String(),
+		MsgID:      version.MsgID,
+		GasLimit:   version.GasLimit,
+		GasFeeCap:  mtypes.SafeFromGo(version.GasFeeCap.Int),
+		GasPremium: mtypes.SafeFromGo(version.GasPremium.Int),
+		Signature:  (*repo.SqlSignature)(version.Signature),
+		Included:   version.Included,
+		Conflict:   version.Conflict,
+		CreatedAt:  version.CreatedAt,
+	}
+}
+
+func (s mysqlMessageVersion) MessageVersion() (*mtypes.MessageVersion, error) {
+	signedCid, err := cid.Decode(s.SignedCid)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mtypes.MessageVersion{
+		SignedCid:  signedCid,
+		MsgID:      s.MsgID,
+		GasLimit:   s.GasLimit,
+		GasFeeCap:  big.Int(mtypes.SafeFromGo(s.GasFeeCap.Int)),
+		GasPremium: big.Int(mtypes.SafeFromGo(s.GasPremium.Int)),
+		Signature:  (*crypto.Signature)(s.Signature),
+		Included:   s.Included,
+		Conflict:   s.Conflict,
+		CreatedAt:  s.CreatedAt,
+	}, nil
+}
+
+func (s mysqlMessageVersion) TableName() string {
+	return "message_versions"
+}
+
+var _ repo.MessageVersionRepo = (*mysqlMessageVersionRepo)(nil)
+
+type mysqlMessageVersionRepo struct {
+	*gorm.DB
+}
+
+func newMysqlMessageVersionRepo(db *gorm.DB) mysqlMessageVersionRepo {
+	return mysqlMessageVersionRepo{DB: db}
+}
+
+func (s mysqlMessageVersionRepo) SaveMessageVersion(versions ...*mtypes.MessageVersion) error {
+	if len(versions) == 0 {
+		return nil
+	}
+	list := make([]*mysqlMessageVersion, 0, len(versions))
+	for _, v := range versions {
+		list = append(list, fromMessageVersion(v))
+	}
+	return s.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(list).Error
+}
+
+func (s mysqlMessageVersionRepo) GetMessageVersion(signedCid cid.Cid) (*mtypes.MessageVersion, error) {
+	var version mysqlMessageVersion
+	if err := s.DB.Take(&version, "signed_cid = ?", signedCid.String()).Error; err != nil {
+		return nil, err
+	}
+	return version.MessageVersion()
+}
+
+func (s mysqlMessageVersionRepo) ListMessageVersion(msgID string) ([]*mtypes.MessageVersion, error) {
+	var internalVersions []*mysqlMessageVersion
+	if err := s.DB.Where("msg_id = ?", msgID).Order("created_at").Find(&internalVersions).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]*mtypes.MessageVersion, 0, len(internalVersions))
+	for _, v := range internalVersions {
+		version, err := v.MessageVersion()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, version)
+	}
+	return result, nil
+}
+
+func (s mysqlMessageVersionRepo) SetIncluded(msgID string, signedCid cid.Cid) error {
+	if err := s.DB.Model(&mysqlMessageVersion{}).Where("msg_id = ? and included = ?", msgID, true).
+		UpdateColumn("included", false).Error; err != nil {
+		return err
+	}
+	if !signedCid.Defined() {
+		return nil
+	}
+	return s.DB.Model(&mysqlMessageVersion{}).Where("msg_id = ? and signed_cid = ?", msgID, signedCid.String()).
+		UpdateColumn("included", true).Error
+}
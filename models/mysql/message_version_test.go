@@ -0,0 +1,117 @@
+package mysql
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/crypto"
+	"github.com/filecoin-project/venus/venus-shared/testutil"
+	venusTypes "github.com/filecoin-project/venus/venus-shared/types"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/models/repo"
+)
+
+func TestMessageVersion(t *testing.T) {
+	r, mock, sqlDB := setup(t)
+
+	t.Run("mysql test save message version", wrapper(testSaveMessageVersion, r, mock))
+	t.Run("mysql test get message version", wrapper(testGetMessageVersion, r, mock))
+	t.Run("mysql test list message version", wrapper(testListMessageVersion, r, mock))
+	t.Run("mysql test set included", wrapper(testSetIncluded, r, mock))
+	t.Run("mysql test clear included", wrapper(testClearIncluded, r, mock))
+
+	assert.NoError(t, closeDB(mock, sqlDB))
+}
+
+func testSaveMessageVersion(t *testing.T, r repo.Repo, mock sqlmock.Sqlmock) {
+	version := &mtypes.MessageVersion{
+		SignedCid:  testutil.CidProvider(32)(t),
+		MsgID:      venusTypes.NewUUID().String(),
+		GasLimit:   10000,
+		GasFeeCap:  big.NewInt(100),
+		GasPremium: big.NewInt(10),
+		Signature:  &crypto.Signature{Type: crypto.SigTypeSecp256k1, Data: []byte("signature")},
+		CreatedAt:  time.Now(),
+	}
+
+	insertSql, insertArgs := genInsertSQL(fromMessageVersion(version))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(insertSql + " ON DUPLICATE KEY UPDATE `signed_cid`=`signed_cid`")).
+		WithArgs(insertArgs...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, r.MessageVersionRepo().SaveMessageVersion(version))
+	// nothing to save
+	assert.NoError(t, r.MessageVersionRepo().SaveMessageVersion())
+}
+
+func testGetMessageVersion(t *testing.T, r repo.Repo, mock sqlmock.Sqlmock) {
+	signedCid := testutil.CidProvider(32)(t)
+	msgID := venusTypes.NewUUID().String()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `message_versions` WHERE signed_cid = ? LIMIT 1")).
+		WithArgs(signedCid.String()).
+		WillReturnRows(sqlmock.NewRows([]string{"signed_cid", "msg_id", "conflict"}).AddRow(signedCid.String(), msgID, true))
+
+	version, err := r.MessageVersionRepo().GetMessageVersion(signedCid)
+	assert.NoError(t, err)
+	assert.Equal(t, signedCid, version.SignedCid)
+	assert.Equal(t, msgID, version.MsgID)
+	assert.True(t, version.Conflict)
+}
+
+func testListMessageVersion(t *testing.T, r repo.Repo, mock sqlmock.Sqlmock) {
+	msgID := venusTypes.NewUUID().String()
+	cids := []cid.Cid{testutil.CidProvider(32)(t), testutil.CidProvider(32)(t)}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `message_versions` WHERE msg_id = ? ORDER BY created_at")).
+		WithArgs(msgID).
+		WillReturnRows(sqlmock.NewRows([]string{"signed_cid", "msg_id", "included"}).
+			AddRow(cids[0].String(), msgID, false).
+			AddRow(cids[1].String(), msgID, true))
+
+	versions, err := r.MessageVersionRepo().ListMessageVersion(msgID)
+	assert.NoError(t, err)
+	assert.Len(t, versions, 2)
+	for i, version := range versions {
+		assert.Equal(t, cids[i], version.SignedCid)
+		assert.Equal(t, i == 1, version.Included)
+	}
+}
+
+func testSetIncluded(t *testing.T, r repo.Repo, mock sqlmock.Sqlmock) {
+	msgID := venusTypes.NewUUID().String()
+	signedCid := testutil.CidProvider(32)(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `message_versions` SET `included`=? WHERE msg_id = ? and included = ?")).
+		WithArgs(false, msgID, true).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `message_versions` SET `included`=? WHERE msg_id = ? and signed_cid = ?")).
+		WithArgs(true, msgID, signedCid.String()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, r.MessageVersionRepo().SetIncluded(msgID, signedCid))
+}
+
+func testClearIncluded(t *testing.T, r repo.Repo, mock sqlmock.Sqlmock) {
+	msgID := venusTypes.NewUUID().String()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `message_versions` SET `included`=? WHERE msg_id = ? and included = ?")).
+		WithArgs(false, msgID, true).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, r.MessageVersionRepo().SetIncluded(msgID, cid.Undef))
+}
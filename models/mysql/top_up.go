@@ -0,0 +1,115 @@
+package mysql
+
+import (
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/big"
+	"gorm.io/gorm"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/models/repo"
+)
+
+type mysqlTopUp struct {
+	ID       string     `gorm:"column:id;type:varchar(256);primary_key;"` // 主键
+	Addr     string     `gorm:"column:addr;type:varchar(256);index;NOT NULL"`
+	Treasury string     `gorm:"column:treasury;type:varchar(256);index;NOT NULL"`
+	MsgID    string     `gorm:"column:msg_id;type:varchar(256);NOT NULL"`
+	Value    mtypes.Int `gorm:"column:value;type:varchar(256);NOT NULL"`
+	Balance  mtypes.Int `gorm:"column:balance;type:varchar(256);NOT NULL"`
+
+	CreatedAt time.Time `gorm:"column:created_at;index;NOT NULL"` // 创建时间
+}
+
+func fromTopUp(record *mtypes.TopUpRecord) *mysqlTopUp {
+	return &mysqlTopUp{
+		ID:        record.ID,
+		Addr:      record.Addr.String(),
+		Treasury:  record.Treasury.String(),
+		MsgID:     record.MsgID,
+		Value:     mtypes.SafeFromGo(record.Value.Int),
+		Balance:   mtypes.SafeFromGo(record.Balance.Int),
+		CreatedAt: record.CreatedAt,
+	}
+}
+
+func (s mysqlTopUp) TopUp() (*mtypes.TopUpRecord, error) {
+	addr, err := address.NewFromString(s.Addr)
+	if err != nil {
+		return nil, err
+	}
+	treasury, err := address.NewFromString(s.Treasury)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mtypes.TopUpRecord{
+		ID:        s.ID,
+		Addr:      addr,
+		Treasury:  treasury,
+		MsgID:     s.MsgID,
+		Value:     big.Int(mtypes.SafeFromGo(s.Value.Int)),
+		Balance:   big.Int(mtypes.SafeFromGo(s.Balance.Int)),
+		CreatedAt: s.CreatedAt,
+	}, nil
+}
+
+func (s mysqlTopUp) TableName() string {
+	return "top_ups"
+}
+
+var _ repo.TopUpRepo = (*mysqlTopUpRepo)(nil)
+
+type mysqlTopUpRepo struct {
+	*gorm.DB
+}
+
+func newMysqlTopUpRepo(db *gorm.DB) mysqlTopUpRepo {
+	return mysqlTopUpRepo{DB: db}
+}
+
+func (s mysqlTopUpRepo) CreateTopUp(record *mtypes.TopUpRecord) error {
+	return s.DB.Create(fromTopUp(record)).Error
+}
+
+func (s mysqlTopUpRepo) GetLatestTopUp(addr address.Address) (*mtypes.TopUpRecord, error) {
+	var record mysqlTopUp
+	if err := s.DB.Order("created_at DESC").Take(&record, "addr = ?", addr.String()).Error; err != nil {
+		return nil, err
+	}
+	return record.TopUp()
+}
+
+func (s mysqlTopUpRepo) ListTopUp(addr address.Address) ([]*mtypes.TopUpRecord, error) {
+	query := s.DB
+	if addr != address.Undef {
+		query = query.Where("addr = ?", addr.String())
+	}
+
+	var records []*mysqlTopUp
+	if err := query.Order("created_at").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	return toTopUps(records)
+}
+
+func (s mysqlTopUpRepo) ListTopUpByTreasury(treasury address.Address, since time.Time) ([]*mtypes.TopUpRecord, error) {
+	var records []*mysqlTopUp
+	if err := s.DB.Order("created_at").Find(&records, "treasury = ? and created_at >= ?", treasury.String(), since).Error; err != nil {
+		return nil, err
+	}
+	return toTopUps(records)
+}
+
+func toTopUps(records []*mysqlTopUp) ([]*mtypes.TopUpRecord, error) {
+	result := make([]*mtypes.TopUpRecord, 0, len(records))
+	for _, r := range records {
+		record, err := r.TopUp()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, record)
+	}
+	return result, nil
+}
@@ -0,0 +1,102 @@
+package mysql
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/venus/venus-shared/testutil"
+	venusTypes "github.com/filecoin-project/venus/venus-shared/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/models/repo"
+)
+
+func TestTopUp(t *testing.T) {
+	r, mock, sqlDB := setup(t)
+
+	t.Run("mysql test create top up", wrapper(testCreateTopUp, r, mock))
+	t.Run("mysql test get latest top up", wrapper(testGetLatestTopUp, r, mock))
+	t.Run("mysql test list top up", wrapper(testListTopUp, r, mock))
+	t.Run("mysql test list top up by treasury", wrapper(testListTopUpByTreasury, r, mock))
+
+	assert.NoError(t, closeDB(mock, sqlDB))
+}
+
+func testCreateTopUp(t *testing.T, r repo.Repo, mock sqlmock.Sqlmock) {
+	record := &mtypes.TopUpRecord{
+		ID:        venusTypes.NewUUID().String(),
+		Addr:      testutil.AddressProvider()(t),
+		Treasury:  testutil.AddressProvider()(t),
+		MsgID:     venusTypes.NewUUID().String(),
+		Value:     big.NewInt(1000),
+		Balance:   big.NewInt(10),
+		CreatedAt: time.Now(),
+	}
+
+	insertSql, insertArgs := genInsertSQL(fromTopUp(record))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(insertSql)).
+		WithArgs(insertArgs...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, r.TopUpRepo().CreateTopUp(record))
+}
+
+func testGetLatestTopUp(t *testing.T, r repo.Repo, mock sqlmock.Sqlmock) {
+	addr := testutil.AddressProvider()(t)
+	treasury := testutil.AddressProvider()(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `top_ups` WHERE addr = ? ORDER BY created_at DESC LIMIT 1")).
+		WithArgs(addr.String()).
+		WillReturnRows(sqlmock.NewRows([]string{"addr", "treasury", "value"}).AddRow(addr.String(), treasury.String(), "1000"))
+
+	record, err := r.TopUpRepo().GetLatestTopUp(addr)
+	assert.NoError(t, err)
+	assert.Equal(t, addr, record.Addr)
+	assert.Equal(t, treasury, record.Treasury)
+	assert.Equal(t, big.NewInt(1000), record.Value)
+}
+
+func testListTopUp(t *testing.T, r repo.Repo, mock sqlmock.Sqlmock) {
+	addr := testutil.AddressProvider()(t)
+	treasury := testutil.AddressProvider()(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `top_ups` WHERE addr = ? ORDER BY created_at")).
+		WithArgs(addr.String()).
+		WillReturnRows(sqlmock.NewRows([]string{"addr", "treasury"}).
+			AddRow(addr.String(), treasury.String()).
+			AddRow(addr.String(), treasury.String()))
+
+	records, err := r.TopUpRepo().ListTopUp(addr)
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+
+	// not filter by address
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `top_ups` ORDER BY created_at")).
+		WillReturnRows(sqlmock.NewRows([]string{"addr", "treasury"}).AddRow(addr.String(), treasury.String()))
+
+	records, err = r.TopUpRepo().ListTopUp(address.Undef)
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+}
+
+func testListTopUpByTreasury(t *testing.T, r repo.Repo, mock sqlmock.Sqlmock) {
+	addr := testutil.AddressProvider()(t)
+	treasury := testutil.AddressProvider()(t)
+	since := time.Now().Add(-time.Hour)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `top_ups` WHERE treasury = ? and created_at >= ? ORDER BY created_at")).
+		WithArgs(treasury.String(), since).
+		WillReturnRows(sqlmock.NewRows([]string{"addr", "treasury"}).AddRow(addr.String(), treasury.String()))
+
+	records, err := r.TopUpRepo().ListTopUpByTreasury(treasury, since)
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, treasury, records[0].Treasury)
+}
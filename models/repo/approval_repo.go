@@ -0,0 +1,17 @@
+package repo
+
+import (
+	"github.com/filecoin-project/go-address"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+)
+
+type ApprovalRepo interface {
+	CreateApproval(approval *mtypes.MessageApproval) error
+	GetApproval(msgID string) (*mtypes.MessageApproval, error)
+	UpdateApproval(approval *mtypes.MessageApproval) error
+	// ListApproval list approvals by state, when `from` is address.Undef, list all addresses
+	ListApproval(from address.Address, state mtypes.ApprovalState) ([]*mtypes.MessageApproval, error)
+	// ListPendingMsgID returns the id of messages of `from` which still wait for approval
+	ListPendingMsgID(from address.Address) ([]string, error)
+}
@@ -0,0 +1,9 @@
+package repo
+
+import "github.com/filecoin-project/venus-messager/models/mtypes"
+
+type AuditRepo interface {
+	CreateAuditLog(log *mtypes.AuditLog) error
+	// ListAuditLog returns audit logs match the query, the latest first
+	ListAuditLog(query *mtypes.AuditQuery) ([]*mtypes.AuditLog, error)
+}
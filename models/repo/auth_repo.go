@@ -0,0 +1,29 @@
+package repo
+
+import (
+	"github.com/filecoin-project/go-address"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+)
+
+// AuthRepo stores the users, tokens and signer bindings of the local auth
+type AuthRepo interface {
+	CreateUser(user *mtypes.AuthUser) error
+	// GetUser returns the user with its signers
+	GetUser(name string) (*mtypes.AuthUser, error)
+	// ListUsers returns all users with their signers, sorted by name
+	ListUsers() ([]*mtypes.AuthUser, error)
+	// DeleteUser deletes the user with its tokens and signers
+	DeleteUser(name string) error
+
+	CreateToken(token *mtypes.AuthToken) error
+	GetToken(tokenHash string) (*mtypes.AuthToken, error)
+	// ListTokens list tokens of the user, when name is empty, list all
+	ListTokens(name string) ([]*mtypes.AuthToken, error)
+	DeleteToken(tokenHash string) error
+
+	BindSigners(name string, signers []address.Address) error
+	UnbindSigners(name string, signers []address.Address) error
+	// ListSignerUsers returns the names of users bound to signer
+	ListSignerUsers(signer address.Address) ([]string, error)
+}
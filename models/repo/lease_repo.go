@@ -0,0 +1,20 @@
+package repo
+
+import (
+	"time"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+)
+
+type LeaseRepo interface {
+	// Now returns the time of database, leases are checked by it, so they don't depend on the clocks of instances
+	Now() (time.Time, error)
+	// AcquireLease acquire or renew lease `name` for holder, it expires after duration from the time of database,
+	// it fails when the lease is held by others and not expired
+	AcquireLease(name, holder string, duration time.Duration) (bool, error)
+	// ReleaseLease make the lease expire immediately if it is held by holder
+	ReleaseLease(name, holder string) error
+	GetLease(name string) (*mtypes.Lease, error)
+	// ListLease list leases whose name starts with prefix
+	ListLease(prefix string) ([]*mtypes.Lease, error)
+}
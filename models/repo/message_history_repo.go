@@ -0,0 +1,11 @@
+package repo
+
+import (
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+)
+
+type MessageHistoryRepo interface {
+	CreateMessageHistory(history ...*mtypes.MessageHistory) error
+	// ListMessageHistory list the history of message in time order
+	ListMessageHistory(msgID string) ([]*mtypes.MessageHistory, error)
+}
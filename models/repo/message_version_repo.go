@@ -0,0 +1,17 @@
+package repo
+
+import (
+	"github.com/ipfs/go-cid"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+)
+
+type MessageVersionRepo interface {
+	// SaveMessageVersion save versions of message, the version which already exist will be ignored
+	SaveMessageVersion(versions ...*mtypes.MessageVersion) error
+	GetMessageVersion(signedCid cid.Cid) (*mtypes.MessageVersion, error)
+	// ListMessageVersion list the versions of message in time order
+	ListMessageVersion(msgID string) ([]*mtypes.MessageVersion, error)
+	// SetIncluded mark signedCid as the included version of message, cid.Undef means none is included
+	SetIncluded(msgID string, signedCid cid.Cid) error
+}
@@ -26,11 +26,22 @@ type Repo interface {
 	AddressRepo() AddressRepo
 	SharedParamsRepo() SharedParamsRepo
 	NodeRepo() NodeRepo
+	ApprovalRepo() ApprovalRepo
+	TopUpRepo() TopUpRepo
+	AuditRepo() AuditRepo
+	MessageHistoryRepo() MessageHistoryRepo
+	MessageVersionRepo() MessageVersionRepo
+	LeaseRepo() LeaseRepo
+	AuthRepo() AuthRepo
 }
 
 type TxRepo interface {
 	MessageRepo() MessageRepo
 	AddressRepo() AddressRepo
+	ApprovalRepo() ApprovalRepo
+	TopUpRepo() TopUpRepo
+	MessageHistoryRepo() MessageHistoryRepo
+	MessageVersionRepo() MessageVersionRepo
 }
 
 type ISqlField interface {
@@ -0,0 +1,19 @@
+package repo
+
+import (
+	"time"
+
+	"github.com/filecoin-project/go-address"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+)
+
+type TopUpRepo interface {
+	CreateTopUp(record *mtypes.TopUpRecord) error
+	// GetLatestTopUp returns the latest top up record of addr
+	GetLatestTopUp(addr address.Address) (*mtypes.TopUpRecord, error)
+	// ListTopUp list top up records of addr, when addr is address.Undef, list all
+	ListTopUp(addr address.Address) ([]*mtypes.TopUpRecord, error)
+	// ListTopUpByTreasury list the top up records paid by treasury after `since`
+	ListTopUpByTreasury(treasury address.Address, since time.Time) ([]*mtypes.TopUpRecord, error)
+}
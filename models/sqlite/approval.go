@@ -0,0 +1,124 @@
+package sqlite
+
+import (
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"gorm.io/gorm"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/models/repo"
+)
+
+type sqliteApproval struct {
+	MsgID     string               `gorm:"column:msg_id;type:varchar(256);primary_key;"` // 主键
+	From      string               `gorm:"column:from_addr;type:varchar(256);index;NOT NULL"`
+	Reason    string               `gorm:"column:reason;type:varchar(256);NOT NULL"`
+	Requester string               `gorm:"column:requester;type:varchar(256);NOT NULL"`
+	State     mtypes.ApprovalState `gorm:"column:state;type:int;index;NOT NULL"`
+	Approver  string               `gorm:"column:approver;type:varchar(256)"`
+	Comment   string               `gorm:"column:comment;type:text"`
+
+	CreatedAt time.Time `gorm:"column:created_at;index;NOT NULL"` // 创建时间
+	UpdatedAt time.Time `gorm:"column:updated_at;index;NOT NULL"` // 更新时间
+}
+
+func fromApproval(approval *mtypes.MessageApproval) *sqliteApproval {
+	return &sqliteApproval{
+		MsgID:     approval.MsgID,
+		From:      approval.From.String(),
+		Reason:    approval.Reason,
+		Requester: approval.Requester,
+		State:     approval.State,
+		Approver:  approval.Approver,
+		Comment:   approval.Comment,
+		CreatedAt: approval.CreatedAt,
+		UpdatedAt: approval.UpdatedAt,
+	}
+}
+
+func (s sqliteApproval) Approval() (*mtypes.MessageApproval, error) {
+	from, err := address.NewFromString(s.From)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mtypes.MessageApproval{
+		MsgID:     s.MsgID,
+		From:      from,
+		Reason:    s.Reason,
+		Requester: s.Requester,
+		State:     s.State,
+		Approver:  s.Approver,
+		Comment:   s.Comment,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}, nil
+}
+
+func (s sqliteApproval) TableName() string {
+	return "message_approvals"
+}
+
+var _ repo.ApprovalRepo = (*sqliteApprovalRepo)(nil)
+
+type sqliteApprovalRepo struct {
+	*gorm.DB
+}
+
+func newSqliteApprovalRepo(db *gorm.DB) sqliteApprovalRepo {
+	return sqliteApprovalRepo{DB: db}
+}
+
+func (s sqliteApprovalRepo) CreateApproval(approval *mtypes.MessageApproval) error {
+	return s.DB.Create(fromApproval(approval)).Error
+}
+
+func (s sqliteApprovalRepo) GetApproval(msgID string) (*mtypes.MessageApproval, error) {
+	var approval sqliteApproval
+	if err := s.DB.Take(&approval, "msg_id = ?", msgID).Error; err != nil {
+		return nil, err
+	}
+	return approval.Approval()
+}
+
+func (s sqliteApprovalRepo) UpdateApproval(approval *mtypes.MessageApproval) error {
+	return s.DB.Model(&sqliteApproval{}).Where("msg_id = ?", approval.MsgID).
+		Updates(map[string]interface{}{
+			"state":      approval.State,
+			"approver":   approval.Approver,
+			"comment":    approval.Comment,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+func (s sqliteApprovalRepo) ListApproval(from address.Address, state mtypes.ApprovalState) ([]*mtypes.MessageApproval, error) {
+	query := s.DB.Where("state = ?", state)
+	if from != address.Undef {
+		query = query.Where("from_addr = ?", from.String())
+	}
+
+	var internalApprovals []*sqliteApproval
+	if err := query.Order("created_at").Find(&internalApprovals).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]*mtypes.MessageApproval, 0, len(internalApprovals))
+	for _, a := range internalApprovals {
+		approval, err := a.Approval()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, approval)
+	}
+	return result, nil
+}
+
+func (s sqliteApprovalRepo) ListPendingMsgID(from address.Address) ([]string, error) {
+	var ids []string
+	if err := s.DB.Model(&sqliteApproval{}).Where("from_addr = ? and state = ?", from.String(), mtypes.ApprovalPending).
+		Pluck("msg_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
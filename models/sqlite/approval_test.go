@@ -0,0 +1,92 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"github.com/filecoin-project/go-address"
+	venustypes "github.com/filecoin-project/venus/venus-shared/types"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/testhelper"
+)
+
+func randApproval(from address.Address) *mtypes.MessageApproval {
+	return &mtypes.MessageApproval{
+		MsgID:     venustypes.NewUUID().String(),
+		From:      from,
+		Reason:    "sensitive method WithdrawBalance",
+		Requester: "pusher",
+		State:     mtypes.ApprovalPending,
+		CreatedAt: time.Now().Round(time.Second),
+		UpdatedAt: time.Now().Round(time.Second),
+	}
+}
+
+func TestApproval(t *testing.T) {
+	approvalRepo := setupRepo(t).ApprovalRepo()
+
+	addrs := testhelper.RandAddresses(t, 2)
+	approval := randApproval(addrs[0])
+	approval2 := randApproval(addrs[0])
+	approval3 := randApproval(addrs[1])
+
+	t.Run("create approval", func(t *testing.T) {
+		assert.NoError(t, approvalRepo.CreateApproval(approval))
+		assert.NoError(t, approvalRepo.CreateApproval(approval2))
+		assert.NoError(t, approvalRepo.CreateApproval(approval3))
+	})
+
+	t.Run("get approval", func(t *testing.T) {
+		res, err := approvalRepo.GetApproval(approval.MsgID)
+		assert.NoError(t, err)
+		assert.Equal(t, approval.From, res.From)
+		assert.Equal(t, approval.Requester, res.Requester)
+		assert.Equal(t, mtypes.ApprovalPending, res.State)
+
+		_, err = approvalRepo.GetApproval(venustypes.NewUUID().String())
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+	})
+
+	t.Run("list pending msg id", func(t *testing.T) {
+		ids, err := approvalRepo.ListPendingMsgID(addrs[0])
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{approval.MsgID, approval2.MsgID}, ids)
+	})
+
+	t.Run("update approval", func(t *testing.T) {
+		approval.State = mtypes.ApprovalApproved
+		approval.Approver = "approver"
+		approval.Comment = "ok"
+		assert.NoError(t, approvalRepo.UpdateApproval(approval))
+
+		res, err := approvalRepo.GetApproval(approval.MsgID)
+		assert.NoError(t, err)
+		assert.Equal(t, mtypes.ApprovalApproved, res.State)
+		assert.Equal(t, "approver", res.Approver)
+		assert.Equal(t, "ok", res.Comment)
+
+		ids, err := approvalRepo.ListPendingMsgID(addrs[0])
+		assert.NoError(t, err)
+		assert.Equal(t, []string{approval2.MsgID}, ids)
+	})
+
+	t.Run("list approval", func(t *testing.T) {
+		list, err := approvalRepo.ListApproval(address.Undef, mtypes.ApprovalPending)
+		assert.NoError(t, err)
+		assert.Len(t, list, 2)
+
+		list, err = approvalRepo.ListApproval(addrs[1], mtypes.ApprovalPending)
+		assert.NoError(t, err)
+		assert.Len(t, list, 1)
+		assert.Equal(t, approval3.MsgID, list[0].MsgID)
+
+		list, err = approvalRepo.ListApproval(addrs[0], mtypes.ApprovalApproved)
+		assert.NoError(t, err)
+		assert.Len(t, list, 1)
+		assert.Equal(t, approval.MsgID, list[0].MsgID)
+	})
+}
@@ -0,0 +1,78 @@
+package sqlite
+
+import (
+	"time"
+
+	"github.com/hunjixin/automapper"
+	"gorm.io/gorm"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/models/repo"
+)
+
+type sqliteAuditLog struct {
+	ID        string    `gorm:"column:id;type:varchar(256);primary_key;"` // 主键
+	Account   string    `gorm:"column:account;type:varchar(256);index;NOT NULL"`
+	Method    string    `gorm:"column:method;type:varchar(256);index;NOT NULL"`
+	Params    string    `gorm:"column:params;type:text"`
+	Previous  string    `gorm:"column:previous;type:text"`
+	Result    string    `gorm:"column:result;type:text"`
+	Error     string    `gorm:"column:error;type:text"`
+	CreatedAt time.Time `gorm:"column:created_at;index;NOT NULL"` // 创建时间
+}
+
+func fromAuditLog(log *mtypes.AuditLog) *sqliteAuditLog {
+	return automapper.MustMapper(log, TSqliteAuditLog).(*sqliteAuditLog)
+}
+
+func (s sqliteAuditLog) AuditLog() *mtypes.AuditLog {
+	return automapper.MustMapper(&s, TAuditLog).(*mtypes.AuditLog)
+}
+
+func (s sqliteAuditLog) TableName() string {
+	return "audit_logs"
+}
+
+var _ repo.AuditRepo = (*sqliteAuditRepo)(nil)
+
+type sqliteAuditRepo struct {
+	*gorm.DB
+}
+
+func newSqliteAuditRepo(db *gorm.DB) sqliteAuditRepo {
+	return sqliteAuditRepo{DB: db}
+}
+
+func (s sqliteAuditRepo) CreateAuditLog(log *mtypes.AuditLog) error {
+	return s.DB.Create(fromAuditLog(log)).Error
+}
+
+func (s sqliteAuditRepo) ListAuditLog(query *mtypes.AuditQuery) ([]*mtypes.AuditLog, error) {
+	db := s.DB
+	if len(query.Account) != 0 {
+		db = db.Where("account = ?", query.Account)
+	}
+	if len(query.Method) != 0 {
+		db = db.Where("method = ?", query.Method)
+	}
+	if !query.Start.IsZero() {
+		db = db.Where("created_at >= ?", query.Start)
+	}
+	if !query.End.IsZero() {
+		db = db.Where("created_at < ?", query.End)
+	}
+	if query.Limit > 0 {
+		db = db.Limit(query.Limit)
+	}
+
+	var logs []*sqliteAuditLog
+	if err := db.Order("created_at DESC").Find(&logs).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]*mtypes.AuditLog, 0, len(logs))
+	for _, l := range logs {
+		result = append(result, l.AuditLog())
+	}
+	return result, nil
+}
@@ -0,0 +1,57 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	venustypes "github.com/filecoin-project/venus/venus-shared/types"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+)
+
+func TestAuditLog(t *testing.T) {
+	auditRepo := setupRepo(t).AuditRepo()
+
+	now := time.Now().Round(time.Second)
+	newLog := func(account, method string, createdAt time.Time) *mtypes.AuditLog {
+		return &mtypes.AuditLog{
+			ID:        venustypes.NewUUID().String(),
+			Account:   account,
+			Method:    method,
+			Params:    `{"id":"1"}`,
+			Previous:  `"FillMsg"`,
+			CreatedAt: createdAt,
+		}
+	}
+	logs := []*mtypes.AuditLog{
+		newLog("admin", "MarkBadMessage", now.Add(-2*time.Hour)),
+		newLog("admin", "UpdateNonce", now.Add(-time.Hour)),
+		newLog("operator", "MarkBadMessage", now),
+	}
+	for _, l := range logs {
+		assert.NoError(t, auditRepo.CreateAuditLog(l))
+	}
+
+	res, err := auditRepo.ListAuditLog(&mtypes.AuditQuery{})
+	assert.NoError(t, err)
+	assert.Len(t, res, 3)
+	assert.Equal(t, logs[2].ID, res[0].ID)
+	assert.Equal(t, logs[2].Params, res[0].Params)
+	assert.Equal(t, logs[2].Previous, res[0].Previous)
+
+	res, err = auditRepo.ListAuditLog(&mtypes.AuditQuery{Account: "admin"})
+	assert.NoError(t, err)
+	assert.Len(t, res, 2)
+
+	res, err = auditRepo.ListAuditLog(&mtypes.AuditQuery{Method: "MarkBadMessage", Limit: 1})
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+	assert.Equal(t, logs[2].ID, res[0].ID)
+
+	res, err = auditRepo.ListAuditLog(&mtypes.AuditQuery{Start: now.Add(-90 * time.Minute), End: now})
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+	assert.Equal(t, logs[1].ID, res[0].ID)
+}
@@ -0,0 +1,220 @@
+package sqlite
+
+import (
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/models/repo"
+)
+
+type sqliteAuthUser struct {
+	Name    string `gorm:"column:name;type:varchar(256);primary_key;"`
+	Comment string `gorm:"column:comment;type:varchar(256);NOT NULL"`
+
+	CreatedAt time.Time `gorm:"column:created_at;NOT NULL"`
+}
+
+func (s sqliteAuthUser) TableName() string {
+	return "auth_users"
+}
+
+type sqliteAuthToken struct {
+	// TokenHash is the hex encoded sha256 hash of token, the token itself is not stored
+	TokenHash string `gorm:"column:token_hash;type:varchar(64);primary_key;"`
+	Name      string `gorm:"column:name;type:varchar(256);index;NOT NULL"`
+	Perm      string `gorm:"column:perm;type:varchar(32);NOT NULL"`
+
+	CreatedAt time.Time `gorm:"column:created_at;NOT NULL"`
+}
+
+func (s sqliteAuthToken) TableName() string {
+	return "auth_tokens"
+}
+
+func (s sqliteAuthToken) AuthToken() *mtypes.AuthToken {
+	return &mtypes.AuthToken{
+		TokenHash: s.TokenHash,
+		Name:      s.Name,
+		Perm:      s.Perm,
+		CreatedAt: s.CreatedAt,
+	}
+}
+
+type sqliteAuthSigner struct {
+	Name   string `gorm:"column:name;type:varchar(256);primary_key;"`
+	Signer string `gorm:"column:signer;type:varchar(256);primary_key;index"`
+
+	CreatedAt time.Time `gorm:"column:created_at;NOT NULL"`
+}
+
+func (s sqliteAuthSigner) TableName() string {
+	return "auth_signers"
+}
+
+var _ repo.AuthRepo = (*sqliteAuthRepo)(nil)
+
+type sqliteAuthRepo struct {
+	*gorm.DB
+}
+
+func newSqliteAuthRepo(db *gorm.DB) sqliteAuthRepo {
+	return sqliteAuthRepo{DB: db}
+}
+
+func (s sqliteAuthRepo) CreateUser(user *mtypes.AuthUser) error {
+	return s.DB.Create(&sqliteAuthUser{
+		Name:      user.Name,
+		Comment:   user.Comment,
+		CreatedAt: user.CreatedAt,
+	}).Error
+}
+
+func (s sqliteAuthRepo) GetUser(name string) (*mtypes.AuthUser, error) {
+	var user sqliteAuthUser
+	if err := s.DB.Take(&user, "name = ?", name).Error; err != nil {
+		return nil, err
+	}
+	users, err := s.withSigners([]*sqliteAuthUser{&user})
+	if err != nil {
+		return nil, err
+	}
+	return users[0], nil
+}
+
+func (s sqliteAuthRepo) ListUsers() ([]*mtypes.AuthUser, error) {
+	var users []*sqliteAuthUser
+	if err := s.DB.Order("name").Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return s.withSigners(users)
+}
+
+func (s sqliteAuthRepo) withSigners(users []*sqliteAuthUser) ([]*mtypes.AuthUser, error) {
+	names := make([]string, 0, len(users))
+	for _, u := range users {
+		names = append(names, u.Name)
+	}
+	var signers []*sqliteAuthSigner
+	if err := s.DB.Order("signer").Find(&signers, "name in ?", names).Error; err != nil {
+		return nil, err
+	}
+	userSigners := make(map[string][]address.Address, len(users))
+	for _, signer := range signers {
+		addr, err := address.NewFromString(signer.Signer)
+		if err != nil {
+			return nil, err
+		}
+		userSigners[signer.Name] = append(userSigners[signer.Name], addr)
+	}
+
+	result := make([]*mtypes.AuthUser, 0, len(users))
+	for _, u := range users {
+		result = append(result, &mtypes.AuthUser{
+			Name:      u.Name,
+			Comment:   u.Comment,
+			Signers:   userSigners[u.Name],
+			CreatedAt: u.CreatedAt,
+		})
+	}
+	return result, nil
+}
+
+func (s sqliteAuthRepo) DeleteUser(name string) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&sqliteAuthToken{}, "name = ?", name).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&sqliteAuthSigner{}, "name = ?", name).Error; err != nil {
+			return err
+		}
+		res := tx.Delete(&sqliteAuthUser{}, "name = ?", name)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (s sqliteAuthRepo) CreateToken(token *mtypes.AuthToken) error {
+	return s.DB.Create(&sqliteAuthToken{
+		TokenHash: token.TokenHash,
+		Name:      token.Name,
+		Perm:      token.Perm,
+		CreatedAt: token.CreatedAt,
+	}).Error
+}
+
+func (s sqliteAuthRepo) GetToken(tokenHash string) (*mtypes.AuthToken, error) {
+	var t sqliteAuthToken
+	if err := s.DB.Take(&t, "token_hash = ?", tokenHash).Error; err != nil {
+		return nil, err
+	}
+	return t.AuthToken(), nil
+}
+
+func (s sqliteAuthRepo) ListTokens(name string) ([]*mtypes.AuthToken, error) {
+	query := s.DB
+	if len(name) != 0 {
+		query = query.Where("name = ?", name)
+	}
+
+	var tokens []*sqliteAuthToken
+	if err := query.Order("created_at").Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+	result := make([]*mtypes.AuthToken, 0, len(tokens))
+	for _, t := range tokens {
+		result = append(result, t.AuthToken())
+	}
+	return result, nil
+}
+
+func (s sqliteAuthRepo) DeleteToken(tokenHash string) error {
+	res := s.DB.Delete(&sqliteAuthToken{}, "token_hash = ?", tokenHash)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (s sqliteAuthRepo) BindSigners(name string, signers []address.Address) error {
+	if len(signers) == 0 {
+		return nil
+	}
+	now := time.Now()
+	list := make([]*sqliteAuthSigner, 0, len(signers))
+	for _, signer := range signers {
+		list = append(list, &sqliteAuthSigner{Name: name, Signer: signer.String(), CreatedAt: now})
+	}
+	return s.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(list).Error
+}
+
+func (s sqliteAuthRepo) UnbindSigners(name string, signers []address.Address) error {
+	if len(signers) == 0 {
+		return nil
+	}
+	list := make([]string, 0, len(signers))
+	for _, signer := range signers {
+		list = append(list, signer.String())
+	}
+	return s.DB.Delete(&sqliteAuthSigner{}, "name = ? and signer in ?", name, list).Error
+}
+
+func (s sqliteAuthRepo) ListSignerUsers(signer address.Address) ([]string, error) {
+	var names []string
+	if err := s.DB.Model(&sqliteAuthSigner{}).Where("signer = ?", signer.String()).Order("name").
+		Pluck("name", &names).Error; err != nil {
+		return nil, err
+	}
+	return names, nil
+}
@@ -0,0 +1,79 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/testhelper"
+)
+
+func TestAuthRepo(t *testing.T) {
+	authRepo := setupRepo(t).AuthRepo()
+	addrs := testhelper.RandAddresses(t, 3)
+
+	for _, name := range []string{"b", "a"} {
+		assert.NoError(t, authRepo.CreateUser(&mtypes.AuthUser{Name: name, Comment: "comment " + name, CreatedAt: time.Now()}))
+	}
+	assert.Error(t, authRepo.CreateUser(&mtypes.AuthUser{Name: "a", CreatedAt: time.Now()}))
+
+	// bind twice takes no effect
+	assert.NoError(t, authRepo.BindSigners("a", addrs[:2]))
+	assert.NoError(t, authRepo.BindSigners("a", addrs[:1]))
+	assert.NoError(t, authRepo.BindSigners("b", addrs[1:]))
+
+	user, err := authRepo.GetUser("a")
+	assert.NoError(t, err)
+	assert.Equal(t, "comment a", user.Comment)
+	assert.ElementsMatch(t, addrs[:2], user.Signers)
+
+	users, err := authRepo.ListUsers()
+	assert.NoError(t, err)
+	require.Len(t, users, 2)
+	assert.Equal(t, "a", users[0].Name)
+	assert.Equal(t, "b", users[1].Name)
+	assert.ElementsMatch(t, addrs[1:], users[1].Signers)
+
+	names, err := authRepo.ListSignerUsers(addrs[1])
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, names)
+
+	assert.NoError(t, authRepo.UnbindSigners("a", addrs[1:2]))
+	names, err = authRepo.ListSignerUsers(addrs[1])
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b"}, names)
+
+	// tokens
+	assert.NoError(t, authRepo.CreateToken(&mtypes.AuthToken{TokenHash: "hash-a", Name: "a", Perm: "write", CreatedAt: time.Now()}))
+	assert.NoError(t, authRepo.CreateToken(&mtypes.AuthToken{TokenHash: "hash-b", Name: "b", Perm: "read", CreatedAt: time.Now()}))
+	token, err := authRepo.GetToken("hash-a")
+	assert.NoError(t, err)
+	assert.Equal(t, "a", token.Name)
+	assert.Equal(t, "write", token.Perm)
+
+	tokens, err := authRepo.ListTokens("")
+	assert.NoError(t, err)
+	assert.Len(t, tokens, 2)
+	tokens, err = authRepo.ListTokens("b")
+	assert.NoError(t, err)
+	require.Len(t, tokens, 1)
+	assert.Equal(t, "hash-b", tokens[0].TokenHash)
+
+	assert.NoError(t, authRepo.DeleteToken("hash-b"))
+	assert.ErrorIs(t, authRepo.DeleteToken("hash-b"), gorm.ErrRecordNotFound)
+
+	// delete user removes its tokens and signers
+	assert.NoError(t, authRepo.DeleteUser("a"))
+	assert.ErrorIs(t, authRepo.DeleteUser("a"), gorm.ErrRecordNotFound)
+	_, err = authRepo.GetUser("a")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	_, err = authRepo.GetToken("hash-a")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	names, err = authRepo.ListSignerUsers(addrs[0])
+	assert.NoError(t, err)
+	assert.Len(t, names, 0)
+}
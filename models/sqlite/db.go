@@ -30,6 +30,34 @@ func (d SqlLiteRepo) NodeRepo() repo.NodeRepo {
 	return newSqliteNodeRepo(d.DB)
 }
 
+func (d SqlLiteRepo) ApprovalRepo() repo.ApprovalRepo {
+	return newSqliteApprovalRepo(d.DB)
+}
+
+func (d SqlLiteRepo) TopUpRepo() repo.TopUpRepo {
+	return newSqliteTopUpRepo(d.DB)
+}
+
+func (d SqlLiteRepo) AuditRepo() repo.AuditRepo {
+	return newSqliteAuditRepo(d.DB)
+}
+
+func (d SqlLiteRepo) MessageHistoryRepo() repo.MessageHistoryRepo {
+	return newSqliteMessageHistoryRepo(d.DB)
+}
+
+func (d SqlLiteRepo) MessageVersionRepo() repo.MessageVersionRepo {
+	return newSqliteMessageVersionRepo(d.DB)
+}
+
+func (d SqlLiteRepo) AuthRepo() repo.AuthRepo {
+	return newSqliteAuthRepo(d.DB)
+}
+
+func (d SqlLiteRepo) LeaseRepo() repo.LeaseRepo {
+	return newSqliteLeaseRepo(d.DB)
+}
+
 func (d SqlLiteRepo) AutoMigrate() error {
 	err := d.GetDb().AutoMigrate(sqliteMessage{})
 	if err != nil {
@@ -44,7 +72,35 @@ func (d SqlLiteRepo) AutoMigrate() error {
 		return err
 	}
 
-	return d.GetDb().AutoMigrate(sqliteNode{})
+	if err := d.GetDb().AutoMigrate(sqliteNode{}); err != nil {
+		return err
+	}
+
+	if err := d.GetDb().AutoMigrate(sqliteApproval{}); err != nil {
+		return err
+	}
+
+	if err := d.GetDb().AutoMigrate(sqliteTopUp{}); err != nil {
+		return err
+	}
+
+	if err := d.GetDb().AutoMigrate(sqliteAuditLog{}); err != nil {
+		return err
+	}
+
+	if err := d.GetDb().AutoMigrate(sqliteMessageHistory{}); err != nil {
+		return err
+	}
+
+	if err := d.GetDb().AutoMigrate(sqliteMessageVersion{}); err != nil {
+		return err
+	}
+
+	if err := d.GetDb().AutoMigrate(sqliteLease{}); err != nil {
+		return err
+	}
+
+	return d.GetDb().AutoMigrate(sqliteAuthUser{}, sqliteAuthToken{}, sqliteAuthSigner{})
 }
 
 func (d SqlLiteRepo) GetDb() *gorm.DB {
@@ -72,6 +128,22 @@ func (t *TxSqlliteRepo) AddressRepo() repo.AddressRepo {
 	return newSqliteAddressRepo(t.DB)
 }
 
+func (t *TxSqlliteRepo) ApprovalRepo() repo.ApprovalRepo {
+	return newSqliteApprovalRepo(t.DB)
+}
+
+func (t *TxSqlliteRepo) TopUpRepo() repo.TopUpRepo {
+	return newSqliteTopUpRepo(t.DB)
+}
+
+func (t *TxSqlliteRepo) MessageHistoryRepo() repo.MessageHistoryRepo {
+	return newSqliteMessageHistoryRepo(t.DB)
+}
+
+func (t *TxSqlliteRepo) MessageVersionRepo() repo.MessageVersionRepo {
+	return newSqliteMessageVersionRepo(t.DB)
+}
+
 func (d SqlLiteRepo) DbClose() error {
 	// todo: if '*gorm.DB' need to dispose?
 	return nil
@@ -4,6 +4,8 @@ import (
 	"reflect"
 
 	types "github.com/filecoin-project/venus/venus-shared/types/messager"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
 )
 
 var (
@@ -25,3 +27,8 @@ var (
 	TNode       = reflect.TypeOf(&types.Node{})
 	TSqliteNode = reflect.TypeOf(&sqliteNode{})
 )
+
+var (
+	TAuditLog       = reflect.TypeOf(&mtypes.AuditLog{})
+	TSqliteAuditLog = reflect.TypeOf(&sqliteAuditLog{})
+)
@@ -0,0 +1,66 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLease(t *testing.T) {
+	leaseRepo := setupRepo(t).LeaseRepo()
+	name := "leader"
+
+	ok, err := leaseRepo.AcquireLease(name, "a", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// held by a, b can not acquire it
+	ok, err = leaseRepo.AcquireLease(name, "b", time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// a renew it
+	now, err := leaseRepo.Now()
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), now, time.Second)
+	ok, err = leaseRepo.AcquireLease(name, "a", 2*time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// expire time is counted from the time of database
+	lease, err := leaseRepo.GetLease(name)
+	assert.NoError(t, err)
+	assert.Equal(t, "a", lease.Holder)
+	assert.WithinDuration(t, now.Add(2*time.Minute), lease.ExpireAt, time.Second)
+
+	// release by others takes no effect
+	assert.NoError(t, leaseRepo.ReleaseLease(name, "b"))
+	ok, err = leaseRepo.AcquireLease(name, "b", time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// b take over after a released
+	assert.NoError(t, leaseRepo.ReleaseLease(name, "a"))
+	time.Sleep(10 * time.Millisecond)
+	ok, err = leaseRepo.AcquireLease(name, "b", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	lease, err = leaseRepo.GetLease(name)
+	assert.NoError(t, err)
+	assert.Equal(t, "b", lease.Holder)
+
+	// list by prefix
+	for _, n := range []string{"instance/a", "instance/b", "address/f01"} {
+		ok, err = leaseRepo.AcquireLease(n, "a", time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	}
+	leases, err := leaseRepo.ListLease("instance/")
+	assert.NoError(t, err)
+	assert.Len(t, leases, 2)
+	for _, l := range leases {
+		assert.Contains(t, []string{"instance/a", "instance/b"}, l.Name)
+	}
+}
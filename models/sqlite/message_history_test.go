@@ -0,0 +1,69 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/stretchr/testify/assert"
+
+	venustypes "github.com/filecoin-project/venus/venus-shared/types"
+	types "github.com/filecoin-project/venus/venus-shared/types/messager"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/testhelper"
+)
+
+func TestMessageHistory(t *testing.T) {
+	historyRepo := setupRepo(t).MessageHistoryRepo()
+
+	msgID := venustypes.NewUUID().String()
+	signedCid := testhelper.NewShareSignedMessage().Cid()
+	now := time.Now().Round(time.Second)
+	pushed := &mtypes.MessageHistory{
+		ID:        venustypes.NewUUID().String(),
+		MsgID:     msgID,
+		OldState:  types.UnKnown,
+		NewState:  types.UnFillMsg,
+		Trigger:   mtypes.TriggerPush,
+		CreatedAt: now.Add(-time.Minute),
+	}
+	selected := &mtypes.MessageHistory{
+		ID:         venustypes.NewUUID().String(),
+		MsgID:      msgID,
+		OldState:   types.UnFillMsg,
+		NewState:   types.FillMsg,
+		GasLimit:   100,
+		GasFeeCap:  big.NewInt(200),
+		GasPremium: big.NewInt(100),
+		SignedCid:  &signedCid,
+		Height:     10,
+		Trigger:    mtypes.TriggerSelector,
+		CreatedAt:  now,
+	}
+	other := &mtypes.MessageHistory{
+		ID:        venustypes.NewUUID().String(),
+		MsgID:     venustypes.NewUUID().String(),
+		OldState:  types.UnKnown,
+		NewState:  types.UnFillMsg,
+		Trigger:   mtypes.TriggerPush,
+		CreatedAt: now,
+	}
+
+	assert.NoError(t, historyRepo.CreateMessageHistory(selected, pushed))
+	assert.NoError(t, historyRepo.CreateMessageHistory(other))
+	assert.NoError(t, historyRepo.CreateMessageHistory())
+
+	list, err := historyRepo.ListMessageHistory(msgID)
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.Equal(t, pushed.ID, list[0].ID)
+	assert.Nil(t, list[0].SignedCid)
+	assert.Equal(t, selected.ID, list[1].ID)
+	assert.Equal(t, types.FillMsg, list[1].NewState)
+	assert.Equal(t, selected.GasFeeCap, list[1].GasFeeCap)
+	assert.Equal(t, selected.GasPremium, list[1].GasPremium)
+	assert.Equal(t, signedCid, *list[1].SignedCid)
+	assert.Equal(t, int64(10), list[1].Height)
+	assert.Equal(t, mtypes.TriggerSelector, list[1].Trigger)
+}
@@ -0,0 +1,70 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	venustypes "github.com/filecoin-project/venus/venus-shared/types"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/testhelper"
+)
+
+func TestMessageVersion(t *testing.T) {
+	versionRepo := setupRepo(t).MessageVersionRepo()
+
+	msgID := venustypes.NewUUID().String()
+	now := time.Now().Round(time.Second)
+	newVersion := func(premium int64, createdAt time.Time) *mtypes.MessageVersion {
+		return &mtypes.MessageVersion{
+			SignedCid:  testhelper.NewShareSignedMessage().Cid(),
+			MsgID:      msgID,
+			GasLimit:   100,
+			GasFeeCap:  big.NewInt(1000),
+			GasPremium: big.NewInt(premium),
+			CreatedAt:  createdAt,
+		}
+	}
+	v1 := newVersion(100, now.Add(-time.Minute))
+	v2 := newVersion(200, now)
+
+	assert.NoError(t, versionRepo.SaveMessageVersion(v1))
+	// save exist version again is ignored
+	assert.NoError(t, versionRepo.SaveMessageVersion(v1, v2))
+
+	list, err := versionRepo.ListMessageVersion(msgID)
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.Equal(t, v1.SignedCid, list[0].SignedCid)
+	assert.Equal(t, v2.SignedCid, list[1].SignedCid)
+	assert.Equal(t, v2.GasPremium, list[1].GasPremium)
+
+	res, err := versionRepo.GetMessageVersion(v1.SignedCid)
+	assert.NoError(t, err)
+	assert.Equal(t, msgID, res.MsgID)
+	_, err = versionRepo.GetMessageVersion(testhelper.NewShareSignedMessage().Cid())
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+
+	assert.NoError(t, versionRepo.SetIncluded(msgID, v2.SignedCid))
+	list, err = versionRepo.ListMessageVersion(msgID)
+	assert.NoError(t, err)
+	assert.False(t, list[0].Included)
+	assert.True(t, list[1].Included)
+
+	assert.NoError(t, versionRepo.SetIncluded(msgID, v1.SignedCid))
+	list, err = versionRepo.ListMessageVersion(msgID)
+	assert.NoError(t, err)
+	assert.True(t, list[0].Included)
+	assert.False(t, list[1].Included)
+
+	assert.NoError(t, versionRepo.SetIncluded(msgID, cid.Undef))
+	list, err = versionRepo.ListMessageVersion(msgID)
+	assert.NoError(t, err)
+	assert.False(t, list[0].Included)
+	assert.False(t, list[1].Included)
+}
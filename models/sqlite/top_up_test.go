@@ -0,0 +1,74 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/big"
+	venustypes "github.com/filecoin-project/venus/venus-shared/types"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/testhelper"
+)
+
+func TestTopUp(t *testing.T) {
+	topUpRepo := setupRepo(t).TopUpRepo()
+
+	addrs := testhelper.RandAddresses(t, 3)
+	treasury := addrs[2]
+	now := time.Now().Round(time.Second)
+	records := []*mtypes.TopUpRecord{
+		{Addr: addrs[0], CreatedAt: now.Add(-48 * time.Hour)},
+		{Addr: addrs[0], CreatedAt: now.Add(-time.Hour)},
+		{Addr: addrs[1], CreatedAt: now},
+	}
+	for i, r := range records {
+		r.ID = venustypes.NewUUID().String()
+		r.Treasury = treasury
+		r.MsgID = venustypes.NewUUID().String()
+		r.Value = big.NewInt(int64(i + 1))
+		r.Balance = big.NewInt(0)
+	}
+
+	t.Run("create top up", func(t *testing.T) {
+		for _, r := range records {
+			assert.NoError(t, topUpRepo.CreateTopUp(r))
+		}
+	})
+
+	t.Run("get latest top up", func(t *testing.T) {
+		res, err := topUpRepo.GetLatestTopUp(addrs[0])
+		assert.NoError(t, err)
+		assert.Equal(t, records[1].ID, res.ID)
+		assert.Equal(t, records[1].MsgID, res.MsgID)
+		assert.Equal(t, records[1].Value, res.Value)
+
+		_, err = topUpRepo.GetLatestTopUp(treasury)
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+	})
+
+	t.Run("list top up", func(t *testing.T) {
+		list, err := topUpRepo.ListTopUp(address.Undef)
+		assert.NoError(t, err)
+		assert.Len(t, list, 3)
+
+		list, err = topUpRepo.ListTopUp(addrs[1])
+		assert.NoError(t, err)
+		assert.Len(t, list, 1)
+		assert.Equal(t, records[2].ID, list[0].ID)
+	})
+
+	t.Run("list top up by treasury", func(t *testing.T) {
+		list, err := topUpRepo.ListTopUpByTreasury(treasury, now.Add(-24*time.Hour))
+		assert.NoError(t, err)
+		assert.Len(t, list, 2)
+
+		list, err = topUpRepo.ListTopUpByTreasury(addrs[0], now.Add(-24*time.Hour))
+		assert.NoError(t, err)
+		assert.Len(t, list, 0)
+	})
+}
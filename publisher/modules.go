@@ -23,6 +23,8 @@ func Options() fx.Option {
 		fx.Provide(NewIMsgPublisher),
 		fx.Provide(NewP2pPublisher),
 		fx.Provide(newRpcPublisher),
+		fx.Provide(NewRouter),
+		fx.Provide(NewPropagationTracker),
 	)
 }
 
@@ -49,11 +51,16 @@ func NewMessageReciver(ctx context.Context, p IMsgPublisher) (MessageReceiver, e
 	return msgReceiver, nil
 }
 
-func NewIMsgPublisher(ctx context.Context, netParams *types.NetworkParams, cfg *config.PublisherConfig, P2pPublisher *P2pPublisher, rpcPublisher *RpcPublisher) (IMsgPublisher, error) {
+func NewIMsgPublisher(ctx context.Context, netParams *types.NetworkParams, cfg *config.PublisherConfig, P2pPublisher *P2pPublisher, rpcPublisher *RpcPublisher, tracker *PropagationTracker) (IMsgPublisher, error) {
 	var ret IMsgPublisher
 	var err error
 
-	mergePublisher := NewMergePublisher(ctx, rpcPublisher)
+	mergePublisher := NewMergePublisher(ctx)
+	// track messages before publishing them, so that the ones coming back quickly are not missed
+	if cfg.ConfirmPropagation {
+		mergePublisher.AddPublisher(tracker)
+	}
+	mergePublisher.AddPublisher(rpcPublisher)
 	if cfg.EnableP2P {
 		mergePublisher.AddPublisher(P2pPublisher)
 	}
@@ -87,6 +94,6 @@ func NewIMsgPublisher(ctx context.Context, netParams *types.NetworkParams, cfg *
 	return ret, nil
 }
 
-func newRpcPublisher(ctx context.Context, nodeClient v1.FullNode, nodeProvider repo.INodeProvider, cfg *config.PublisherConfig) *RpcPublisher {
-	return NewRpcPublisher(ctx, nodeClient, nodeProvider, cfg.EnableMultiNode)
+func newRpcPublisher(ctx context.Context, nodeClient v1.FullNode, nodeProvider repo.INodeProvider, cfg *config.PublisherConfig, router *Router) *RpcPublisher {
+	return NewRpcPublisher(ctx, nodeClient, nodeProvider, cfg, router)
 }
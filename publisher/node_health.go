@@ -0,0 +1,125 @@
+package publisher
+
+import (
+	"sync"
+	"time"
+
+	"github.com/filecoin-project/go-state-types/abi"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+)
+
+const maxNodeRetryBackoff = 10 * time.Minute
+
+// nodeHealth records the results of pushing messages to and probing a node, the node is excluded from publishing
+// after `threshold` consecutive failures and retried with backoff.
+type nodeHealth struct {
+	lk          sync.Mutex
+	name        string
+	weight      int
+	threshold   int
+	baseBackoff time.Duration
+
+	lastSuccess time.Time
+	failures    int
+	lastErr     string
+	latency     time.Duration
+	height      abi.ChainEpoch
+	backoff     time.Duration
+	nextRetry   time.Time
+}
+
+func newNodeHealth(name string, weight, threshold int, baseBackoff time.Duration) *nodeHealth {
+	return &nodeHealth{
+		name:        name,
+		weight:      weight,
+		threshold:   threshold,
+		baseBackoff: baseBackoff,
+	}
+}
+
+func (h *nodeHealth) healthyLocked() bool {
+	return h.threshold <= 0 || h.failures < h.threshold
+}
+
+func (h *nodeHealth) healthy() bool {
+	h.lk.Lock()
+	defer h.lk.Unlock()
+	return h.healthyLocked()
+}
+
+func (h *nodeHealth) success(latency time.Duration) {
+	h.lk.Lock()
+	defer h.lk.Unlock()
+	if !h.healthyLocked() {
+		log.Infof("node %s recovered", h.name)
+	}
+	h.lastSuccess = time.Now()
+	h.failures = 0
+	h.lastErr = ""
+	h.latency = latency
+	h.backoff = 0
+	h.nextRetry = time.Time{}
+}
+
+func (h *nodeHealth) failure(err error) {
+	h.lk.Lock()
+	defer h.lk.Unlock()
+	h.failures++
+	h.lastErr = err.Error()
+	if h.healthyLocked() {
+		return
+	}
+
+	if h.backoff == 0 {
+		h.backoff = h.baseBackoff
+		log.Warnf("exclude node %s after %d consecutive failures: %v", h.name, h.failures, err)
+	} else if h.backoff *= 2; h.backoff > maxNodeRetryBackoff {
+		h.backoff = maxNodeRetryBackoff
+	}
+	h.nextRetry = time.Now().Add(h.backoff)
+}
+
+// tryUse returns whether the node can be probed now, an excluded node is retried once its backoff elapsed
+func (h *nodeHealth) tryUse(now time.Time) bool {
+	h.lk.Lock()
+	defer h.lk.Unlock()
+	if h.healthyLocked() {
+		return true
+	}
+	if now.Before(h.nextRetry) {
+		return false
+	}
+	// only one retry in a backoff window
+	h.nextRetry = now.Add(h.backoff)
+	return true
+}
+
+// available is tryUse without taking the retry, it is used to reconnect the node failed to connect
+func (h *nodeHealth) available(now time.Time) bool {
+	h.lk.Lock()
+	defer h.lk.Unlock()
+	return h.healthyLocked() || !now.Before(h.nextRetry)
+}
+
+func (h *nodeHealth) setHeight(height abi.ChainEpoch) {
+	h.lk.Lock()
+	defer h.lk.Unlock()
+	h.height = height
+}
+
+func (h *nodeHealth) info() *mtypes.PublishNodeHealth {
+	h.lk.Lock()
+	defer h.lk.Unlock()
+	return &mtypes.PublishNodeHealth{
+		Name:                h.name,
+		Weight:              h.weight,
+		Healthy:             h.healthyLocked(),
+		LastSuccess:         h.lastSuccess,
+		ConsecutiveFailures: h.failures,
+		LastError:           h.lastErr,
+		Latency:             h.latency,
+		HeadHeight:          int64(h.height),
+		NextRetry:           h.nextRetry,
+	}
+}
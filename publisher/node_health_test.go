@@ -0,0 +1,163 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/filecoin-project/go-jsonrpc"
+	mockV1 "github.com/filecoin-project/venus/venus-shared/api/chain/v1/mock"
+	"github.com/filecoin-project/venus/venus-shared/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/filecoin-project/venus-messager/config"
+	"github.com/filecoin-project/venus-messager/testhelper"
+)
+
+func TestNodeHealth(t *testing.T) {
+	h := newNodeHealth("node", 1, 2, time.Minute)
+	errPush := errors.New("push failed")
+
+	h.failure(errPush)
+	assert.True(t, h.healthy())
+	assert.True(t, h.tryUse(time.Now()))
+
+	// excluded after threshold failures
+	h.failure(errPush)
+	info := h.info()
+	assert.False(t, info.Healthy)
+	assert.Equal(t, 2, info.ConsecutiveFailures)
+	assert.Equal(t, errPush.Error(), info.LastError)
+	assert.False(t, h.tryUse(time.Now()))
+
+	// retried once after backoff elapsed
+	now := time.Now().Add(time.Minute + time.Second)
+	assert.True(t, h.tryUse(now))
+	assert.False(t, h.tryUse(now))
+
+	// backoff doubles
+	h.failure(errPush)
+	assert.False(t, h.tryUse(time.Now().Add(time.Minute+time.Second)))
+	assert.True(t, h.tryUse(time.Now().Add(2*time.Minute+time.Second)))
+
+	h.success(time.Millisecond)
+	info = h.info()
+	assert.True(t, info.Healthy)
+	assert.Equal(t, 0, info.ConsecutiveFailures)
+	assert.Empty(t, info.LastError)
+	assert.Equal(t, time.Millisecond, info.Latency)
+
+	// never excluded without threshold
+	h = newNodeHealth("main", 1, 0, 0)
+	for i := 0; i < 10; i++ {
+		h.failure(errPush)
+	}
+	assert.True(t, h.tryUse(time.Now()))
+}
+
+func TestSelectNodes(t *testing.T) {
+	cfg := &config.PublisherConfig{
+		EnableMultiNode:      true,
+		NodeFailureThreshold: 1,
+		NodeRetryBackoff:     time.Minute,
+		NodeWeights:          map[string]int{"a": 2, "backup": 0},
+	}
+	router, err := NewRouter(cfg, nil)
+	assert.NoError(t, err)
+	p := &RpcPublisher{cfg: cfg, router: router}
+	newNode := func(name string) *rpcNode {
+		return &rpcNode{
+			name:   name,
+			thread: &nodeThread{name: name},
+			health: newNodeHealth(name, p.nodeWeight(name), cfg.NodeFailureThreshold, cfg.NodeRetryBackoff),
+		}
+	}
+	a, b, c, backup := newNode("a"), newNode("b"), newNode("c"), newNode("backup")
+	p.nodes = map[types.UUID]*rpcNode{{1}: a, {2}: b, {3}: c, {4}: backup}
+
+	selected := func() []string {
+		nodes, _ := p.selectNodes(nil)
+		var ret []string
+		for _, n := range nodes {
+			ret = append(ret, n.name)
+		}
+		return ret
+	}
+
+	assert.Equal(t, []string{"a", "b", "c"}, selected())
+
+	cfg.MaxPublishNodes = 2
+	assert.Equal(t, []string{"a", "b"}, selected())
+
+	// unhealthy node is skipped
+	a.health.failure(errors.New("push failed"))
+	assert.Equal(t, []string{"b", "c"}, selected())
+
+	// backup is used only when no other node is available
+	b.health.failure(errors.New("push failed"))
+	c.health.failure(errors.New("push failed"))
+	assert.Equal(t, []string{"backup"}, selected())
+
+	// node not connected is skipped
+	backup.thread = nil
+	assert.Empty(t, selected())
+}
+
+func TestSelectNodesNotTakeRetry(t *testing.T) {
+	cfg := &config.PublisherConfig{EnableMultiNode: true, NodeFailureThreshold: 1, NodeRetryBackoff: time.Millisecond}
+	router, err := NewRouter(cfg, nil)
+	assert.NoError(t, err)
+	p := &RpcPublisher{cfg: cfg, router: router}
+	n := &rpcNode{
+		name:   "a",
+		thread: &nodeThread{name: "a"},
+		health: newNodeHealth("a", 1, cfg.NodeFailureThreshold, cfg.NodeRetryBackoff),
+	}
+	p.nodes = map[types.UUID]*rpcNode{{1}: n}
+
+	n.health.failure(errors.New("connect failed"))
+	time.Sleep(10 * time.Millisecond)
+	// excluded node isn't used for publishing even if its backoff elapsed, the retry is left to probing
+	for i := 0; i < 3; i++ {
+		nodes, skipped := p.selectNodes(nil)
+		assert.Empty(t, nodes)
+		assert.Equal(t, "unhealthy", skipped["a"])
+	}
+	assert.True(t, n.health.tryUse(time.Now()))
+
+	n.health.success(time.Millisecond)
+	nodes, _ := p.selectNodes(nil)
+	assert.Len(t, nodes, 1)
+}
+
+func TestNodeThreadPush(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	node := mockV1.NewMockFullNode(ctrl)
+	health := newNodeHealth("node", 1, 2, time.Minute)
+	n := &nodeThread{name: "node", nodeClient: node, health: health, results: newPublishResults(0)}
+	msgs := testhelper.NewShareSignedMessages(1)
+
+	// rejected by mpool, the node works well
+	node.EXPECT().MpoolBatchPushUntrusted(gomock.Any(), msgs).Return(nil, errors.New("gas fee cap too low")).Times(3)
+	for i := 0; i < 3; i++ {
+		n.push(ctx, node, msgs)
+	}
+	assert.True(t, health.healthy())
+	assert.Equal(t, 0, health.info().ConsecutiveFailures)
+
+	// push stopped by ctx is not the fault of node
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	node.EXPECT().MpoolBatchPushUntrusted(gomock.Any(), msgs).Return(nil, context.Canceled)
+	n.push(canceled, node, msgs)
+	assert.Equal(t, 0, health.info().ConsecutiveFailures)
+
+	// connection error
+	node.EXPECT().MpoolBatchPushUntrusted(gomock.Any(), msgs).Return(nil, &jsonrpc.ErrClient{}).Times(2)
+	n.push(ctx, node, msgs)
+	n.push(ctx, node, msgs)
+	assert.False(t, health.healthy())
+}
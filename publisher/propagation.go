@@ -0,0 +1,178 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/filecoin-project/venus/venus-shared/types"
+	"github.com/ipfs/go-cid"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"go.opencensus.io/stats"
+
+	"github.com/filecoin-project/venus-messager/config"
+	"github.com/filecoin-project/venus-messager/metrics"
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+)
+
+const propagationTTL = time.Hour
+
+type trackedMsg struct {
+	msg  *types.SignedMessage
+	info mtypes.MessagePropagation
+}
+
+// PropagationTracker confirms the messages published are propagated in network by subscribing the messages topic,
+// a message is seen when it comes from other peers. The messages not seen in PropagationTimeout are republished by
+// rpc nodes once.
+type PropagationTracker struct {
+	cfg         *config.PublisherConfig
+	republisher IMsgPublisher
+
+	lk   sync.Mutex
+	msgs map[cid.Cid]*trackedMsg
+}
+
+func NewPropagationTracker(ctx context.Context, cfg *config.PublisherConfig, p2pPublisher *P2pPublisher, rpcPublisher *RpcPublisher) (*PropagationTracker, error) {
+	pt := newPropagationTracker(cfg, rpcPublisher)
+	if !cfg.ConfirmPropagation {
+		return pt, nil
+	}
+
+	sub, err := p2pPublisher.topic.Subscribe()
+	if err != nil {
+		return nil, fmt.Errorf("subscribe messages topic failed %w", err)
+	}
+	go pt.receive(ctx, sub, p2pPublisher.self)
+	go pt.run(ctx)
+
+	return pt, nil
+}
+
+func newPropagationTracker(cfg *config.PublisherConfig, republisher IMsgPublisher) *PropagationTracker {
+	return &PropagationTracker{
+		cfg:         cfg,
+		republisher: republisher,
+		msgs:        make(map[cid.Cid]*trackedMsg),
+	}
+}
+
+// PublishMessages starts tracking msgs, it must be called before publishing them
+func (pt *PropagationTracker) PublishMessages(ctx context.Context, msgs []*types.SignedMessage) error {
+	now := time.Now()
+	pt.lk.Lock()
+	defer pt.lk.Unlock()
+
+	for _, msg := range msgs {
+		c := msg.Cid()
+		if _, ok := pt.msgs[c]; ok {
+			continue
+		}
+		pt.msgs[c] = &trackedMsg{
+			msg:  msg,
+			info: mtypes.MessagePropagation{SignedCid: c, PublishedAt: now},
+		}
+	}
+	return nil
+}
+
+func (pt *PropagationTracker) receive(ctx context.Context, sub *pubsub.Subscription, self peer.ID) {
+	defer sub.Cancel()
+	for {
+		msg, err := sub.Next(ctx)
+		if err != nil {
+			log.Warnf("stop receiving messages from network: %v", err)
+			return
+		}
+		// published by self
+		if msg.ReceivedFrom == self {
+			continue
+		}
+
+		var smsg types.SignedMessage
+		if err := smsg.UnmarshalCBOR(bytes.NewReader(msg.Data)); err != nil {
+			log.Debugf("decode message from %s failed %v", msg.ReceivedFrom, err)
+			continue
+		}
+		pt.seen(ctx, smsg.Cid(), msg.ReceivedFrom)
+	}
+}
+
+func (pt *PropagationTracker) seen(ctx context.Context, c cid.Cid, from peer.ID) {
+	now := time.Now()
+	pt.lk.Lock()
+	defer pt.lk.Unlock()
+
+	tracked, ok := pt.msgs[c]
+	if !ok || !tracked.info.SeenAt.IsZero() {
+		return
+	}
+	tracked.info.SeenAt = now
+	tracked.info.SeenFrom = from.String()
+	stats.Record(ctx, metrics.MsgPropagationDelay.M(now.Sub(tracked.info.PublishedAt).Seconds()))
+	log.Debugf("message %s seen from %s", c, from)
+}
+
+func (pt *PropagationTracker) run(ctx context.Context) {
+	interval := pt.cfg.PropagationTimeout / 2
+	if interval < time.Second {
+		interval = time.Second
+	}
+	tm := time.NewTicker(interval)
+	defer tm.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tm.C:
+			pt.republishUnseen(ctx, time.Now())
+		}
+	}
+}
+
+// republishUnseen republishes the messages not seen in time by rpc nodes, and stops tracking the old messages
+func (pt *PropagationTracker) republishUnseen(ctx context.Context, now time.Time) {
+	var unseen []*types.SignedMessage
+	pt.lk.Lock()
+	for c, tracked := range pt.msgs {
+		elapsed := now.Sub(tracked.info.PublishedAt)
+		if elapsed > propagationTTL {
+			delete(pt.msgs, c)
+			continue
+		}
+		if tracked.info.SeenAt.IsZero() && !tracked.info.Republished && elapsed > pt.cfg.PropagationTimeout {
+			tracked.info.Republished = true
+			unseen = append(unseen, tracked.msg)
+		}
+	}
+	pt.lk.Unlock()
+
+	if len(unseen) == 0 {
+		return
+	}
+	log.Warnf("%d messages not seen by network in %v, republish them", len(unseen), pt.cfg.PropagationTimeout)
+	stats.Record(ctx, metrics.NumOfUnseenMsg.M(int64(len(unseen))))
+	if err := pt.republisher.PublishMessages(ctx, unseen); err != nil {
+		log.Warnf("republish unseen messages failed %v", err)
+	}
+}
+
+// MessagePropagation returns the propagation of the signed message, nil when it is not tracked
+func (pt *PropagationTracker) MessagePropagation(ctx context.Context, signedCid cid.Cid) (*mtypes.MessagePropagation, error) {
+	if !pt.cfg.ConfirmPropagation {
+		return nil, fmt.Errorf("confirming propagation is not enabled")
+	}
+	pt.lk.Lock()
+	defer pt.lk.Unlock()
+
+	tracked, ok := pt.msgs[signedCid]
+	if !ok {
+		return nil, nil
+	}
+	info := tracked.info
+	return &info, nil
+}
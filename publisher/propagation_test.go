@@ -0,0 +1,115 @@
+package publisher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockV1 "github.com/filecoin-project/venus/venus-shared/api/chain/v1/mock"
+	"github.com/filecoin-project/venus/venus-shared/types"
+	"github.com/golang/mock/gomock"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/venus-messager/config"
+	mpubsub "github.com/filecoin-project/venus-messager/publisher/pubsub"
+	"github.com/filecoin-project/venus-messager/testhelper"
+)
+
+func TestRepublishUnseen(t *testing.T) {
+	ctx := context.Background()
+	msgs := testhelper.NewShareSignedMessages(3)
+
+	ctrl := gomock.NewController(t)
+	republisher := testhelper.NewMockIMsgPublisher(ctrl)
+	cfg := &config.PublisherConfig{ConfirmPropagation: true, PropagationTimeout: time.Minute}
+	pt := newPropagationTracker(cfg, republisher)
+
+	assert.NoError(t, pt.PublishMessages(ctx, msgs))
+	publishedAt := time.Now()
+	pt.seen(ctx, msgs[0].Cid(), peer.ID("peer"))
+
+	info, err := pt.MessagePropagation(ctx, msgs[0].Cid())
+	assert.NoError(t, err)
+	assert.False(t, info.SeenAt.IsZero())
+	assert.Equal(t, peer.ID("peer").String(), info.SeenFrom)
+
+	// publishing again does not reset the tracking
+	assert.NoError(t, pt.PublishMessages(ctx, msgs))
+	info, err = pt.MessagePropagation(ctx, msgs[0].Cid())
+	assert.NoError(t, err)
+	assert.False(t, info.SeenAt.IsZero())
+
+	pt.republishUnseen(ctx, publishedAt.Add(time.Second))
+
+	// unseen messages are republished once
+	republisher.EXPECT().PublishMessages(ctx, gomock.InAnyOrder(msgs[1:])).Return(nil).Times(1)
+	pt.republishUnseen(ctx, publishedAt.Add(2*time.Minute))
+	pt.republishUnseen(ctx, publishedAt.Add(3*time.Minute))
+	info, err = pt.MessagePropagation(ctx, msgs[1].Cid())
+	assert.NoError(t, err)
+	assert.True(t, info.Republished)
+	assert.True(t, info.SeenAt.IsZero())
+
+	// old messages are not tracked
+	pt.republishUnseen(ctx, publishedAt.Add(propagationTTL+time.Minute))
+	info, err = pt.MessagePropagation(ctx, msgs[1].Cid())
+	assert.NoError(t, err)
+	assert.Nil(t, info)
+
+	_, err = newPropagationTracker(&config.PublisherConfig{}, republisher).MessagePropagation(ctx, msgs[1].Cid())
+	assert.Error(t, err)
+}
+
+func TestConfirmPropagation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	netName := types.NetworkName("test_net_name")
+
+	netCfg := *config.DefaultConfig().Libp2pNet
+	netCfg.ListenAddress = "/ip4/127.0.0.1/tcp/0"
+	ps1, err := mpubsub.NewPubsub(ctx, netName, &netCfg, nil, mpubsub.PersistFiles{})
+	require.NoError(t, err)
+	pi1, err := ps1.AddrListen(ctx)
+	require.NoError(t, err)
+	ps2, err := mpubsub.NewPubsub(ctx, netName, &netCfg, nil, mpubsub.PersistFiles{})
+	require.NoError(t, err)
+	require.NoError(t, ps2.Connect(ctx, pi1))
+
+	cfg := &config.PublisherConfig{ConfirmPropagation: true, PropagationTimeout: time.Minute}
+	router, err := NewRouter(cfg, nil)
+	require.NoError(t, err)
+	p1, err := NewP2pPublisher(ps1, netName, router)
+	require.NoError(t, err)
+	p2, err := NewP2pPublisher(ps2, netName, router)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	rpcPublisher := NewRpcPublisher(ctx, mockV1.NewMockFullNode(ctrl), nil, cfg, router)
+	pt, err := NewPropagationTracker(ctx, cfg, p1, rpcPublisher)
+	require.NoError(t, err)
+
+	msgs := testhelper.NewShareSignedMessages(2)
+	require.NoError(t, pt.PublishMessages(ctx, msgs))
+	// messages published by self are not seen by network
+	require.NoError(t, p1.PublishMessages(ctx, msgs[:1]))
+
+	assert.Eventually(t, func() bool {
+		// the messages are relayed by other peers
+		require.NoError(t, p2.PublishMessages(ctx, msgs[1:]))
+		info, err := pt.MessagePropagation(ctx, msgs[1].Cid())
+		require.NoError(t, err)
+		return !info.SeenAt.IsZero()
+	}, 10*time.Second, 200*time.Millisecond)
+
+	info, err := pt.MessagePropagation(ctx, msgs[1].Cid())
+	require.NoError(t, err)
+	pi2, err := ps2.AddrListen(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, pi2.ID.String(), info.SeenFrom)
+
+	info, err = pt.MessagePropagation(ctx, msgs[0].Cid())
+	require.NoError(t, err)
+	assert.True(t, info.SeenAt.IsZero())
+}
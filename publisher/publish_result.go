@@ -0,0 +1,185 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/filecoin-project/go-jsonrpc"
+	"github.com/filecoin-project/venus/venus-shared/types"
+	"github.com/ipfs/go-cid"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+)
+
+const publishResultTTL = time.Hour
+
+// PublishError is the error returned by node when pushing message, classified by the error message
+type PublishError struct {
+	Node  string
+	Class mtypes.PublishErrorClass
+	Err   error
+}
+
+func newPublishError(node string, err error) *PublishError {
+	return &PublishError{
+		Node:  node,
+		Class: classifyPublishError(err),
+		Err:   err,
+	}
+}
+
+func (e *PublishError) Error() string {
+	return fmt.Sprintf("push to node %s failed(%s): %v", e.Node, e.Class, e.Err)
+}
+
+func (e *PublishError) Unwrap() error {
+	return e.Err
+}
+
+// publishErrorPatterns are the errors of mpool in venus and lotus
+var publishErrorPatterns = []struct {
+	class    mtypes.PublishErrorClass
+	patterns []string
+}{
+	{mtypes.PublishErrNonceTooLow, []string{errMinimumNonce.Error()}},
+	{mtypes.PublishErrAlreadyInMpool, []string{errAlreadyInMpool.Error()}},
+	{mtypes.PublishErrReplaceFeeTooLow, []string{"replace by fee", "too low gaspremium"}},
+	{mtypes.PublishErrFeeCapTooLow, []string{"fee cap too low", "feecap too low", "less than block base fee", "below minimum"}},
+	{mtypes.PublishErrNotEnoughFunds, []string{"not enough funds", "insufficient funds", "insufficient balance"}},
+	{mtypes.PublishErrGasLimit, []string{"gas limit", "not enough gas", "out of gas"}},
+	{mtypes.PublishErrSignature, []string{"signature"}},
+	{mtypes.PublishErrMpoolLimit, []string{"too many pending messages", "nonce gap", "mpool is full"}},
+}
+
+func classifyPublishError(err error) mtypes.PublishErrorClass {
+	var clientErr *jsonrpc.ErrClient
+	if errors.As(err, &clientErr) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return mtypes.PublishErrConnection
+	}
+
+	errMsg := strings.ToLower(err.Error())
+	for _, p := range publishErrorPatterns {
+		for _, pattern := range p.patterns {
+			if strings.Contains(errMsg, pattern) {
+				return p.class
+			}
+		}
+	}
+	return mtypes.PublishErrUnknown
+}
+
+// isNodeFailure returns whether pushing failed because the node can't be reached, the messages rejected by mpool
+// don't mean the node is unhealthy, neither does the push stopped by ctx
+func isNodeFailure(ctx context.Context, pubErr *PublishError) bool {
+	return pubErr.Class == mtypes.PublishErrConnection && ctx.Err() == nil
+}
+
+type msgPublishResults struct {
+	updatedAt time.Time
+	nodes     map[string]*mtypes.PublishResult
+}
+
+// publishResults records the results of pushing messages to every node, keyed by signed cid. The results are
+// kept in memory for publishResultTTL after last push.
+type publishResults struct {
+	lk        sync.Mutex
+	threshold int
+	results   map[cid.Cid]*msgPublishResults
+	lastPrune time.Time
+
+	// onRejected is called when a message is rejected by a node for threshold times in a row
+	onRejected func(signedCid cid.Cid, err *PublishError)
+}
+
+func newPublishResults(threshold int) *publishResults {
+	return &publishResults{
+		threshold: threshold,
+		results:   make(map[cid.Cid]*msgPublishResults),
+		lastPrune: time.Now(),
+	}
+}
+
+func (pr *publishResults) setOnRejected(f func(signedCid cid.Cid, err *PublishError)) {
+	pr.lk.Lock()
+	defer pr.lk.Unlock()
+	pr.onRejected = f
+}
+
+// record saves the result of pushing msg to node, pubErr is nil when succeeded
+func (pr *publishResults) record(node string, msg *types.SignedMessage, pubErr *PublishError) {
+	now := time.Now()
+	c := msg.Cid()
+
+	pr.lk.Lock()
+	pr.pruneLocked(now)
+	msgResults, ok := pr.results[c]
+	if !ok {
+		msgResults = &msgPublishResults{nodes: make(map[string]*mtypes.PublishResult)}
+		pr.results[c] = msgResults
+	}
+	msgResults.updatedAt = now
+	res, ok := msgResults.nodes[node]
+	if !ok {
+		res = &mtypes.PublishResult{Node: node}
+		msgResults.nodes[node] = res
+	}
+	res.Attempts++
+	res.UpdatedAt = now
+
+	var onRejected func(signedCid cid.Cid, err *PublishError)
+	switch {
+	case pubErr == nil:
+		res.Success, res.ErrorClass, res.Error, res.Rejections = true, "", "", 0
+	case pubErr.Class.Rejected():
+		res.Success, res.ErrorClass, res.Error = false, pubErr.Class, pubErr.Err.Error()
+		res.Rejections++
+		if pr.threshold > 0 && res.Rejections == pr.threshold {
+			onRejected = pr.onRejected
+		}
+	default:
+		// the message was accepted before, or the node is unreachable which says nothing about the message
+		res.Success = pubErr.Class != mtypes.PublishErrConnection
+		res.ErrorClass, res.Error = pubErr.Class, pubErr.Err.Error()
+	}
+	pr.lk.Unlock()
+
+	if onRejected != nil {
+		onRejected(c, pubErr)
+	}
+}
+
+func (pr *publishResults) pruneLocked(now time.Time) {
+	if now.Sub(pr.lastPrune) < time.Minute {
+		return
+	}
+	pr.lastPrune = now
+	for c, msgResults := range pr.results {
+		if now.Sub(msgResults.updatedAt) > publishResultTTL {
+			delete(pr.results, c)
+		}
+	}
+}
+
+func (pr *publishResults) list(signedCid cid.Cid) []*mtypes.PublishResult {
+	pr.lk.Lock()
+	defer pr.lk.Unlock()
+
+	msgResults, ok := pr.results[signedCid]
+	if !ok {
+		return []*mtypes.PublishResult{}
+	}
+	results := make([]*mtypes.PublishResult, 0, len(msgResults.nodes))
+	for _, res := range msgResults.nodes {
+		tmp := *res
+		results = append(results, &tmp)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Node < results[j].Node
+	})
+	return results
+}
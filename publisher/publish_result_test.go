@@ -0,0 +1,100 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/filecoin-project/go-jsonrpc"
+	mockV1 "github.com/filecoin-project/venus/venus-shared/api/chain/v1/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/testhelper"
+)
+
+func TestClassifyPublishError(t *testing.T) {
+	cases := map[string]mtypes.PublishErrorClass{
+		"minimum expected nonce is 10, got 9":                mtypes.PublishErrNonceTooLow,
+		"already in mpool: validation failure":               mtypes.PublishErrAlreadyInMpool,
+		"replace by fee has too low GasPremium":              mtypes.PublishErrReplaceFeeTooLow,
+		"gas fee cap too low: 100 < 200":                     mtypes.PublishErrFeeCapTooLow,
+		"not enough funds (required: 1 FIL, balance: 0 FIL)": mtypes.PublishErrNotEnoughFunds,
+		"message gas limit too high":                         mtypes.PublishErrGasLimit,
+		"signature verification failed":                      mtypes.PublishErrSignature,
+		"too many pending messages for actor f01000":         mtypes.PublishErrMpoolLimit,
+		"something unexpected":                               mtypes.PublishErrUnknown,
+	}
+	for errMsg, class := range cases {
+		assert.Equal(t, class, classifyPublishError(errors.New(errMsg)), errMsg)
+	}
+
+	assert.Equal(t, mtypes.PublishErrConnection, classifyPublishError(fmt.Errorf("wrap: %w", context.DeadlineExceeded)))
+	assert.Equal(t, mtypes.PublishErrConnection, classifyPublishError(&jsonrpc.ErrClient{}))
+}
+
+func TestPublishResults(t *testing.T) {
+	ctx := context.Background()
+	msgs := testhelper.NewShareSignedMessages(3)
+	errFeeCap := errors.New("gas fee cap too low")
+
+	ctrl := gomock.NewController(t)
+	node := mockV1.NewMockFullNode(ctrl)
+
+	var rejected []cid.Cid
+	results := newPublishResults(2)
+	results.setOnRejected(func(signedCid cid.Cid, err *PublishError) {
+		assert.Equal(t, mtypes.PublishErrFeeCapTooLow, err.Class)
+		assert.True(t, errors.Is(err, errFeeCap))
+		rejected = append(rejected, signedCid)
+	})
+	thread := &nodeThread{name: "node", nodeClient: node, health: newNodeHealth("node", 1, 3, 0), results: results}
+
+	// batch push failed, every message is pushed alone to get its result
+	node.EXPECT().MpoolBatchPushUntrusted(ctx, msgs).Return(nil, errFeeCap).Times(2)
+	node.EXPECT().MpoolPushUntrusted(ctx, msgs[0]).Return(msgs[0].Cid(), nil).Times(2)
+	node.EXPECT().MpoolPushUntrusted(ctx, msgs[1]).Return(cid.Undef, errFeeCap).Times(2)
+	node.EXPECT().MpoolPushUntrusted(ctx, msgs[2]).Return(cid.Undef, errAlreadyInMpool).Times(2)
+	thread.push(ctx, node, msgs)
+	assert.Empty(t, rejected)
+	thread.push(ctx, node, msgs)
+	assert.Equal(t, []cid.Cid{msgs[1].Cid()}, rejected)
+	// rejections are not failures of node
+	assert.True(t, thread.health.healthy())
+
+	res := results.list(msgs[0].Cid())
+	assert.Len(t, res, 1)
+	assert.True(t, res[0].Success)
+	assert.Equal(t, 2, res[0].Attempts)
+
+	res = results.list(msgs[1].Cid())
+	assert.False(t, res[0].Success)
+	assert.Equal(t, mtypes.PublishErrFeeCapTooLow, res[0].ErrorClass)
+	assert.Equal(t, 2, res[0].Rejections)
+
+	res = results.list(msgs[2].Cid())
+	assert.True(t, res[0].Success)
+	assert.Equal(t, mtypes.PublishErrAlreadyInMpool, res[0].ErrorClass)
+
+	// connection error is a failure of node, not of message
+	node.EXPECT().MpoolBatchPushUntrusted(ctx, msgs[1:2]).Return(nil, &jsonrpc.ErrClient{})
+	thread.push(ctx, node, msgs[1:2])
+	res = results.list(msgs[1].Cid())
+	assert.False(t, res[0].Success)
+	assert.Equal(t, mtypes.PublishErrConnection, res[0].ErrorClass)
+	assert.Equal(t, 2, res[0].Rejections)
+	assert.Equal(t, 1, thread.health.info().ConsecutiveFailures)
+
+	// succeeded at last
+	node.EXPECT().MpoolBatchPushUntrusted(ctx, msgs[1:2]).Return(nil, nil)
+	thread.push(ctx, node, msgs[1:2])
+	res = results.list(msgs[1].Cid())
+	assert.True(t, res[0].Success)
+	assert.Equal(t, 0, res[0].Rejections)
+	assert.Len(t, rejected, 1)
+
+	assert.Empty(t, results.list(cid.Undef))
+}
@@ -4,16 +4,26 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strings"
+	"sort"
 	"sync"
 	"time"
 
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/venus-messager/config"
+	"github.com/filecoin-project/venus-messager/models/mtypes"
 	"github.com/filecoin-project/venus-messager/models/repo"
 	mpubsub "github.com/filecoin-project/venus-messager/publisher/pubsub"
 	v1 "github.com/filecoin-project/venus/venus-shared/api/chain/v1"
 	"github.com/filecoin-project/venus/venus-shared/types"
+	"github.com/filecoin-project/venus/venus-shared/types/messager"
 	"github.com/ipfs/go-cid"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+const (
+	mainNodeName    = "mainNode"
+	reasonNotRouted = "not matched by routing rule"
 )
 
 var errAlreadyInMpool = fmt.Errorf("already in mpool: validation failure")
@@ -25,10 +35,12 @@ type IMsgPublisher interface {
 }
 
 type P2pPublisher struct {
-	topic *pubsub.Topic
+	topic  *pubsub.Topic
+	self   peer.ID
+	router *Router
 }
 
-func NewP2pPublisher(pubsub mpubsub.IPubsuber, netName types.NetworkName) (*P2pPublisher, error) {
+func NewP2pPublisher(pubsub mpubsub.IPubsuber, netName types.NetworkName, router *Router) (*P2pPublisher, error) {
 	topicName := fmt.Sprintf("/fil/msgs/%s", netName)
 	topic, err := pubsub.GetTopic(topicName)
 	if err != nil {
@@ -36,12 +48,17 @@ func NewP2pPublisher(pubsub mpubsub.IPubsuber, netName types.NetworkName) (*P2pP
 	}
 
 	return &P2pPublisher{
-		topic: topic,
+		topic:  topic,
+		self:   pubsub.HostID(),
+		router: router,
 	}, nil
 }
 
 func (p *P2pPublisher) PublishMessages(ctx context.Context, msgs []*types.SignedMessage) error {
 	for _, msg := range msgs {
+		if !p.router.allowPubsub(p.router.ruleOf(ctx, msg.Message.From)) {
+			continue
+		}
 		msgb, err := msg.Serialize()
 		if err != nil {
 			return fmt.Errorf("marshal message %s failed %w", msg.Cid(), err)
@@ -53,39 +70,73 @@ func (p *P2pPublisher) PublishMessages(ctx context.Context, msgs []*types.Signed
 	return nil
 }
 
+type rpcNode struct {
+	name   string
+	url    string
+	thread *nodeThread
+	close  func()
+	health *nodeHealth
+}
+
+// ActiveNodeGetter is implemented by the main node which routes calls to one of several nodes, eg. the node pool
+// with failover enabled. Messages are pushed to the node it returns, so the routing rules are checked against the
+// node actually used.
+type ActiveNodeGetter interface {
+	ActiveNode() (name string, url string, client v1.FullNode)
+}
+
 type RpcPublisher struct {
-	ctx             context.Context
-	mainNodeThread  *nodeThread
-	nodeProvider    repo.INodeProvider
-	enableMultiNode bool
-
-	nodeThreads map[types.UUID]struct {
-		nodeThread *nodeThread
-		close      func()
-	}
-	lk sync.Mutex
-}
-
-func NewRpcPublisher(ctx context.Context, nodeClient v1.FullNode, nodeProvider repo.INodeProvider, enableMultiNode bool) *RpcPublisher {
-	nThread := newNodeThread(ctx, "mainNode", nodeClient)
-	return &RpcPublisher{
-		ctx:             ctx,
-		mainNodeThread:  nThread,
-		nodeProvider:    nodeProvider,
-		enableMultiNode: enableMultiNode,
-		nodeThreads: make(map[types.UUID]struct {
-			nodeThread *nodeThread
-			close      func()
-		}),
+	ctx            context.Context
+	mainNodeThread *nodeThread
+	activeNode     func() (string, string, v1.FullNode)
+	nodeProvider   repo.INodeProvider
+	cfg            *config.PublisherConfig
+	router         *Router
+
+	// nodes are the nodes in NodeRepo, thread is nil when failed to connect it
+	nodes   map[types.UUID]*rpcNode
+	results *publishResults
+	lk      sync.Mutex
+}
+
+func NewRpcPublisher(ctx context.Context, nodeClient v1.FullNode, nodeProvider repo.INodeProvider, cfg *config.PublisherConfig, router *Router) *RpcPublisher {
+	health := newNodeHealth(mainNodeName, 1, 0, 0)
+	results := newPublishResults(cfg.RejectThreshold)
+	nThread := newNodeThread(ctx, mainNodeName, nodeClient, health, results)
+	activeNode := func() (string, string, v1.FullNode) {
+		return mainNodeName, "", nodeClient
+	}
+	if getter, ok := nodeClient.(ActiveNodeGetter); ok {
+		activeNode = getter.ActiveNode
+	}
+	p := &RpcPublisher{
+		ctx:            ctx,
+		mainNodeThread: nThread,
+		activeNode:     activeNode,
+		nodeProvider:   nodeProvider,
+		cfg:            cfg,
+		router:         router,
+		nodes:          make(map[types.UUID]*rpcNode),
+		results:        results,
 
 		lk: sync.Mutex{},
 	}
+	if cfg.EnableMultiNode && cfg.NodeProbeInterval > 0 {
+		go p.probeLoop()
+	}
+	return p
 }
 
 func (p *RpcPublisher) PublishMessages(ctx context.Context, msgs []*types.SignedMessage) error {
-	p.mainNodeThread.HandleMsg(msgs)
+	groups := p.router.split(ctx, msgs)
+	name, url, client := p.activeNode()
+	for _, group := range groups {
+		if p.router.allowNode(group.rule, name, url) {
+			p.mainNodeThread.handleMsgTo(client, group.msgs)
+		}
+	}
 
-	if !p.enableMultiNode {
+	if !p.cfg.EnableMultiNode {
 		return nil
 	}
 
@@ -97,56 +148,260 @@ func (p *RpcPublisher) PublishMessages(ctx context.Context, msgs []*types.Signed
 	p.lk.Lock()
 	defer p.lk.Unlock()
 
+	p.syncNodes(nodeList)
+	for _, group := range groups {
+		nodes, _ := p.selectNodes(group.rule)
+		if len(nodes) == 0 && group.rule != nil {
+			log.Warnf("no node of tags %v to publish messages of group %s", group.rule.tags, group.rule.group)
+		}
+		for _, node := range nodes {
+			node.thread.HandleMsg(group.msgs)
+		}
+	}
+
+	return nil
+}
+
+// PublishRoute returns the nodes which the messages of from would be published to now, without publishing anything
+func (p *RpcPublisher) PublishRoute(ctx context.Context, from address.Address) (*mtypes.PublishRoute, error) {
+	rule := p.router.ruleOf(ctx, from)
+	route := &mtypes.PublishRoute{
+		From:   from,
+		Pubsub: p.cfg.EnableP2P && p.router.allowPubsub(rule),
+		Nodes:  []*mtypes.PublishRouteNode{},
+	}
+	if rule != nil {
+		route.Group = rule.group
+		route.Tags = rule.tags
+	}
+
+	// the main node maybe a pool, report the node actually used
+	name, url, _ := p.activeNode()
+	mainNode := &mtypes.PublishRouteNode{Name: name, Selected: true}
+	if !p.router.allowNode(rule, name, url) {
+		mainNode.Selected, mainNode.Reason = false, reasonNotRouted
+	}
+	route.Nodes = append(route.Nodes, mainNode)
+
+	if !p.cfg.EnableMultiNode {
+		return route, nil
+	}
+
+	nodeList, err := p.nodeProvider.ListNode()
+	if err != nil {
+		return nil, fmt.Errorf("list node fail %w", err)
+	}
+
+	p.lk.Lock()
+	defer p.lk.Unlock()
+
+	selected, skipped := p.selectNodes(rule)
+	for _, n := range selected {
+		route.Nodes = append(route.Nodes, &mtypes.PublishRouteNode{Name: n.name, Selected: true})
+	}
+	for _, n := range p.sortedNodes() {
+		if reason, ok := skipped[n.name]; ok {
+			route.Nodes = append(route.Nodes, &mtypes.PublishRouteNode{Name: n.name, Reason: reason})
+		}
+	}
+	// the nodes added after last publishing are connected in next publishing
+	for _, node := range nodeList {
+		if _, ok := p.nodes[node.ID]; !ok {
+			routeNode := &mtypes.PublishRouteNode{Name: node.Name, Selected: true, Reason: "not connected yet"}
+			if !p.router.allowNode(rule, node.Name, node.URL) {
+				routeNode.Selected, routeNode.Reason = false, reasonNotRouted
+			}
+			route.Nodes = append(route.Nodes, routeNode)
+		}
+	}
+
+	return route, nil
+}
+
+func (p *RpcPublisher) nodeWeight(name string) int {
+	if weight, ok := p.cfg.NodeWeights[name]; ok {
+		return weight
+	}
+	return 1
+}
+
+// syncNodes connect the nodes added and close the nodes removed, the nodes failed to connect are retried with backoff,
+// it must be called with lock held.
+func (p *RpcPublisher) syncNodes(nodeList []*messager.Node) {
+	now := time.Now()
 	nodesRemain := make(map[types.UUID]struct{})
 	for _, node := range nodeList {
-		threadStruct, ok := p.nodeThreads[node.ID]
 		nodesRemain[node.ID] = struct{}{}
+		n, ok := p.nodes[node.ID]
 		if !ok {
-			thrCtx, cancel := context.WithCancel(p.ctx) // nolint ignore lostcancel
-			cli, closer, err := v1.DialFullNodeRPC(thrCtx, node.URL, node.Token, nil)
+			n = &rpcNode{
+				name:   node.Name,
+				url:    node.URL,
+				health: newNodeHealth(node.Name, p.nodeWeight(node.Name), p.cfg.NodeFailureThreshold, p.cfg.NodeRetryBackoff),
+			}
+			p.nodes[node.ID] = n
+		}
+		n.url = node.URL
+		if n.thread != nil || !n.health.available(now) {
+			continue
+		}
+
+		thrCtx, cancel := context.WithCancel(p.ctx)
+		cli, closer, err := v1.DialFullNodeRPC(thrCtx, node.URL, node.Token, nil)
+		if err != nil {
+			cancel()
+			log.Warnf("connect node(%s) fail %v", node.Name, err)
+			n.health.failure(fmt.Errorf("connect failed: %w", err))
+			continue
+		}
+
+		nodeName := node.Name
+		n.thread = newNodeThread(thrCtx, nodeName, cli, n.health, p.results)
+		n.close = func() {
+			cancel()
+			closer()
+			log.Debugf("close node thread %s", nodeName)
+		}
+	}
+
+	for id, n := range p.nodes {
+		if _, ok := nodesRemain[id]; !ok {
+			if n.close != nil {
+				n.close()
+			}
+			delete(p.nodes, id)
+		}
+	}
+}
+
+// selectNodes returns the connected and healthy nodes allowed by rule ordered by weight, the backups are used only
+// when no other node is available, the reasons of nodes not selected are returned too. The excluded nodes are
+// used again after recovered by probing. It must be called with lock held.
+func (p *RpcPublisher) selectNodes(rule *routingRule) ([]*rpcNode, map[string]string) {
+	skipped := make(map[string]string)
+	var nodes, backups []*rpcNode
+	for _, n := range p.sortedNodes() {
+		switch {
+		case !p.router.allowNode(rule, n.name, n.url):
+			skipped[n.name] = reasonNotRouted
+		case n.thread == nil:
+			skipped[n.name] = "not connected"
+		case !n.health.healthy():
+			skipped[n.name] = "unhealthy"
+		case n.health.weight > 0:
+			nodes = append(nodes, n)
+		default:
+			backups = append(backups, n)
+		}
+	}
+	if len(nodes) == 0 {
+		nodes = backups
+	} else {
+		for _, n := range backups {
+			skipped[n.name] = "backup"
+		}
+	}
+	if p.cfg.MaxPublishNodes > 0 && len(nodes) > p.cfg.MaxPublishNodes {
+		for _, n := range nodes[p.cfg.MaxPublishNodes:] {
+			skipped[n.name] = "exceed max publish nodes"
+		}
+		nodes = nodes[:p.cfg.MaxPublishNodes]
+	}
+	return nodes, skipped
+}
+
+func (p *RpcPublisher) sortedNodes() []*rpcNode {
+	nodes := make([]*rpcNode, 0, len(p.nodes))
+	for _, n := range p.nodes {
+		nodes = append(nodes, n)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].health.weight != nodes[j].health.weight {
+			return nodes[i].health.weight > nodes[j].health.weight
+		}
+		return nodes[i].name < nodes[j].name
+	})
+	return nodes
+}
+
+// probeLoop check the head of nodes periodically, the excluded nodes are probed when their backoff elapsed, it is
+// the only one taking the retry of excluded nodes, and they are used for publishing after the probe succeeded
+func (p *RpcPublisher) probeLoop() {
+	tm := time.NewTicker(p.cfg.NodeProbeInterval)
+	defer tm.Stop()
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-tm.C:
+			nodeList, err := p.nodeProvider.ListNode()
 			if err != nil {
-				log.Warnf("connect node(%s) fail %v", node.Name, err)
+				log.Warnf("list node fail %v", err)
 				continue
 			}
 
-			nodeName := node.Name
-			threadStruct = struct {
-				nodeThread *nodeThread
-				close      func()
-			}{
-				nodeThread: newNodeThread(thrCtx, nodeName, cli),
-				close: func() {
-					cancel()
-					closer()
-					log.Debugf("close node thread %s", nodeName)
-				},
+			now := time.Now()
+			p.lk.Lock()
+			p.syncNodes(nodeList)
+			var nodes []*rpcNode
+			for _, n := range p.nodes {
+				if n.thread != nil && n.health.tryUse(now) {
+					nodes = append(nodes, n)
+				}
+			}
+			p.lk.Unlock()
+
+			for _, n := range nodes {
+				go n.thread.probe(p.ctx, p.cfg.NodeProbeInterval)
 			}
-			p.nodeThreads[node.ID] = threadStruct
 		}
-		threadStruct.nodeThread.HandleMsg(msgs)
 	}
+}
 
-	for id, threadStruct := range p.nodeThreads {
-		if _, ok := nodesRemain[id]; !ok {
-			threadStruct.close()
-			delete(p.nodeThreads, id)
-		}
+// ListNodeHealth returns the health of main node and the nodes in NodeRepo
+func (p *RpcPublisher) ListNodeHealth(ctx context.Context) ([]*mtypes.PublishNodeHealth, error) {
+	p.lk.Lock()
+	defer p.lk.Unlock()
+
+	healths := []*mtypes.PublishNodeHealth{p.mainNodeThread.health.info()}
+	for _, n := range p.sortedNodes() {
+		healths = append(healths, n.health.info())
 	}
+	return healths, nil
+}
+
+// ListPublishResult returns the results of pushing the signed message to every node
+func (p *RpcPublisher) ListPublishResult(ctx context.Context, signedCid cid.Cid) ([]*mtypes.PublishResult, error) {
+	return p.results.list(signedCid), nil
+}
+
+// OnRejected register f which is called when a message is rejected by a node for RejectThreshold times in a row
+func (p *RpcPublisher) OnRejected(f func(signedCid cid.Cid, err *PublishError)) {
+	p.results.setOnRejected(f)
+}
 
-	return nil // nolint ignore lostcancel
+// pushTask is the messages to push by client
+type pushTask struct {
+	client v1.FullNode
+	msgs   []*types.SignedMessage
 }
 
 type nodeThread struct {
 	name       string
 	nodeClient v1.FullNode
-	msgChan    chan []*types.SignedMessage
+	msgChan    chan *pushTask
+	health     *nodeHealth
+	results    *publishResults
 }
 
-func newNodeThread(ctx context.Context, name string, nodeClient v1.FullNode) *nodeThread {
+func newNodeThread(ctx context.Context, name string, nodeClient v1.FullNode, health *nodeHealth, results *publishResults) *nodeThread {
 	t := &nodeThread{
 		name:       name,
 		nodeClient: nodeClient,
-		msgChan:    make(chan []*types.SignedMessage, 30),
+		msgChan:    make(chan *pushTask, 30),
+		health:     health,
+		results:    results,
 	}
 	go t.run(ctx)
 	return t
@@ -158,22 +413,86 @@ func (n *nodeThread) run(ctx context.Context) {
 			select {
 			case <-ctx.Done():
 				return
-			case msgs := <-n.msgChan:
-				if _, err := n.nodeClient.MpoolBatchPushUntrusted(ctx, msgs); err != nil {
-					//skip error
-					if !strings.Contains(err.Error(), errMinimumNonce.Error()) && !strings.Contains(err.Error(), errAlreadyInMpool.Error()) {
-						log.Errorf("push message to node %s failed %v", n.name, err)
-					} else {
-						log.Debugf("push message to node failed %v", err)
-					}
-				}
+			case task := <-n.msgChan:
+				n.push(ctx, task.client, task.msgs)
 			}
 		}
 	}()
 }
 
+func (n *nodeThread) push(ctx context.Context, client v1.FullNode, msgs []*types.SignedMessage) {
+	start := time.Now()
+	_, err := client.MpoolBatchPushUntrusted(ctx, msgs)
+	if err == nil {
+		n.health.success(time.Since(start))
+		for _, msg := range msgs {
+			n.results.record(n.name, msg, nil)
+		}
+		return
+	}
+
+	pubErr := newPublishError(n.name, err)
+	if pubErr.Class == mtypes.PublishErrConnection {
+		log.Errorf("push message to node %s failed %v", n.name, err)
+		if isNodeFailure(ctx, pubErr) {
+			n.health.failure(err)
+		}
+		for _, msg := range msgs {
+			n.results.record(n.name, msg, pubErr)
+		}
+		return
+	}
+	n.health.success(time.Since(start))
+
+	// batch push stops at the first failed message, push one by one to get the result of every message
+	if len(msgs) > 1 {
+		for _, msg := range msgs {
+			if _, err := client.MpoolPushUntrusted(ctx, msg); err != nil {
+				pubErr = newPublishError(n.name, err)
+				if isNodeFailure(ctx, pubErr) {
+					n.health.failure(err)
+				}
+				n.logPublishError(msg, pubErr)
+				n.results.record(n.name, msg, pubErr)
+				continue
+			}
+			n.results.record(n.name, msg, nil)
+		}
+		return
+	}
+	n.logPublishError(msgs[0], pubErr)
+	n.results.record(n.name, msgs[0], pubErr)
+}
+
+func (n *nodeThread) logPublishError(msg *types.SignedMessage, pubErr *PublishError) {
+	if pubErr.Class.Rejected() {
+		log.Warnf("message %s rejected by node %s: %v", msg.Cid(), n.name, pubErr)
+		return
+	}
+	log.Debugf("push message %s to node %s failed %v", msg.Cid(), n.name, pubErr)
+}
+
+func (n *nodeThread) probe(ctx context.Context, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	head, err := n.nodeClient.ChainHead(ctx)
+	if err != nil {
+		n.health.failure(fmt.Errorf("probe failed: %w", err))
+		return
+	}
+	n.health.success(time.Since(start))
+	n.health.setHeight(head.Height())
+}
+
 func (n *nodeThread) HandleMsg(msgs []*types.SignedMessage) {
-	n.msgChan <- msgs
+	n.handleMsgTo(n.nodeClient, msgs)
+}
+
+// handleMsgTo pushes msgs by client instead of the client of thread, it is used by the main node which maybe a pool
+func (n *nodeThread) handleMsgTo(client v1.FullNode, msgs []*types.SignedMessage) {
+	n.msgChan <- &pushTask{client: client, msgs: msgs}
 }
 
 type MergePublisher struct {
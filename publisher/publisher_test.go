@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/filecoin-project/venus-messager/config"
 	"github.com/filecoin-project/venus-messager/testhelper"
 	mockV1 "github.com/filecoin-project/venus/venus-shared/api/chain/v1/mock"
 	"github.com/filecoin-project/venus/venus-shared/types"
@@ -23,12 +24,15 @@ func TestMainNodePublishMessage(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mainNode := mockV1.NewMockFullNode(ctrl)
 
-	rpcPublisher := NewRpcPublisher(ctx, mainNode, nil, false)
+	cfg := &config.PublisherConfig{}
+	router, err := NewRouter(cfg, nil)
+	assert.NoError(t, err)
+	rpcPublisher := NewRpcPublisher(ctx, mainNode, nil, cfg, router)
 	publisher := NewMergePublisher(ctx, rpcPublisher)
 	msgs := testhelper.NewShareSignedMessages(10)
 
 	mainNode.EXPECT().MpoolBatchPushUntrusted(ctx, msgs).Return(nil, nil).Times(1)
-	err := publisher.PublishMessages(ctx, msgs)
+	err = publisher.PublishMessages(ctx, msgs)
 	assert.NoError(t, err)
 	runtime.Gosched()
 	time.Sleep(1 * time.Second)
@@ -61,7 +65,10 @@ func TestMultiNodePublishMessage(t *testing.T) {
 	}
 
 	nodeProvider := testhelper.NewMockNodeRepo(ctrl)
-	rpcPublisher := NewRpcPublisher(ctx, mainNode, nodeProvider, true)
+	cfg := &config.PublisherConfig{EnableMultiNode: true}
+	router, err := NewRouter(cfg, nil)
+	assert.NoError(t, err)
+	rpcPublisher := NewRpcPublisher(ctx, mainNode, nodeProvider, cfg, router)
 
 	t.Run("publish message to multi node", func(t *testing.T) {
 		nodeProvider.EXPECT().ListNode().Return(nodes[:3], nil).Times(1)
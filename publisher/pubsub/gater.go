@@ -0,0 +1,140 @@
+package pubsub
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/connmgr"
+	"github.com/libp2p/go-libp2p/core/control"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+)
+
+// banGater blocks the connections from and to the banned peers, the banned peers are persisted in file
+// if it is not empty
+type banGater struct {
+	file string
+
+	lk     sync.RWMutex
+	banned map[peer.ID]time.Time
+}
+
+var _ connmgr.ConnectionGater = (*banGater)(nil)
+
+func newBanGater(file string) (*banGater, error) {
+	g := &banGater{file: file, banned: make(map[peer.ID]time.Time)}
+	if len(file) == 0 {
+		return g, nil
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return g, nil
+		}
+		return nil, err
+	}
+	var banned []*mtypes.BannedPeer
+	if err := json.Unmarshal(data, &banned); err != nil {
+		return nil, fmt.Errorf("failed to decode banned peers file %s: %w", file, err)
+	}
+	for _, p := range banned {
+		g.banned[p.ID] = p.BannedAt
+	}
+	return g, nil
+}
+
+func (g *banGater) ban(id peer.ID) error {
+	g.lk.Lock()
+	defer g.lk.Unlock()
+
+	if _, ok := g.banned[id]; ok {
+		return nil
+	}
+	g.banned[id] = time.Now()
+	if err := g.save(); err != nil {
+		delete(g.banned, id)
+		return err
+	}
+	return nil
+}
+
+func (g *banGater) unban(id peer.ID) error {
+	g.lk.Lock()
+	defer g.lk.Unlock()
+
+	bannedAt, ok := g.banned[id]
+	if !ok {
+		return fmt.Errorf("peer %s is not banned", id)
+	}
+	delete(g.banned, id)
+	if err := g.save(); err != nil {
+		g.banned[id] = bannedAt
+		return err
+	}
+	return nil
+}
+
+func (g *banGater) list() []*mtypes.BannedPeer {
+	g.lk.RLock()
+	defer g.lk.RUnlock()
+	return g.sorted()
+}
+
+// sorted returns the banned peers in order of banned time, it must be called with lock held
+func (g *banGater) sorted() []*mtypes.BannedPeer {
+	banned := make([]*mtypes.BannedPeer, 0, len(g.banned))
+	for id, bannedAt := range g.banned {
+		banned = append(banned, &mtypes.BannedPeer{ID: id, BannedAt: bannedAt})
+	}
+	sort.Slice(banned, func(i, j int) bool {
+		return banned[i].BannedAt.Before(banned[j].BannedAt)
+	})
+	return banned
+}
+
+// save must be called with lock held
+func (g *banGater) save() error {
+	if len(g.file) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(g.sorted())
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(g.file, data, 0o644)
+}
+
+func (g *banGater) isBanned(id peer.ID) bool {
+	g.lk.RLock()
+	defer g.lk.RUnlock()
+	_, ok := g.banned[id]
+	return ok
+}
+
+func (g *banGater) InterceptPeerDial(p peer.ID) bool {
+	return !g.isBanned(p)
+}
+
+func (g *banGater) InterceptAddrDial(p peer.ID, _ ma.Multiaddr) bool {
+	return !g.isBanned(p)
+}
+
+func (g *banGater) InterceptAccept(network.ConnMultiaddrs) bool {
+	return true
+}
+
+func (g *banGater) InterceptSecured(_ network.Direction, p peer.ID, _ network.ConnMultiaddrs) bool {
+	return !g.isBanned(p)
+}
+
+func (g *banGater) InterceptUpgraded(network.Conn) (bool, control.DisconnectReason) {
+	return true, 0
+}
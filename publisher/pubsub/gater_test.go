@@ -0,0 +1,96 @@
+package pubsub
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/venus-messager/filestore"
+)
+
+func TestPeerManagement(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	netCfg := newTestNetConfig()
+	files := PersistFiles{BannedPeers: filepath.Join(t.TempDir(), filestore.BannedPeersFile)}
+
+	ps2, err := NewPubsub(ctx, "test_net_name", netCfg, nil, PersistFiles{})
+	require.NoError(t, err)
+	pi2, err := ps2.AddrListen(ctx)
+	require.NoError(t, err)
+
+	protectedCfg := *netCfg
+	protectedCfg.ProtectedPeers = []string{pi2.ID.String()}
+	ps1, err := NewPubsub(ctx, "test_net_name", &protectedCfg, nil, files)
+	require.NoError(t, err)
+	require.NoError(t, ps1.Connect(ctx, pi2))
+
+	_, err = ps1.GetTopic("test")
+	require.NoError(t, err)
+	topic, err := ps2.GetTopic("test")
+	require.NoError(t, err)
+	_, err = topic.Subscribe()
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		peers, err := ps1.PeersInfo(ctx)
+		require.NoError(t, err)
+		return len(peers) == 1 && len(peers[0].Topics) == 1
+	}, 10*time.Second, 100*time.Millisecond)
+
+	peers, err := ps1.PeersInfo(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, pi2.ID, peers[0].ID)
+	assert.Equal(t, "venus-messager", peers[0].Agent)
+	assert.Equal(t, []string{"test"}, peers[0].Topics)
+	assert.True(t, peers[0].Protected)
+	assert.NotEmpty(t, peers[0].Protocols)
+
+	require.NoError(t, ps1.Disconnect(ctx, pi2.ID))
+	assert.Equal(t, network.NotConnected, ps1.host.Network().Connectedness(pi2.ID))
+	assert.Error(t, ps1.Disconnect(ctx, pi2.ID))
+
+	// banned peers can't be connected, and they are banned after restarting
+	require.NoError(t, ps1.Connect(ctx, pi2))
+	require.NoError(t, ps1.BanPeer(ctx, pi2.ID))
+	assert.Equal(t, network.NotConnected, ps1.host.Network().Connectedness(pi2.ID))
+	assert.Error(t, ps1.Connect(ctx, pi2))
+
+	ps3, err := NewPubsub(ctx, "test_net_name", netCfg, nil, files)
+	require.NoError(t, err)
+	banned, err := ps3.ListBannedPeers(ctx)
+	require.NoError(t, err)
+	require.Len(t, banned, 1)
+	assert.Equal(t, pi2.ID, banned[0].ID)
+	assert.Error(t, ps3.Connect(ctx, pi2))
+
+	require.NoError(t, ps3.UnbanPeer(ctx, pi2.ID))
+	assert.Error(t, ps3.UnbanPeer(ctx, pi2.ID))
+	require.NoError(t, ps3.Connect(ctx, pi2))
+	gater, err := newBanGater(files.BannedPeers)
+	require.NoError(t, err)
+	assert.Empty(t, gater.list())
+}
+
+func TestParseProtectedPeers(t *testing.T) {
+	id := "12D3KooWGzxzKZYveHXtpG6AsrUJBcWxHBFS2HsEoGTxrMLvKXtf"
+	peers, err := parseProtectedPeers([]string{id, "/ip4/127.0.0.1/tcp/1234/p2p/" + id, "/ip4/127.0.0.1/tcp/1235/p2p/" + id})
+	require.NoError(t, err)
+	// the addresses of the same peer are merged
+	require.Len(t, peers, 1)
+	assert.Equal(t, id, peers[0].ID.String())
+	assert.Len(t, peers[0].Addrs, 2)
+
+	_, err = parseProtectedPeers([]string{"invalid"})
+	assert.Error(t, err)
+
+	netCfg := newTestNetConfig()
+	netCfg.ConnMgrLow = 10
+	netCfg.ConnMgrHigh = 5
+	_, err = NewPubsub(context.Background(), "test_net_name", netCfg, nil, PersistFiles{})
+	assert.Error(t, err)
+}
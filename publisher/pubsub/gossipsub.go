@@ -0,0 +1,118 @@
+package pubsub
+
+import (
+	"fmt"
+	"time"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/filecoin-project/venus-messager/config"
+)
+
+// bootstrapperScore is the app specific score of bootstrappers, which are not pruned and accepted PX from
+const bootstrapperScore = 2500
+
+// gossipSubOptions validates cfg and returns the options of gossipsub router, msgTopic is the topic to score.
+func gossipSubOptions(cfg *config.GossipSubConfig, msgTopic string, bootstrappers []peer.AddrInfo) ([]pubsub.Option, error) {
+	if cfg.HeartbeatInterval <= 0 {
+		return nil, fmt.Errorf("gossipsub heartbeat interval must be positive")
+	}
+	if cfg.Dlo <= 0 || cfg.Dlo > cfg.D || cfg.D > cfg.Dhi {
+		return nil, fmt.Errorf("gossipsub degree must be 0 < dlo(%d) <= d(%d) <= dhi(%d)", cfg.Dlo, cfg.D, cfg.Dhi)
+	}
+	directPeers, err := parseAddrInfos(cfg.DirectPeers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse gossipsub direct peers: %w", err)
+	}
+
+	params := pubsub.DefaultGossipSubParams()
+	params.HeartbeatInterval = cfg.HeartbeatInterval
+	params.D = cfg.D
+	params.Dlo = cfg.Dlo
+	params.Dhi = cfg.Dhi
+	// Dout must be less than Dlo and at most D/2, Dscore must not exceed D
+	params.Dout = minInt(params.Dout, cfg.Dlo-1, cfg.D/2)
+	params.Dscore = minInt(params.Dscore, cfg.D)
+
+	options := []pubsub.Option{
+		pubsub.WithGossipSubParams(params),
+		pubsub.WithFloodPublish(cfg.FloodPublish),
+	}
+	if len(directPeers) > 0 {
+		options = append(options, pubsub.WithDirectPeers(directPeers))
+	}
+	if cfg.EnablePeerScore {
+		options = append(options, pubsub.WithPeerScore(
+			peerScoreParams(cfg, msgTopic, bootstrappers),
+			&pubsub.PeerScoreThresholds{
+				GossipThreshold:             cfg.GossipThreshold,
+				PublishThreshold:            cfg.PublishThreshold,
+				GraylistThreshold:           cfg.GraylistThreshold,
+				AcceptPXThreshold:           cfg.AcceptPXThreshold,
+				OpportunisticGraftThreshold: cfg.OpportunisticGraftThreshold,
+			},
+		))
+	}
+	return options, nil
+}
+
+// peerScoreParams is the same as the scoring of messages topic in venus
+func peerScoreParams(cfg *config.GossipSubConfig, msgTopic string, bootstrappers []peer.AddrInfo) *pubsub.PeerScoreParams {
+	bootstrapperSet := make(map[peer.ID]struct{}, len(bootstrappers))
+	for _, pi := range bootstrappers {
+		bootstrapperSet[pi.ID] = struct{}{}
+	}
+
+	return &pubsub.PeerScoreParams{
+		AppSpecificScore: func(p peer.ID) float64 {
+			if _, ok := bootstrapperSet[p]; ok {
+				return bootstrapperScore
+			}
+			return 0
+		},
+		AppSpecificWeight: 1,
+
+		// penalties apply when there are more than 5 peers from the same ip
+		IPColocationFactorThreshold: 5,
+		IPColocationFactorWeight:    -100,
+
+		// behavioural penalties decay after 1 hour
+		BehaviourPenaltyThreshold: 6,
+		BehaviourPenaltyWeight:    -10,
+		BehaviourPenaltyDecay:     pubsub.ScoreParameterDecay(time.Hour),
+
+		DecayInterval: pubsub.DefaultDecayInterval,
+		DecayToZero:   pubsub.DefaultDecayToZero,
+		RetainScore:   6 * time.Hour,
+
+		Topics: map[string]*pubsub.TopicScoreParams{
+			msgTopic: {
+				TopicWeight: cfg.MessageTopicWeight,
+
+				// 1 tick per second, maxes at 1 hour
+				TimeInMeshWeight:  0.0002778,
+				TimeInMeshQuantum: time.Second,
+				TimeInMeshCap:     1,
+
+				// deliveries decay after 10 minutes, cap at 100 messages
+				FirstMessageDeliveriesWeight: 0.5,
+				FirstMessageDeliveriesDecay:  pubsub.ScoreParameterDecay(10 * time.Minute),
+				FirstMessageDeliveriesCap:    100,
+
+				// invalid messages decay after 1 hour
+				InvalidMessageDeliveriesWeight: -1000,
+				InvalidMessageDeliveriesDecay:  pubsub.ScoreParameterDecay(time.Hour),
+			},
+		},
+	}
+}
+
+func minInt(first int, others ...int) int {
+	for _, v := range others {
+		if v < first {
+			first = v
+		}
+	}
+	return first
+}
@@ -0,0 +1,57 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/venus-messager/config"
+)
+
+func TestGossipSubOptions(t *testing.T) {
+	newCfg := func(modify func(cfg *config.GossipSubConfig)) *config.GossipSubConfig {
+		cfg := config.DefaultConfig().Libp2pNet.GossipSub
+		modify(&cfg)
+		return &cfg
+	}
+
+	opts, err := gossipSubOptions(newCfg(func(cfg *config.GossipSubConfig) {}), "/fil/msgs/test", nil)
+	require.NoError(t, err)
+	assert.Len(t, opts, 2)
+
+	opts, err = gossipSubOptions(newCfg(func(cfg *config.GossipSubConfig) {
+		cfg.DirectPeers = []string{"/ip4/127.0.0.1/tcp/1234/p2p/12D3KooWGzxzKZYveHXtpG6AsrUJBcWxHBFS2HsEoGTxrMLvKXtf"}
+		cfg.EnablePeerScore = true
+	}), "/fil/msgs/test", nil)
+	require.NoError(t, err)
+	assert.Len(t, opts, 4)
+
+	for _, modify := range []func(cfg *config.GossipSubConfig){
+		func(cfg *config.GossipSubConfig) { cfg.HeartbeatInterval = 0 },
+		func(cfg *config.GossipSubConfig) { cfg.Dlo = 0 },
+		func(cfg *config.GossipSubConfig) { cfg.Dlo = cfg.D + 1 },
+		func(cfg *config.GossipSubConfig) { cfg.Dhi = cfg.D - 1 },
+		func(cfg *config.GossipSubConfig) { cfg.DirectPeers = []string{"invalid"} },
+	} {
+		_, err := gossipSubOptions(newCfg(modify), "/fil/msgs/test", nil)
+		assert.Error(t, err)
+	}
+
+	// the thresholds are validated when creating pubsub
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	netCfg := newTestNetConfig()
+	netCfg.GossipSub.EnablePeerScore = true
+	netCfg.GossipSub.GossipThreshold = 1
+	_, err = NewPubsub(ctx, "test_net_name", netCfg, nil, PersistFiles{})
+	assert.Error(t, err)
+
+	netCfg = newTestNetConfig()
+	netCfg.GossipSub.EnablePeerScore = true
+	netCfg.GossipSub.HeartbeatInterval = time.Second
+	_, err = NewPubsub(ctx, "test_net_name", netCfg, nil, PersistFiles{})
+	assert.NoError(t, err)
+}
@@ -4,9 +4,14 @@ import (
 	"context"
 	"fmt"
 	"runtime"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/filecoin-project/venus-messager/config"
+	"github.com/filecoin-project/venus-messager/filestore"
+	"github.com/filecoin-project/venus-messager/models/mtypes"
 	"github.com/filecoin-project/venus/fixtures/networks"
 	"github.com/filecoin-project/venus/pkg/net"
 	"github.com/filecoin-project/venus/venus-shared/types"
@@ -14,8 +19,13 @@ import (
 	"github.com/libp2p/go-libp2p"
 	dht "github.com/libp2p/go-libp2p-kad-dht"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/network"
 	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/peerstore"
+	"github.com/libp2p/go-libp2p/p2p/host/autorelay"
 	routedhost "github.com/libp2p/go-libp2p/p2p/host/routed"
+	"github.com/libp2p/go-libp2p/p2p/net/connmgr"
 	swarm "github.com/libp2p/go-libp2p/p2p/net/swarm"
 	ma "github.com/multiformats/go-multiaddr"
 	"github.com/pelletier/go-toml"
@@ -31,34 +41,64 @@ type INet interface {
 	FindPeer(ctx context.Context, peerID peer.ID) (peer.AddrInfo, error)
 	Peers(ctx context.Context) ([]peer.AddrInfo, error)
 	AddrListen(ctx context.Context) (peer.AddrInfo, error)
+	ListenInfo(ctx context.Context) (*mtypes.NetListenInfo, error)
+	PeersInfo(ctx context.Context) ([]*mtypes.PeerInfo, error)
+	Disconnect(ctx context.Context, peerID peer.ID) error
+	BanPeer(ctx context.Context, peerID peer.ID) error
+	UnbanPeer(ctx context.Context, peerID peer.ID) error
+	ListBannedPeers(ctx context.Context) ([]*mtypes.BannedPeer, error)
 }
 
 type IPubsuber interface {
 	GetTopic(topic string) (*pubsub.Topic, error)
+	// HostID returns the peer id of self
+	HostID() peer.ID
 }
 
 var _ INet = &PubSub{}
 var _ IPubsuber = &PubSub{}
 
+// protectedTag is the tag of the peers protected by config
+const protectedTag = "config-protected"
+
+// PersistFiles are the files to persist the state of host, the state is not persisted if the file is empty
+type PersistFiles struct {
+	Peerstore   string
+	BannedPeers string
+}
+
 type PubSub struct {
+	netCfg           *config.Libp2pNetConfig
 	host             types.RawHost
 	pubsub           *pubsub.PubSub
 	dht              *dht.IpfsDHT
 	bootstrappers    []peer.AddrInfo
+	protectedPeers   []peer.AddrInfo
+	gater            *banGater
 	period           time.Duration
 	timeout          time.Duration
 	minPeerThreshold int
 	expanding        chan struct{}
+
+	lk sync.Mutex
+	// topics are the names of topics joined
+	topics map[string]struct{}
+
+	// peerstoreFile is empty when the peers are not persisted
+	peerstoreFile string
+	savedPeers    []peer.AddrInfo
 }
 
+// NewPubsub creates the libp2p host with identity, a random identity is used when it is nil.
+// The connected peers are saved and reconnected at next startup, the banned peers are saved too.
 func NewPubsub(ctx context.Context,
-	listenAddress string,
 	networkName types.NetworkName,
-	bootstrap []string,
-	period time.Duration,
-	threshold int,
+	netCfg *config.Libp2pNetConfig,
+	identity crypto.PrivKey,
+	files PersistFiles,
 ) (*PubSub, error) {
 	finalTimeout, finalPeriod, finalThreshold := time.Second*30, time.Second*30, 1
+	bootstrap := append([]string{}, netCfg.BootstrapAddresses...)
 
 	netconfig, err := networks.GetNetworkConfig(string(networkName))
 	if err != nil {
@@ -69,21 +109,16 @@ func NewPubsub(ctx context.Context,
 		finalThreshold = netconfig.Bootstrap.MinPeerThreshold
 		_ = toml.Unmarshal([]byte(netconfig.Bootstrap.Period), &finalPeriod)
 	}
-	if period != 0 {
-		finalPeriod = period
+	if netCfg.ExpandPeriod != 0 {
+		finalPeriod = netCfg.ExpandPeriod
 	}
-	if threshold != 0 {
-		finalThreshold = threshold
+	if netCfg.MinPeerThreshold != 0 {
+		finalThreshold = netCfg.MinPeerThreshold
 	}
 	if finalTimeout > finalPeriod {
 		finalTimeout = finalPeriod
 	}
 
-	rawHost, err := buildHost(ctx, listenAddress)
-	if err != nil {
-		return nil, err
-	}
-
 	bootstrapPeersres := make([]peer.AddrInfo, len(bootstrap))
 	for i, addr := range bootstrap {
 		peerInfo, err := peer.AddrInfoFromString(addr)
@@ -92,6 +127,42 @@ func NewPubsub(ctx context.Context,
 		}
 		bootstrapPeersres[i] = *peerInfo
 	}
+	gossipSubOpts, err := gossipSubOptions(&netCfg.GossipSub, types.MessageTopic(string(networkName)), bootstrapPeersres)
+	if err != nil {
+		return nil, err
+	}
+	protectedPeers, err := parseProtectedPeers(netCfg.ProtectedPeers)
+	if err != nil {
+		return nil, err
+	}
+	gater, err := newBanGater(files.BannedPeers)
+	if err != nil {
+		return nil, err
+	}
+
+	rawHost, err := buildHost(ctx, netCfg, identity, gater)
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("libp2p host id %s", rawHost.ID())
+
+	var protectedWithAddrs []peer.AddrInfo
+	for _, pi := range protectedPeers {
+		rawHost.ConnManager().Protect(pi.ID, protectedTag)
+		if len(pi.Addrs) > 0 {
+			rawHost.Peerstore().AddAddrs(pi.ID, pi.Addrs, peerstore.PermanentAddrTTL)
+			protectedWithAddrs = append(protectedWithAddrs, pi)
+		}
+	}
+
+	savedPeers, err := loadPeers(files.Peerstore)
+	if err != nil {
+		log.Warnf("failed to load saved peers: %s", err)
+	}
+	for _, pi := range savedPeers {
+		rawHost.Peerstore().AddAddrs(pi.ID, pi.Addrs, peerstore.AddressTTL)
+	}
+
 	router, err := makeDHT(ctx, rawHost, string(networkName), bootstrapPeersres)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create DHT: %s", err)
@@ -99,10 +170,7 @@ func NewPubsub(ctx context.Context,
 
 	peerHost := routedhost.Wrap(rawHost, router)
 
-	pubsub.GossipSubHeartbeatInterval = 100 * time.Millisecond
 	options := []pubsub.Option{
-		// Gossipsubv1.1 configuration
-		pubsub.WithFloodPublish(true),
 		//  buffer, 32 -> 10K
 		pubsub.WithValidateQueueSize(10 << 10),
 		//  worker, 1x cpu -> 2x cpu
@@ -111,6 +179,7 @@ func NewPubsub(ctx context.Context,
 		pubsub.WithValidateThrottle(16 << 10),
 		pubsub.WithMessageSigning(true),
 	}
+	options = append(options, gossipSubOpts...)
 
 	gsub, err := pubsub.NewGossipSub(ctx, peerHost, options...)
 	if err != nil {
@@ -118,14 +187,20 @@ func NewPubsub(ctx context.Context,
 	}
 
 	pubsub := PubSub{
+		netCfg:           netCfg,
 		host:             peerHost,
 		pubsub:           gsub,
 		bootstrappers:    bootstrapPeersres,
+		protectedPeers:   protectedWithAddrs,
+		gater:            gater,
+		topics:           make(map[string]struct{}),
 		dht:              router,
 		expanding:        make(chan struct{}, 1),
 		minPeerThreshold: finalThreshold,
 		period:           finalPeriod,
 		timeout:          finalTimeout,
+		peerstoreFile:    files.Peerstore,
+		savedPeers:       savedPeers,
 	}
 
 	go pubsub.run(ctx)
@@ -133,10 +208,22 @@ func NewPubsub(ctx context.Context,
 }
 
 func (m *PubSub) GetTopic(topic string) (*pubsub.Topic, error) {
-	return m.pubsub.Join(topic)
+	t, err := m.pubsub.Join(topic)
+	if err != nil {
+		return nil, err
+	}
+	m.lk.Lock()
+	m.topics[topic] = struct{}{}
+	m.lk.Unlock()
+	return t, nil
+}
+
+func (m *PubSub) HostID() peer.ID {
+	return m.host.ID()
 }
 
 func (m *PubSub) run(ctx context.Context) {
+	m.connectSavedPeers(ctx)
 	err := m.connectBootstrap(ctx)
 	if err != nil {
 		log.Errorf("connect bootstrap failed %s", err)
@@ -152,9 +239,15 @@ func (m *PubSub) run(ctx context.Context) {
 				log.Debug("peer count %d is less than threshold %d, expanding", pcount, m.minPeerThreshold)
 				m.expandPeers()
 			}
+			if err := m.savePeers(); err != nil {
+				log.Warnf("failed to save peers: %s", err)
+			}
 
 		case <-ctx.Done():
 			log.Warnf("stop expand peers: %v", ctx.Err())
+			if err := m.savePeers(); err != nil {
+				log.Warnf("failed to save peers: %s", err)
+			}
 			return
 		}
 	}
@@ -180,6 +273,71 @@ func (m *PubSub) Peers(ctx context.Context) ([]peer.AddrInfo, error) {
 	return peers, nil
 }
 
+// PeersInfo returns the connected peers with the agent, latency, protocols and the gossipsub topics joined
+func (m *PubSub) PeersInfo(ctx context.Context) ([]*mtypes.PeerInfo, error) {
+	topics := make(map[peer.ID][]string)
+	m.lk.Lock()
+	for topic := range m.topics {
+		for _, id := range m.pubsub.ListPeers(topic) {
+			topics[id] = append(topics[id], topic)
+		}
+	}
+	m.lk.Unlock()
+	for _, t := range topics {
+		sort.Strings(t)
+	}
+
+	ps := m.host.Peerstore()
+	peers := m.host.Network().Peers()
+	infos := make([]*mtypes.PeerInfo, 0, len(peers))
+	for _, id := range peers {
+		info := &mtypes.PeerInfo{
+			ID:        id,
+			Latency:   ps.LatencyEWMA(id),
+			Topics:    topics[id],
+			Protected: m.host.ConnManager().IsProtected(id, ""),
+		}
+		for _, conn := range m.host.Network().ConnsToPeer(id) {
+			info.Addrs = append(info.Addrs, conn.RemoteMultiaddr())
+		}
+		if agent, err := ps.Get(id, "AgentVersion"); err == nil {
+			info.Agent, _ = agent.(string)
+		}
+		if protocols, err := ps.GetProtocols(id); err == nil {
+			sort.Strings(protocols)
+			info.Protocols = protocols
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+func (m *PubSub) Disconnect(ctx context.Context, peerID peer.ID) error {
+	if m.host.Network().Connectedness(peerID) != network.Connected {
+		return fmt.Errorf("peer %s is not connected", peerID)
+	}
+	return m.host.Network().ClosePeer(peerID)
+}
+
+// BanPeer disconnects the peer and rejects the connections from and to it until unbanned
+func (m *PubSub) BanPeer(ctx context.Context, peerID peer.ID) error {
+	if err := m.gater.ban(peerID); err != nil {
+		return err
+	}
+	if m.host.Network().Connectedness(peerID) == network.Connected {
+		return m.host.Network().ClosePeer(peerID)
+	}
+	return nil
+}
+
+func (m *PubSub) UnbanPeer(ctx context.Context, peerID peer.ID) error {
+	return m.gater.unban(peerID)
+}
+
+func (m *PubSub) ListBannedPeers(ctx context.Context) ([]*mtypes.BannedPeer, error) {
+	return m.gater.list(), nil
+}
+
 // FindPeer searches the libp2p router for a given peer id
 func (m *PubSub) FindPeer(ctx context.Context, peerID peer.ID) (peer.AddrInfo, error) {
 	return m.dht.FindPeer(ctx, peerID)
@@ -192,7 +350,29 @@ func (m *PubSub) AddrListen(ctx context.Context) (peer.AddrInfo, error) {
 	}, nil
 }
 
+// ListenInfo returns the listen and announced addresses of host, and the NAT traversal options
+func (m *PubSub) ListenInfo(ctx context.Context) (*mtypes.NetListenInfo, error) {
+	listenAddrs, err := m.host.Network().InterfaceListenAddresses()
+	if err != nil {
+		return nil, err
+	}
+	return &mtypes.NetListenInfo{
+		ID:                 m.host.ID(),
+		ListenAddrs:        listenAddrs,
+		Addrs:              m.host.Addrs(),
+		EnableRelay:        m.netCfg.EnableRelay,
+		StaticRelays:       m.netCfg.StaticRelays,
+		EnableHolePunching: m.netCfg.EnableHolePunching,
+		EnableNATPortMap:   m.netCfg.EnableNATPortMap,
+	}, nil
+}
+
 func (m *PubSub) connectBootstrap(ctx context.Context) error {
+	for _, pi := range m.protectedPeers {
+		if err := m.host.Connect(ctx, pi); err != nil {
+			log.Warnf("failed to connect to protected peer: %s %s", pi, err)
+		}
+	}
 	for _, bsp := range m.bootstrappers {
 		if err := m.host.Connect(ctx, bsp); err != nil {
 			log.Warnf("failed to connect to bootstrap peer: %s %s", bsp, err)
@@ -261,19 +441,176 @@ func makeDHT(ctx context.Context, h types.RawHost, networkName string, bootNodes
 	return r, nil
 }
 
-func buildHost(ctx context.Context, address string) (types.RawHost, error) {
+func buildHost(ctx context.Context, netCfg *config.Libp2pNetConfig, identity crypto.PrivKey, gater *banGater) (types.RawHost, error) {
+	addrsFactory, err := makeAddrsFactory(netCfg.AnnounceAddresses, netCfg.NoAnnounceAddresses)
+	if err != nil {
+		return nil, err
+	}
+
 	opts := []libp2p.Option{
 		libp2p.UserAgent("venus-messager"),
-		libp2p.ListenAddrStrings(address),
-		// libp2p.Identity(secret),
+		libp2p.ListenAddrStrings(splitListenAddress(netCfg.ListenAddress)...),
 		libp2p.Ping(true),
-		libp2p.DisableRelay(),
+		libp2p.AddrsFactory(addrsFactory),
+		libp2p.ConnectionGater(gater),
+	}
+	if netCfg.ConnMgrHigh > 0 {
+		if netCfg.ConnMgrLow > netCfg.ConnMgrHigh {
+			return nil, fmt.Errorf("low watermark %d of connection manager is greater than high watermark %d",
+				netCfg.ConnMgrLow, netCfg.ConnMgrHigh)
+		}
+		cm, err := connmgr.NewConnManager(netCfg.ConnMgrLow, netCfg.ConnMgrHigh, connmgr.WithGracePeriod(netCfg.ConnMgrGrace))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create connection manager: %w", err)
+		}
+		opts = append(opts, libp2p.ConnectionManager(cm))
+	}
+	if identity != nil {
+		opts = append(opts, libp2p.Identity(identity))
 	}
+
+	if netCfg.EnableRelay {
+		opts = append(opts, libp2p.EnableRelay())
+		if len(netCfg.StaticRelays) > 0 {
+			relays, err := parseAddrInfos(netCfg.StaticRelays)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse static relays: %w", err)
+			}
+			opts = append(opts, libp2p.EnableAutoRelay(autorelay.WithStaticRelays(relays)))
+		}
+	} else {
+		if len(netCfg.StaticRelays) > 0 {
+			return nil, fmt.Errorf("static relays require relay enabled")
+		}
+		opts = append(opts, libp2p.DisableRelay())
+	}
+	if netCfg.EnableHolePunching {
+		opts = append(opts, libp2p.EnableHolePunching())
+	}
+	if netCfg.EnableNATPortMap {
+		opts = append(opts, libp2p.NATPortMap())
+	}
+
 	return libp2p.New(opts...)
 }
 
-func ProvidePubsub(ctx context.Context, networkName types.NetworkName, net *config.Libp2pNetConfig) (*PubSub, error) {
-	return NewPubsub(ctx, net.ListenAddress, networkName, net.BootstrapAddresses, net.ExpandPeriod, net.MinPeerThreshold)
+// splitListenAddress splits the comma separated listen addresses
+func splitListenAddress(address string) []string {
+	var addrs []string
+	for _, addr := range strings.Split(address, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+func parseMultiaddrs(addrs []string) ([]ma.Multiaddr, error) {
+	maddrs := make([]ma.Multiaddr, 0, len(addrs))
+	for _, addr := range addrs {
+		maddr, err := ma.NewMultiaddr(addr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse address %s: %w", addr, err)
+		}
+		maddrs = append(maddrs, maddr)
+	}
+	return maddrs, nil
+}
+
+// parseProtectedPeers parses the peer ids and the addresses with peer id
+func parseProtectedPeers(peers []string) ([]peer.AddrInfo, error) {
+	var ids []string
+	var addrs []string
+	for _, p := range peers {
+		if strings.HasPrefix(p, "/") {
+			addrs = append(addrs, p)
+		} else {
+			ids = append(ids, p)
+		}
+	}
+
+	// the addresses of the same peer are merged by AddrInfosFromP2pAddrs
+	infos, err := parseAddrInfos(addrs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse protected peers: %w", err)
+	}
+	for _, str := range ids {
+		id, err := peer.Decode(str)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse protected peer %s: %w", str, err)
+		}
+		if !containsPeer(infos, id) {
+			infos = append(infos, peer.AddrInfo{ID: id})
+		}
+	}
+	return infos, nil
+}
+
+func containsPeer(infos []peer.AddrInfo, id peer.ID) bool {
+	for _, info := range infos {
+		if info.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func parseAddrInfos(addrs []string) ([]peer.AddrInfo, error) {
+	maddrs, err := parseMultiaddrs(addrs)
+	if err != nil {
+		return nil, err
+	}
+	return peer.AddrInfosFromP2pAddrs(maddrs...)
+}
+
+// makeAddrsFactory returns the addresses announced to peers, announce replaces the listen addresses if not empty,
+// and the addresses in noAnnounce are removed.
+func makeAddrsFactory(announce, noAnnounce []string) (func([]ma.Multiaddr) []ma.Multiaddr, error) {
+	annAddrs, err := parseMultiaddrs(announce)
+	if err != nil {
+		return nil, err
+	}
+	noAnnAddrs, err := parseMultiaddrs(noAnnounce)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(allAddrs []ma.Multiaddr) []ma.Multiaddr {
+		var addrs []ma.Multiaddr
+		if len(annAddrs) > 0 {
+			addrs = annAddrs
+		} else {
+			addrs = allAddrs
+		}
+
+		out := make([]ma.Multiaddr, 0, len(addrs))
+		for _, addr := range addrs {
+			if !containsAddr(noAnnAddrs, addr) {
+				out = append(out, addr)
+			}
+		}
+		return out
+	}, nil
+}
+
+func containsAddr(addrs []ma.Multiaddr, addr ma.Multiaddr) bool {
+	for _, a := range addrs {
+		if a.Equal(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func ProvidePubsub(ctx context.Context, networkName types.NetworkName, net *config.Libp2pNetConfig, fsRepo filestore.FSRepo) (*PubSub, error) {
+	identity, err := loadIdentity(fsRepo)
+	if err != nil {
+		return nil, err
+	}
+	return NewPubsub(ctx, networkName, net, identity, PersistFiles{
+		Peerstore:   fsRepo.PeerstoreFile(),
+		BannedPeers: fsRepo.BannedPeersFile(),
+	})
 }
 
 func NewINet(p *PubSub) INet {
@@ -6,12 +6,22 @@ import (
 	"time"
 
 	"github.com/libp2p/go-libp2p/core/peer"
+	ma "github.com/multiformats/go-multiaddr"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/venus-messager/config"
 )
 
+func newTestNetConfig() *config.Libp2pNetConfig {
+	netCfg := *config.DefaultConfig().Libp2pNet
+	netCfg.ListenAddress = "/ip4/127.0.0.1/tcp/0"
+	return &netCfg
+}
+
 func TestMessagePubSub(t *testing.T) {
 	ctx := context.Background()
-	ps1, err := NewPubsub(ctx, "/ip4/127.0.0.1/tcp/0", "test_net_name", []string{}, 0, 0)
+	ps1, err := NewPubsub(ctx, "test_net_name", newTestNetConfig(), nil, PersistFiles{})
 	assert.Nil(t, err)
 	addressInfo1 := peer.AddrInfo{
 		ID:    ps1.host.ID(),
@@ -26,7 +36,9 @@ func TestMessagePubSub(t *testing.T) {
 		multiaddr[i] = addr.String()
 	}
 
-	ps2, err := NewPubsub(ctx, "/ip4/127.0.0.1/tcp/0", "test_net_name", multiaddr, 0, 0)
+	netCfg := newTestNetConfig()
+	netCfg.BootstrapAddresses = multiaddr
+	ps2, err := NewPubsub(ctx, "test_net_name", netCfg, nil, PersistFiles{})
 	assert.Nil(t, err)
 
 	topic, err := ps1.GetTopic("test")
@@ -60,3 +72,42 @@ func TestMessagePubSub(t *testing.T) {
 	err = ps2.Connect(ctx, pi1)
 	assert.Nil(t, err)
 }
+
+func TestHostOptions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	relay := "/ip4/127.0.0.1/tcp/1234/p2p/12D3KooWGzxzKZYveHXtpG6AsrUJBcWxHBFS2HsEoGTxrMLvKXtf"
+	netCfg := newTestNetConfig()
+	netCfg.StaticRelays = []string{relay}
+	_, err := NewPubsub(ctx, "test_net_name", netCfg, nil, PersistFiles{})
+	assert.Error(t, err, "static relays require relay enabled")
+	netCfg = newTestNetConfig()
+	netCfg.AnnounceAddresses = []string{"invalid"}
+	_, err = NewPubsub(ctx, "test_net_name", netCfg, nil, PersistFiles{})
+	assert.Error(t, err)
+
+	announce := "/ip4/1.2.3.4/tcp/1234"
+	netCfg = newTestNetConfig()
+	netCfg.ListenAddress = "/ip4/127.0.0.1/tcp/0, /ip4/127.0.0.1/udp/0/quic"
+	netCfg.AnnounceAddresses = []string{announce, "/ip4/1.2.3.4/tcp/4321"}
+	netCfg.NoAnnounceAddresses = []string{"/ip4/1.2.3.4/tcp/4321"}
+	netCfg.EnableRelay = true
+	netCfg.StaticRelays = []string{relay}
+	netCfg.EnableHolePunching = true
+	netCfg.EnableNATPortMap = true
+	ps, err := NewPubsub(ctx, "test_net_name", netCfg, nil, PersistFiles{})
+	require.NoError(t, err)
+
+	info, err := ps.ListenInfo(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, ps.host.ID(), info.ID)
+	// the relay transport listens on /p2p-circuit
+	assert.Len(t, info.ListenAddrs, 3)
+	assert.Contains(t, info.ListenAddrs, ma.StringCast("/p2p-circuit"))
+	assert.Equal(t, []ma.Multiaddr{ma.StringCast(announce)}, info.Addrs)
+	assert.True(t, info.EnableRelay)
+	assert.Len(t, info.StaticRelays, 1)
+	assert.True(t, info.EnableHolePunching)
+	assert.True(t, info.EnableNATPortMap)
+}
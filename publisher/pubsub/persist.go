@@ -0,0 +1,123 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/filecoin-project/venus-messager/filestore"
+)
+
+const (
+	// maxSavedPeers is the max number of connected peers saved to the peerstore file
+	maxSavedPeers = 100
+	// connectSavedTimeout is the timeout to reconnect a saved peer at startup
+	connectSavedTimeout = 10 * time.Second
+)
+
+// loadIdentity loads the libp2p key from repo, the key is generated and saved at the first time
+func loadIdentity(fsRepo filestore.FSRepo) (crypto.PrivKey, error) {
+	data, err := fsRepo.GetLibp2pKey()
+	if err == nil {
+		key, err := crypto.UnmarshalPrivateKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal libp2p key: %w", err)
+		}
+		return key, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read libp2p key: %w", err)
+	}
+
+	key, _, err := crypto.GenerateEd25519Key(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate libp2p key: %w", err)
+	}
+	data, err = crypto.MarshalPrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal libp2p key: %w", err)
+	}
+	if err := fsRepo.SaveLibp2pKey(data); err != nil {
+		return nil, err
+	}
+	log.Info("generate new libp2p identity")
+	return key, nil
+}
+
+// loadPeers reads the peers saved in file, it returns nothing when the file does not exist
+func loadPeers(file string) ([]peer.AddrInfo, error) {
+	if len(file) == 0 {
+		return nil, nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var peers []peer.AddrInfo
+	if err := json.Unmarshal(data, &peers); err != nil {
+		return nil, fmt.Errorf("failed to decode peerstore file %s: %w", file, err)
+	}
+	return peers, nil
+}
+
+// savePeers writes the addresses of the connected peers to the peerstore file, the file is kept when no peer is
+// connected, so the peers saved before are still reconnected after a restart during a network outage
+func (m *PubSub) savePeers() error {
+	if len(m.peerstoreFile) == 0 {
+		return nil
+	}
+
+	peers := make([]peer.AddrInfo, 0, maxSavedPeers)
+	for _, id := range m.host.Network().Peers() {
+		if len(peers) >= maxSavedPeers {
+			break
+		}
+		addrs := m.host.Peerstore().Addrs(id)
+		if len(addrs) == 0 {
+			continue
+		}
+		peers = append(peers, peer.AddrInfo{ID: id, Addrs: addrs})
+	}
+	if len(peers) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(peers)
+	if err != nil {
+		return err
+	}
+
+	// write to a temp file first, the file is not broken if the process exits when writing
+	tmp := m.peerstoreFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, m.peerstoreFile)
+}
+
+// connectSavedPeers reconnects the peers saved in the last running
+func (m *PubSub) connectSavedPeers(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, pi := range m.savedPeers {
+		wg.Add(1)
+		go func(pi peer.AddrInfo) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, connectSavedTimeout)
+			defer cancel()
+			if err := m.host.Connect(ctx, pi); err != nil {
+				log.Debugf("failed to connect to saved peer %s: %s", pi.ID, err)
+			}
+		}(pi)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,74 @@
+package pubsub
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/venus-messager/filestore"
+)
+
+func TestLoadIdentity(t *testing.T) {
+	fsRepo := filestore.NewMockFileStore(t.TempDir())
+	key, err := loadIdentity(fsRepo)
+	require.NoError(t, err)
+	key2, err := loadIdentity(fsRepo)
+	require.NoError(t, err)
+	assert.True(t, key.Equals(key2))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	netCfg := newTestNetConfig()
+	ps, err := NewPubsub(ctx, "test_net_name", netCfg, key, PersistFiles{})
+	require.NoError(t, err)
+	ps2, err := NewPubsub(ctx, "test_net_name", netCfg, key2, PersistFiles{})
+	require.NoError(t, err)
+	assert.Equal(t, ps.HostID(), ps2.HostID())
+}
+
+func TestPersistPeers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	netCfg := newTestNetConfig()
+	file := filepath.Join(t.TempDir(), filestore.PeerstoreFile)
+
+	peers, err := loadPeers(file)
+	require.NoError(t, err)
+	assert.Empty(t, peers)
+
+	ps1, err := NewPubsub(ctx, "test_net_name", netCfg, nil, PersistFiles{Peerstore: file})
+	require.NoError(t, err)
+	ps2, err := NewPubsub(ctx, "test_net_name", netCfg, nil, PersistFiles{})
+	require.NoError(t, err)
+	pi2, err := ps2.AddrListen(ctx)
+	require.NoError(t, err)
+	require.NoError(t, ps1.Connect(ctx, pi2))
+
+	require.NoError(t, ps1.savePeers())
+	peers, err = loadPeers(file)
+	require.NoError(t, err)
+	require.Len(t, peers, 1)
+	assert.Equal(t, pi2.ID, peers[0].ID)
+
+	// the saved peers are kept when no peer is connected
+	ps4, err := NewPubsub(ctx, "test_net_name", netCfg, nil, PersistFiles{})
+	require.NoError(t, err)
+	ps4.peerstoreFile = file
+	require.NoError(t, ps4.savePeers())
+	peers, err = loadPeers(file)
+	require.NoError(t, err)
+	require.Len(t, peers, 1)
+	assert.Equal(t, pi2.ID, peers[0].ID)
+
+	// the saved peers are reconnected after restarting
+	ps3, err := NewPubsub(ctx, "test_net_name", netCfg, nil, PersistFiles{Peerstore: file})
+	require.NoError(t, err)
+	assert.NotEmpty(t, ps3.host.Peerstore().Addrs(pi2.ID))
+	assert.Eventually(t, func() bool {
+		return len(ps3.host.Network().ConnsToPeer(pi2.ID)) > 0
+	}, 10*time.Second, 100*time.Millisecond)
+}
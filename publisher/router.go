@@ -0,0 +1,223 @@
+package publisher
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	v1 "github.com/filecoin-project/venus/venus-shared/api/chain/v1"
+	"github.com/filecoin-project/venus/venus-shared/types"
+
+	"github.com/filecoin-project/venus-messager/config"
+)
+
+const pendingResolveInterval = time.Minute
+
+// routingRule is the parsed config.RoutingRule
+type routingRule struct {
+	group  string
+	tags   []string
+	pubsub bool
+	// index is the order in config, the first rule takes effect
+	index int
+}
+
+// unresolvedRule restricts the messages from a rule address which can't be resolved, they are not published
+// anywhere, otherwise the rules of the address are bypassed
+var unresolvedRule = &routingRule{group: "unresolved", index: -1}
+
+// Router decides the nodes which messages are published to by the routing rules of the from address. The addresses
+// are compared in ID form, so the rules apply to both ID and robust form of an address.
+type Router struct {
+	node     v1.FullNode
+	nodeTags map[string]map[string]struct{}
+	// members are the addresses in config of rules, in the form they are configured
+	members map[address.Address]struct{}
+
+	lk sync.Mutex
+	// rules are keyed by ID address
+	rules map[address.Address]*routingRule
+	// pending are the rule addresses not resolved yet, they are resolved again every pendingResolveInterval
+	pending     map[address.Address]*routingRule
+	lastResolve time.Time
+	ids         map[address.Address]address.Address
+}
+
+func NewRouter(cfg *config.PublisherConfig, node v1.FullNode) (*Router, error) {
+	r := &Router{
+		node:     node,
+		nodeTags: make(map[string]map[string]struct{}),
+		members:  make(map[address.Address]struct{}),
+		rules:    make(map[address.Address]*routingRule),
+		pending:  make(map[address.Address]*routingRule),
+		ids:      make(map[address.Address]address.Address),
+	}
+	for node, tags := range cfg.Routing.NodeTags {
+		r.nodeTags[node] = make(map[string]struct{}, len(tags))
+		for _, tag := range tags {
+			r.nodeTags[node][tag] = struct{}{}
+		}
+	}
+
+	for i, rule := range cfg.Routing.Rules {
+		addrs, ok := cfg.Routing.AddressGroups[rule.Group]
+		if !ok {
+			return nil, fmt.Errorf("routing rule refers to unknown address group %s", rule.Group)
+		}
+		if len(rule.Tags) == 0 {
+			return nil, fmt.Errorf("routing rule of group %s has no tags", rule.Group)
+		}
+		parsed := &routingRule{group: rule.Group, tags: rule.Tags, pubsub: rule.EnablePubsub, index: i}
+		for _, str := range addrs {
+			addr, err := address.NewFromString(str)
+			if err != nil {
+				return nil, fmt.Errorf("parse address %s of group %s failed: %w", str, rule.Group, err)
+			}
+			r.members[addr] = struct{}{}
+			if existed, ok := r.pending[addr]; !ok || existed.index > parsed.index {
+				r.pending[addr] = parsed
+			}
+		}
+	}
+	if len(r.pending) != 0 && node == nil {
+		return nil, fmt.Errorf("node is required to resolve the addresses of routing rules")
+	}
+	r.lastResolve = time.Now()
+	r.resolvePending(context.Background())
+
+	return r, nil
+}
+
+// lookupID returns the ID address of addr, it must be called without lock held as it may request the node
+func (r *Router) lookupID(ctx context.Context, addr address.Address) (address.Address, error) {
+	if addr.Protocol() == address.ID {
+		return addr, nil
+	}
+	return r.node.StateLookupID(ctx, addr, types.EmptyTSK)
+}
+
+// resolvePending moves the rule addresses resolved to rules
+func (r *Router) resolvePending(ctx context.Context) {
+	r.lk.Lock()
+	pending := make(map[address.Address]*routingRule, len(r.pending))
+	for addr, rule := range r.pending {
+		pending[addr] = rule
+	}
+	r.lk.Unlock()
+
+	resolved := make(map[address.Address]address.Address, len(pending))
+	for addr, rule := range pending {
+		id, err := r.lookupID(ctx, addr)
+		if err != nil {
+			log.Warnf("resolve routing address %s of group %s failed: %v", addr, rule.group, err)
+			continue
+		}
+		resolved[addr] = id
+	}
+
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	for addr, id := range resolved {
+		r.setResolved(addr, id)
+	}
+}
+
+// setResolved caches the ID address of addr, and moves the rule of addr to rules if it is pending. It must be called
+// with lock held.
+func (r *Router) setResolved(addr, id address.Address) {
+	if addr.Protocol() != address.ID {
+		r.ids[addr] = id
+	}
+	rule, ok := r.pending[addr]
+	if !ok {
+		return
+	}
+	delete(r.pending, addr)
+	if existed, ok := r.rules[id]; !ok || existed.index > rule.index {
+		r.rules[id] = rule
+	}
+}
+
+// ruleOf returns the rule of addr, nil means no restriction. When a rule address can't be resolved, it is
+// restricted by unresolvedRule, other addresses can't be resolved are not restricted.
+func (r *Router) ruleOf(ctx context.Context, addr address.Address) *routingRule {
+	r.lk.Lock()
+	if len(r.rules) == 0 && len(r.pending) == 0 {
+		r.lk.Unlock()
+		return nil
+	}
+	resolvePending := len(r.pending) != 0 && time.Since(r.lastResolve) > pendingResolveInterval
+	if resolvePending {
+		r.lastResolve = time.Now()
+	}
+	id, ok := r.ids[addr]
+	r.lk.Unlock()
+
+	if resolvePending {
+		r.resolvePending(ctx)
+	}
+	if !ok {
+		var err error
+		id, err = r.lookupID(ctx, addr)
+		if err != nil {
+			if _, member := r.members[addr]; member {
+				log.Warnf("resolve %s failed, its messages are not published: %v", addr, err)
+				return unresolvedRule
+			}
+			log.Debugf("resolve %s failed, its messages are not restricted: %v", addr, err)
+			return nil
+		}
+	}
+
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	r.setResolved(addr, id)
+	return r.rules[id]
+}
+
+type routedMsgs struct {
+	// rule is nil for the messages not restricted
+	rule *routingRule
+	msgs []*types.SignedMessage
+}
+
+// split groups msgs by their rules, the order of messages in a group is kept
+func (r *Router) split(ctx context.Context, msgs []*types.SignedMessage) []*routedMsgs {
+	var groups []*routedMsgs
+	index := make(map[*routingRule]int)
+	for _, msg := range msgs {
+		rule := r.ruleOf(ctx, msg.Message.From)
+		i, ok := index[rule]
+		if !ok {
+			i = len(groups)
+			index[rule] = i
+			groups = append(groups, &routedMsgs{rule: rule})
+		}
+		groups[i].msgs = append(groups[i].msgs, msg)
+	}
+	return groups
+}
+
+// allowNode returns whether messages restricted by rule can be published to the node, the tags of node are
+// configured by its name or url
+func (r *Router) allowNode(rule *routingRule, name, url string) bool {
+	if rule == nil {
+		return true
+	}
+	for _, tag := range rule.tags {
+		if _, ok := r.nodeTags[name][tag]; ok {
+			return true
+		}
+		if _, ok := r.nodeTags[url][tag]; ok && len(url) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// allowPubsub returns whether messages restricted by rule can be published by pubsub
+func (r *Router) allowPubsub(rule *routingRule) bool {
+	return rule == nil || rule.pubsub
+}
@@ -0,0 +1,278 @@
+package publisher
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	v1 "github.com/filecoin-project/venus/venus-shared/api/chain/v1"
+	mockV1 "github.com/filecoin-project/venus/venus-shared/api/chain/v1/mock"
+	"github.com/filecoin-project/venus/venus-shared/testutil"
+	"github.com/filecoin-project/venus/venus-shared/types"
+	"github.com/filecoin-project/venus/venus-shared/types/messager"
+	"github.com/golang/mock/gomock"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/venus-messager/config"
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/testhelper"
+)
+
+// mockLookupID makes node resolve the addresses by ids
+func mockLookupID(node *mockV1.MockFullNode, ids map[address.Address]address.Address) {
+	node.EXPECT().StateLookupID(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, addr address.Address, _ types.TipSetKey) (address.Address, error) {
+			if id, ok := ids[addr]; ok {
+				return id, nil
+			}
+			return address.Undef, fmt.Errorf("actor %s not found", addr)
+		}).AnyTimes()
+}
+
+func TestNewRouter(t *testing.T) {
+	newCfg := func(groups map[string][]string, rules ...config.RoutingRule) *config.PublisherConfig {
+		return &config.PublisherConfig{Routing: config.RoutingConfig{AddressGroups: groups, Rules: rules}}
+	}
+	ctx := context.Background()
+	node := mockV1.NewMockFullNode(gomock.NewController(t))
+	mockLookupID(node, nil)
+
+	_, err := NewRouter(newCfg(nil, config.RoutingRule{Group: "private", Tags: []string{"private"}}), node)
+	assert.Error(t, err)
+	_, err = NewRouter(newCfg(map[string][]string{"private": {"f01000"}}, config.RoutingRule{Group: "private"}), node)
+	assert.Error(t, err)
+	_, err = NewRouter(newCfg(map[string][]string{"private": {"invalid"}}, config.RoutingRule{Group: "private", Tags: []string{"private"}}), node)
+	assert.Error(t, err)
+	// node is required to resolve addresses
+	_, err = NewRouter(newCfg(map[string][]string{"private": {"f01000"}}, config.RoutingRule{Group: "private", Tags: []string{"private"}}), nil)
+	assert.Error(t, err)
+
+	addr, err := address.NewIDAddress(1000)
+	require.NoError(t, err)
+	r, err := NewRouter(newCfg(map[string][]string{"a": {addr.String()}, "b": {addr.String()}},
+		config.RoutingRule{Group: "a", Tags: []string{"a"}},
+		config.RoutingRule{Group: "b", Tags: []string{"b"}},
+	), node)
+	require.NoError(t, err)
+	// the first rule takes effect
+	assert.Equal(t, "a", r.ruleOf(ctx, addr).group)
+}
+
+func TestRouterResolveAddress(t *testing.T) {
+	ctx := context.Background()
+	addrs := make([]address.Address, 4)
+	for i := range addrs {
+		addrs[i] = testutil.BlsAddressProvider()(t)
+	}
+	ids := make(map[address.Address]address.Address)
+	idOf := func(i int) address.Address {
+		id, err := address.NewIDAddress(uint64(1000 + i))
+		require.NoError(t, err)
+		return id
+	}
+	for i := 0; i < 3; i++ {
+		ids[addrs[i]] = idOf(i)
+	}
+	node := mockV1.NewMockFullNode(gomock.NewController(t))
+	mockLookupID(node, ids)
+
+	pending := idOf(3)
+	r, err := NewRouter(&config.PublisherConfig{Routing: config.RoutingConfig{
+		NodeTags: map[string][]string{"node": {"private"}},
+		AddressGroups: map[string][]string{
+			// configured by robust address and ID address
+			"private": {addrs[0].String(), idOf(1).String()},
+			// not on chain yet
+			"pending": {addrs[3].String()},
+		},
+		Rules: []config.RoutingRule{
+			{Group: "private", Tags: []string{"private"}},
+			{Group: "pending", Tags: []string{"private"}},
+		},
+	}}, node)
+	require.NoError(t, err)
+
+	// the rule applies to both forms of address
+	for _, addr := range []address.Address{addrs[0], idOf(0), addrs[1], idOf(1)} {
+		rule := r.ruleOf(ctx, addr)
+		require.NotNil(t, rule, addr)
+		assert.Equal(t, "private", rule.group)
+	}
+	assert.Nil(t, r.ruleOf(ctx, addrs[2]))
+
+	// the address which can't be resolved is not published anywhere
+	rule := r.ruleOf(ctx, addrs[3])
+	assert.Equal(t, unresolvedRule, rule)
+	assert.False(t, r.allowNode(rule, "node", ""))
+	assert.False(t, r.allowPubsub(rule))
+	// the address not in any rule is not restricted even it can't be resolved
+	assert.Nil(t, r.ruleOf(ctx, testutil.BlsAddressProvider()(t)))
+
+	// the pending rule address takes effect after it is on chain
+	ids[addrs[3]] = pending
+	r.lastResolve = time.Now().Add(-2 * pendingResolveInterval)
+	rule = r.ruleOf(ctx, pending)
+	require.NotNil(t, rule)
+	assert.Equal(t, "pending", rule.group)
+	assert.True(t, r.allowNode(rule, "node", ""))
+}
+
+func TestRouting(t *testing.T) {
+	ctx := context.Background()
+	msgs := testhelper.NewShareSignedMessages(3)
+	private := msgs[0].Message.From
+
+	cfg := &config.PublisherConfig{
+		EnableP2P:       true,
+		EnableMultiNode: true,
+		Routing: config.RoutingConfig{
+			NodeTags:      map[string][]string{"node_private": {"private", "asia"}},
+			AddressGroups: map[string][]string{"private": {private.String()}},
+			Rules:         []config.RoutingRule{{Group: "private", Tags: []string{"private"}}},
+		},
+	}
+	ctrl := gomock.NewController(t)
+	mainNode := mockV1.NewMockFullNode(ctrl)
+	ids := make(map[address.Address]address.Address)
+	for i, msg := range msgs {
+		id, err := address.NewIDAddress(uint64(1000 + i))
+		require.NoError(t, err)
+		ids[msg.Message.From] = id
+	}
+	mockLookupID(mainNode, ids)
+	router, err := NewRouter(cfg, mainNode)
+	require.NoError(t, err)
+
+	groups := router.split(ctx, msgs)
+	require.Len(t, groups, 2)
+	assert.Equal(t, "private", groups[0].rule.group)
+	assert.Equal(t, msgs[:1], groups[0].msgs)
+	assert.Nil(t, groups[1].rule)
+	assert.Equal(t, msgs[1:], groups[1].msgs)
+
+	assert.True(t, router.allowNode(nil, mainNodeName, ""))
+	assert.False(t, router.allowNode(groups[0].rule, mainNodeName, ""))
+	assert.True(t, router.allowNode(groups[0].rule, "node_private", ""))
+	assert.False(t, router.allowPubsub(groups[0].rule))
+	assert.True(t, router.allowPubsub(nil))
+
+	nodeProvider := testhelper.NewMockNodeRepo(ctrl)
+	p := NewRpcPublisher(ctx, mainNode, nodeProvider, cfg, router)
+
+	// nodes are connected, node_unhealthy is excluded
+	results := newPublishResults(0)
+	for i, name := range []string{"node_private", "node_public", "node_unhealthy"} {
+		health := newNodeHealth(name, 1, 1, time.Minute)
+		p.nodes[types.UUID{byte(i)}] = &rpcNode{
+			name:   name,
+			thread: &nodeThread{name: name, msgChan: make(chan *pushTask, 2), health: health, results: results},
+			health: health,
+		}
+	}
+	p.nodes[types.UUID{2}].health.failure(assert.AnError)
+	nodeList := []*messager.Node{
+		{ID: types.UUID{0}, Name: "node_private"},
+		{ID: types.UUID{1}, Name: "node_public"},
+		{ID: types.UUID{2}, Name: "node_unhealthy"},
+		{ID: types.UUID{3}, Name: "node_new"},
+	}
+	nodeProvider.EXPECT().ListNode().Return(nodeList[:3], nil).Times(1)
+	nodeProvider.EXPECT().ListNode().Return(nodeList, nil).AnyTimes()
+
+	// messages of private address are published to the private node only
+	pushed := make(chan struct{})
+	mainNode.EXPECT().MpoolBatchPushUntrusted(gomock.Any(), msgs[1:]).DoAndReturn(
+		func(context.Context, []*types.SignedMessage) ([]cid.Cid, error) {
+			close(pushed)
+			return nil, nil
+		})
+	require.NoError(t, p.PublishMessages(ctx, msgs))
+	<-pushed
+	assert.Equal(t, msgs[:1], (<-p.nodes[types.UUID{0}].thread.msgChan).msgs)
+	assert.Equal(t, msgs[1:], (<-p.nodes[types.UUID{0}].thread.msgChan).msgs)
+	assert.Equal(t, msgs[1:], (<-p.nodes[types.UUID{1}].thread.msgChan).msgs)
+	assert.Empty(t, p.nodes[types.UUID{2}].thread.msgChan)
+
+	route, err := p.PublishRoute(ctx, private)
+	require.NoError(t, err)
+	assert.Equal(t, "private", route.Group)
+	assert.Equal(t, []string{"private"}, route.Tags)
+	assert.False(t, route.Pubsub)
+	assert.Equal(t, []*mtypes.PublishRouteNode{
+		{Name: mainNodeName, Reason: reasonNotRouted},
+		{Name: "node_private", Selected: true},
+		{Name: "node_public", Reason: reasonNotRouted},
+		{Name: "node_unhealthy", Reason: reasonNotRouted},
+		{Name: "node_new", Reason: reasonNotRouted},
+	}, route.Nodes)
+
+	route, err = p.PublishRoute(ctx, msgs[1].Message.From)
+	require.NoError(t, err)
+	assert.Empty(t, route.Group)
+	assert.True(t, route.Pubsub)
+	assert.Equal(t, []*mtypes.PublishRouteNode{
+		{Name: mainNodeName, Selected: true},
+		{Name: "node_private", Selected: true},
+		{Name: "node_public", Selected: true},
+		{Name: "node_unhealthy", Reason: "unhealthy"},
+		{Name: "node_new", Selected: true, Reason: "not connected yet"},
+	}, route.Nodes)
+}
+
+// mockPool is a main node which routes calls to the active node
+type mockPool struct {
+	*mockV1.MockFullNode
+	name, url string
+	active    *mockV1.MockFullNode
+}
+
+func (p *mockPool) ActiveNode() (string, string, v1.FullNode) {
+	return p.name, p.url, p.active
+}
+
+func TestRoutingByActiveNode(t *testing.T) {
+	ctx := context.Background()
+	msgs := testhelper.NewShareSignedMessages(1)
+	private := msgs[0].Message.From
+
+	cfg := &config.PublisherConfig{
+		Routing: config.RoutingConfig{
+			NodeTags:      map[string][]string{"ws://private": {"private"}},
+			AddressGroups: map[string][]string{"private": {private.String()}},
+			Rules:         []config.RoutingRule{{Group: "private", Tags: []string{"private"}}},
+		},
+	}
+	ctrl := gomock.NewController(t)
+	pool := &mockPool{MockFullNode: mockV1.NewMockFullNode(ctrl), name: mainNodeName, url: "ws://public"}
+	id, err := address.NewIDAddress(1000)
+	require.NoError(t, err)
+	mockLookupID(pool.MockFullNode, map[address.Address]address.Address{private: id})
+	router, err := NewRouter(cfg, pool)
+	require.NoError(t, err)
+	p := NewRpcPublisher(ctx, pool, nil, cfg, router)
+
+	// the active node is not tagged
+	route, err := p.PublishRoute(ctx, private)
+	require.NoError(t, err)
+	assert.Equal(t, []*mtypes.PublishRouteNode{{Name: mainNodeName, Reason: reasonNotRouted}}, route.Nodes)
+	require.NoError(t, p.PublishMessages(ctx, msgs))
+
+	// switch to the tagged node, messages are pushed to it
+	pool.name, pool.url, pool.active = "node_private", "ws://private", mockV1.NewMockFullNode(ctrl)
+	route, err = p.PublishRoute(ctx, private)
+	require.NoError(t, err)
+	assert.Equal(t, []*mtypes.PublishRouteNode{{Name: "node_private", Selected: true}}, route.Nodes)
+
+	pushed := make(chan struct{})
+	pool.active.EXPECT().MpoolBatchPushUntrusted(gomock.Any(), msgs).DoAndReturn(
+		func(context.Context, []*types.SignedMessage) ([]cid.Cid, error) {
+			close(pushed)
+			return nil, nil
+		})
+	require.NoError(t, p.PublishMessages(ctx, msgs))
+	<-pushed
+}
@@ -4,33 +4,57 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/big"
+	"gorm.io/gorm"
 
 	"github.com/filecoin-project/venus-auth/jwtclient"
 
+	v1 "github.com/filecoin-project/venus/venus-shared/api/chain/v1"
 	gatewayAPI "github.com/filecoin-project/venus/venus-shared/api/gateway/v2"
 	venusTypes "github.com/filecoin-project/venus/venus-shared/types"
 	types "github.com/filecoin-project/venus/venus-shared/types/messager"
 
+	"github.com/filecoin-project/venus-messager/config"
+	"github.com/filecoin-project/venus-messager/models/mtypes"
 	"github.com/filecoin-project/venus-messager/models/repo"
 )
 
 var errAddressNotExists = errors.New("address not exists")
 
+// newDefaultAddress returns an alive address which uses the shared fee params
+func newDefaultAddress(addr address.Address) *types.Address {
+	return &types.Address{
+		ID:        venusTypes.NewUUID(),
+		Addr:      addr,
+		Nonce:     0,
+		SelMsgNum: 0,
+		State:     types.AddressStateAlive,
+		IsDeleted: repo.NotDeleted,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
 type AddressService struct {
 	repo         repo.Repo
 	walletClient gatewayAPI.IWalletClient
+	nodeClient   v1.FullNode
 	authClient   jwtclient.IAuthClient
+	signerCache  *signerCache
 }
 
-func NewAddressService(repo repo.Repo, walletClient gatewayAPI.IWalletClient, remoteAuthCli jwtclient.IAuthClient) *AddressService {
+func NewAddressService(repo repo.Repo, walletClient gatewayAPI.IWalletClient, nodeClient v1.FullNode, remoteAuthCli jwtclient.IAuthClient, jwtCfg *config.JWTConfig) *AddressService {
 	addressService := &AddressService{
 		repo: repo,
 
 		walletClient: walletClient,
+		nodeClient:   nodeClient,
 		authClient:   remoteAuthCli,
+		signerCache:  newSignerCache(&jwtCfg.SignerCache),
 	}
 
 	return addressService
@@ -173,16 +197,123 @@ func (addressService *AddressService) ActiveAddresses(ctx context.Context) map[a
 	return addrs
 }
 
+// signerKey returns the key address of addr, signers are bound to users by key address. The ID address is resolved
+// by the node once and cached, so the cached users are still used when the node is unavailable.
+func (addressService *AddressService) signerKey(ctx context.Context, addr address.Address) (address.Address, error) {
+	if addr.Protocol() != address.ID {
+		return addr, nil
+	}
+	return addressService.signerCache.key(addr, func() (address.Address, error) {
+		key, err := addressService.nodeClient.StateAccountKey(ctx, addr, venusTypes.EmptyTSK)
+		if err != nil {
+			return address.Undef, fmt.Errorf("resolve signer %s: %w", addr, err)
+		}
+		return key, nil
+	})
+}
+
 func (addressService *AddressService) GetAccountsOfSigner(ctx context.Context, addr address.Address) ([]string, error) {
-	users, err := addressService.authClient.GetUserBySigner(addr.String())
+	key, err := addressService.signerKey(ctx, addr)
 	if err != nil {
 		return nil, err
 	}
+	return addressService.signerCache.get(ctx, key, func() ([]string, error) {
+		users, err := addressService.authClient.GetUserBySigner(key.String())
+		if err != nil {
+			return nil, err
+		}
+
+		accounts := make([]string, 0, len(users))
+		for _, user := range users {
+			accounts = append(accounts, user.Name)
+		}
+		return accounts, nil
+	})
+}
 
-	accounts := make([]string, 0, len(users))
-	for _, user := range users {
-		accounts = append(accounts, user.Name)
+// InvalidateSignerCache removes the cached users of addrs, so they are fetched from the auth service next time,
+// all are removed when addrs is empty
+func (addressService *AddressService) InvalidateSignerCache(ctx context.Context, addrs []address.Address) error {
+	keys := make([]address.Address, 0, len(addrs))
+	for _, addr := range addrs {
+		key, err := addressService.signerKey(ctx, addr)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
 	}
+	addressService.signerCache.invalidate(keys)
+	log.Infof("invalidate the cached users of %d signers", len(addrs))
+	return nil
+}
+
+// DiscoverAddress lists the signer addresses of the wallets connected to gateways, the addresses not in messager are
+// saved with the default fee params if register is true.
+func (addressService *AddressService) DiscoverAddress(ctx context.Context, register bool) ([]*mtypes.DiscoveredAddress, error) {
+	details, err := addressService.walletClient.ListWalletInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list wallet info: %w", err)
+	}
+
+	found := make(map[address.Address]*mtypes.DiscoveredAddress)
+	for _, detail := range details {
+		for _, state := range detail.ConnectStates {
+			for _, addr := range state.Addrs {
+				discovered, ok := found[addr]
+				if !ok {
+					discovered = &mtypes.DiscoveredAddress{Addr: addr}
+					found[addr] = discovered
+				}
+				discovered.Wallets = appendUnique(discovered.Wallets, detail.Account)
+				discovered.Accounts = appendUnique(discovered.Accounts, detail.SupportAccounts...)
+			}
+		}
+	}
+
+	res := make([]*mtypes.DiscoveredAddress, 0, len(found))
+	for addr, discovered := range found {
+		res = append(res, discovered)
+		// the deleted address still holds the record of addr
+		record, err := addressService.repo.AddressRepo().GetOneRecord(ctx, addr)
+		if err == nil {
+			discovered.Registered = record.IsDeleted == repo.NotDeleted
+			discovered.Deleted = !discovered.Registered
+			continue
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Warnf("get discovered address %s failed: %v", addr, err)
+			discovered.Error = err.Error()
+			continue
+		}
+		if register {
+			if _, err := addressService.SaveAddress(ctx, newDefaultAddress(addr)); err != nil {
+				log.Warnf("add discovered address %s failed: %v", addr, err)
+				discovered.Error = err.Error()
+				continue
+			}
+			log.Infof("add discovered address %s", addr)
+			discovered.Registered = true
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Addr.String() < res[j].Addr.String()
+	})
 
-	return accounts, nil
+	return res, nil
+}
+
+func appendUnique(list []string, values ...string) []string {
+	for _, v := range values {
+		exist := false
+		for _, s := range list {
+			if s == v {
+				exist = true
+				break
+			}
+		}
+		if !exist {
+			list = append(list, v)
+		}
+	}
+	return list
 }
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/filecoin-project/venus/venus-shared/api/chain/v1"
+	venusTypes "github.com/filecoin-project/venus/venus-shared/types"
+	types "github.com/filecoin-project/venus/venus-shared/types/messager"
+
+	"github.com/filecoin-project/venus-messager/config"
+	"github.com/filecoin-project/venus-messager/filestore"
+	"github.com/filecoin-project/venus-messager/gateway"
+	"github.com/filecoin-project/venus-messager/models"
+	"github.com/filecoin-project/venus-messager/testhelper"
+)
+
+func TestDiscoverAddress(t *testing.T) {
+	ctx := context.Background()
+
+	fsRepo := filestore.NewMockFileStore(t.TempDir())
+	require.NoError(t, fsRepo.ReplaceConfig(config.DefaultConfig()))
+	repo, err := models.SetDataBase(fsRepo)
+	require.NoError(t, err)
+	require.NoError(t, repo.AutoMigrate())
+
+	walletProxy := gateway.NewMockWalletProxy()
+	addressService := NewAddressService(repo, walletProxy, nil, testhelper.NewMockAuthClient(), &config.DefaultConfig().JWT)
+
+	addrs := testhelper.ResolveAddrs(t, testhelper.RandAddresses(t, 4))
+	require.NoError(t, walletProxy.AddAddress("acc1", addrs[:3]))
+	require.NoError(t, walletProxy.AddAddress("acc2", addrs[2:]))
+	_, err = addressService.SaveAddress(ctx, newDefaultAddress(addrs[0]))
+	require.NoError(t, err)
+	// the deleted address is reported but not registered again
+	_, err = addressService.SaveAddress(ctx, newDefaultAddress(addrs[1]))
+	require.NoError(t, err)
+	require.NoError(t, addressService.DeleteAddress(ctx, addrs[1]))
+
+	checkDiscovered := func(register bool, registered map[address.Address]bool) {
+		discovered, err := addressService.DiscoverAddress(ctx, register)
+		require.NoError(t, err)
+		require.Len(t, discovered, len(addrs))
+		for _, d := range discovered {
+			assert.Equal(t, registered[d.Addr], d.Registered, d.Addr)
+			assert.Equal(t, d.Addr == addrs[1], d.Deleted, d.Addr)
+			assert.Empty(t, d.Error)
+			if d.Addr == addrs[2] {
+				assert.ElementsMatch(t, []string{"acc1", "acc2"}, d.Wallets)
+				assert.ElementsMatch(t, []string{"acc1", "acc2"}, d.Accounts)
+			} else {
+				assert.Len(t, d.Wallets, 1)
+			}
+		}
+	}
+
+	// list only
+	checkDiscovered(false, map[address.Address]bool{addrs[0]: true})
+	list, err := addressService.ListAddress(ctx)
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	// register the addresses not in messager
+	all := make(map[address.Address]bool, len(addrs))
+	for _, addr := range addrs {
+		all[addr] = addr != addrs[1]
+	}
+	checkDiscovered(true, all)
+	for _, addr := range addrs {
+		if addr == addrs[1] {
+			_, err := addressService.GetAddress(ctx, addr)
+			assert.Error(t, err)
+			continue
+		}
+		addrInfo, err := addressService.GetAddress(ctx, addr)
+		require.NoError(t, err)
+		assert.Equal(t, types.AddressStateAlive, addrInfo.State)
+		assert.Equal(t, uint64(0), addrInfo.Nonce)
+	}
+}
+
+// accountKeyNode counts the calls of StateAccountKey, which fail when err is set
+type accountKeyNode struct {
+	v1.FullNode
+
+	calls int
+	err   error
+}
+
+func (n *accountKeyNode) StateAccountKey(ctx context.Context, addr address.Address, tsk venusTypes.TipSetKey) (address.Address, error) {
+	n.calls++
+	if n.err != nil {
+		return address.Undef, n.err
+	}
+	return n.FullNode.StateAccountKey(ctx, addr, tsk)
+}
+
+func TestGetAccountsOfSigner(t *testing.T) {
+	ctx := context.Background()
+
+	fsRepo := filestore.NewMockFileStore(t.TempDir())
+	require.NoError(t, fsRepo.ReplaceConfig(config.DefaultConfig()))
+	repo, err := models.SetDataBase(fsRepo)
+	require.NoError(t, err)
+	require.NoError(t, repo.AutoMigrate())
+	fullNode, err := testhelper.NewMockFullNode(ctx, time.Second)
+	require.NoError(t, err)
+
+	authClient := testhelper.NewMockAuthClient()
+	jwtCfg := config.DefaultConfig().JWT
+	jwtCfg.SignerCache.TTL = time.Hour
+	node := &accountKeyNode{FullNode: fullNode}
+	addressService := NewAddressService(repo, gateway.NewMockWalletProxy(), node, authClient, &jwtCfg)
+
+	id := testhelper.RandAddresses(t, 1)[0]
+	key, err := testhelper.ResolveIDAddr(id)
+	require.NoError(t, err)
+	authClient.AddMockUserAndSigner("acc1", []address.Address{id})
+
+	// both forms of the signer are bound to the same users and share the cache entry
+	for _, addr := range []address.Address{id, key, id} {
+		accounts, err := addressService.GetAccountsOfSigner(ctx, addr)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"acc1"}, accounts)
+	}
+	entry, ok := addressService.signerCache.entry(key)
+	require.True(t, ok)
+	assert.Equal(t, []string{"acc1"}, entry.accounts)
+
+	// invalidating by ID address removes the cached users of key address
+	require.NoError(t, addressService.InvalidateSignerCache(ctx, []address.Address{id}))
+	_, ok = addressService.signerCache.entry(key)
+	assert.False(t, ok)
+
+	// the ID address is resolved only once, the users are still fetched when the node is unavailable
+	node.err = errors.New("node unavailable")
+	accounts, err := addressService.GetAccountsOfSigner(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acc1"}, accounts)
+	assert.Equal(t, 1, node.calls)
+}
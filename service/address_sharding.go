@@ -0,0 +1,302 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"go.uber.org/fx"
+
+	"github.com/filecoin-project/venus-messager/config"
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/models/repo"
+)
+
+const (
+	instanceLeasePrefix = "instance/"
+	addressLeasePrefix  = "address/"
+)
+
+// AddressSharding spreads addresses over the live instances sharing one database. Every instance renews an
+// instance lease as heartbeat, computes the addresses it should own by rendezvous hashing over the live
+// instances and holds an address lease for each of them, so an address is never owned by two instances even
+// while rebalancing. When sharding is not enabled, the instance owns all addresses.
+type AddressSharding struct {
+	repo repo.Repo
+	cfg  *config.ShardingConfig
+	id   string
+
+	lk        sync.RWMutex
+	instances []string
+	// owned maps address to the expire time of its lease
+	owned map[address.Address]time.Time
+	// releasing are addresses handed over in last round, their leases are released in the next round after
+	// the in-flight selection finished
+	releasing map[address.Address]struct{}
+	onGained  func(ctx context.Context, addrs []address.Address)
+}
+
+func NewAddressSharding(lc fx.Lifecycle, repo repo.Repo, cfg *config.ShardingConfig) (*AddressSharding, error) {
+	as, err := newAddressSharding(repo, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if !cfg.Enable {
+		return as, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go func() {
+				defer close(done)
+				as.run(ctx)
+			}()
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			cancel()
+			<-done
+			return nil
+		},
+	})
+	return as, nil
+}
+
+func newAddressSharding(repo repo.Repo, cfg *config.ShardingConfig) (*AddressSharding, error) {
+	as := &AddressSharding{
+		repo:      repo,
+		cfg:       cfg,
+		owned:     make(map[address.Address]time.Time),
+		releasing: make(map[address.Address]struct{}),
+	}
+	if !cfg.Enable {
+		return as, nil
+	}
+
+	if cfg.RenewInterval <= 0 || cfg.LeaseDuration <= cfg.RenewInterval {
+		return nil, fmt.Errorf("lease duration %v must bigger than renew interval %v", cfg.LeaseDuration, cfg.RenewInterval)
+	}
+	id, err := instanceID(cfg.ID)
+	if err != nil {
+		return nil, err
+	}
+	as.id = id
+	log.Infof("address sharding enabled, instance id %s", as.id)
+
+	return as, nil
+}
+
+// OnGained register f which is called with the addresses newly owned by the instance
+func (as *AddressSharding) OnGained(f func(ctx context.Context, addrs []address.Address)) {
+	as.lk.Lock()
+	defer as.lk.Unlock()
+	as.onGained = f
+}
+
+func (as *AddressSharding) run(ctx context.Context) {
+	tm := time.NewTicker(as.cfg.RenewInterval)
+	defer tm.Stop()
+
+	as.refresh(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			as.releaseAll()
+			return
+		case <-tm.C:
+			as.refresh(ctx)
+		}
+	}
+}
+
+func (as *AddressSharding) refresh(ctx context.Context) {
+	// leases are counted from the time of database, the local deadline counted before acquiring is earlier than it
+	expireAt := time.Now().Add(as.cfg.LeaseDuration)
+	leaseRepo := as.repo.LeaseRepo()
+
+	if _, err := leaseRepo.AcquireLease(instanceLeasePrefix+as.id, as.id, as.cfg.LeaseDuration); err != nil {
+		log.Errorf("renew instance lease failed: %v", err)
+		return
+	}
+	instances, err := as.liveInstances()
+	if err != nil {
+		log.Errorf("list live instances failed: %v", err)
+		return
+	}
+	addrList, err := as.repo.AddressRepo().ListActiveAddress(ctx)
+	if err != nil {
+		log.Errorf("list active address failed: %v", err)
+		return
+	}
+
+	as.lk.RLock()
+	prev, releasing := as.owned, as.releasing
+	as.lk.RUnlock()
+
+	owned := make(map[address.Address]time.Time)
+	var gained []address.Address
+	for _, addrInfo := range addrList {
+		addr := addrInfo.Addr
+		if ownerOf(addr, instances) != as.id {
+			continue
+		}
+		ok, err := leaseRepo.AcquireLease(addressLeasePrefix+addr.String(), as.id, as.cfg.LeaseDuration)
+		if err != nil {
+			log.Errorf("acquire lease of %s failed: %v", addr, err)
+			if prevExpireAt, has := prev[addr]; has {
+				owned[addr] = prevExpireAt
+			}
+			continue
+		}
+		// still held by the previous owner, try again in next round
+		if !ok {
+			continue
+		}
+		owned[addr] = expireAt
+		if _, has := prev[addr]; !has {
+			gained = append(gained, addr)
+		}
+	}
+
+	for addr := range releasing {
+		if _, has := owned[addr]; has {
+			continue
+		}
+		if err := leaseRepo.ReleaseLease(addressLeasePrefix+addr.String(), as.id); err != nil {
+			log.Warnf("release lease of %s failed: %v", addr, err)
+		}
+	}
+	nextReleasing := make(map[address.Address]struct{})
+	for addr := range prev {
+		if _, has := owned[addr]; !has {
+			log.Infof("hand over address %s", addr)
+			nextReleasing[addr] = struct{}{}
+		}
+	}
+	for _, addr := range gained {
+		log.Infof("take over address %s", addr)
+	}
+
+	as.lk.Lock()
+	as.instances = instances
+	as.owned = owned
+	as.releasing = nextReleasing
+	onGained := as.onGained
+	as.lk.Unlock()
+
+	if len(gained) > 0 && onGained != nil {
+		onGained(ctx, gained)
+	}
+}
+
+func (as *AddressSharding) liveInstances() ([]string, error) {
+	now, err := as.repo.LeaseRepo().Now()
+	if err != nil {
+		return nil, err
+	}
+	leases, err := as.repo.LeaseRepo().ListLease(instanceLeasePrefix)
+	if err != nil {
+		return nil, err
+	}
+	instances := make([]string, 0, len(leases))
+	hasSelf := false
+	for _, lease := range leases {
+		if !lease.ExpireAt.After(now) {
+			continue
+		}
+		id := strings.TrimPrefix(lease.Name, instanceLeasePrefix)
+		if id == as.id {
+			hasSelf = true
+		}
+		instances = append(instances, id)
+	}
+	if !hasSelf {
+		instances = append(instances, as.id)
+	}
+	sort.Strings(instances)
+
+	return instances, nil
+}
+
+func (as *AddressSharding) releaseAll() {
+	as.lk.Lock()
+	owned, releasing := as.owned, as.releasing
+	as.owned = make(map[address.Address]time.Time)
+	as.releasing = make(map[address.Address]struct{})
+	as.lk.Unlock()
+
+	leaseRepo := as.repo.LeaseRepo()
+	for addr := range owned {
+		releasing[addr] = struct{}{}
+	}
+	for addr := range releasing {
+		if err := leaseRepo.ReleaseLease(addressLeasePrefix+addr.String(), as.id); err != nil {
+			log.Warnf("release lease of %s failed: %v", addr, err)
+		}
+	}
+	if err := leaseRepo.ReleaseLease(instanceLeasePrefix+as.id, as.id); err != nil {
+		log.Warnf("release instance lease failed: %v", err)
+	}
+}
+
+// Owns returns whether the instance selects messages and refreshes message state for addr
+func (as *AddressSharding) Owns(addr address.Address) bool {
+	if !as.cfg.Enable {
+		return true
+	}
+	as.lk.RLock()
+	expireAt, ok := as.owned[addr]
+	as.lk.RUnlock()
+
+	// stop before the lease expires, avoid two owners
+	return ok && time.Now().Add(as.cfg.RenewInterval).Before(expireAt)
+}
+
+func (as *AddressSharding) ShardingInfo(ctx context.Context) (*mtypes.ShardingInfo, error) {
+	info := &mtypes.ShardingInfo{
+		ID:      as.id,
+		Enabled: as.cfg.Enable,
+	}
+	if !as.cfg.Enable {
+		return info, nil
+	}
+
+	as.lk.RLock()
+	info.Instances = append(info.Instances, as.instances...)
+	as.lk.RUnlock()
+
+	addrList, err := as.repo.AddressRepo().ListActiveAddress(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, addrInfo := range addrList {
+		if as.Owns(addrInfo.Addr) {
+			info.OwnedAddresses = append(info.OwnedAddresses, addrInfo.Addr)
+		}
+	}
+
+	return info, nil
+}
+
+// ownerOf picks the instance with the highest hash weight of addr, only addresses of the joined or
+// disappeared instance move when the instances change.
+func ownerOf(addr address.Address, instances []string) string {
+	var owner string
+	var maxWeight uint64
+	for _, id := range instances {
+		h := sha256.Sum256([]byte(addr.String() + "/" + id))
+		weight := binary.BigEndian.Uint64(h[:8])
+		if len(owner) == 0 || weight > maxWeight {
+			owner, maxWeight = id, weight
+		}
+	}
+	return owner
+}
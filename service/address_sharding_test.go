@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/big"
+	venustypes "github.com/filecoin-project/venus/venus-shared/types"
+	types "github.com/filecoin-project/venus/venus-shared/types/messager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx/fxtest"
+
+	"github.com/filecoin-project/venus-messager/config"
+	"github.com/filecoin-project/venus-messager/filestore"
+	"github.com/filecoin-project/venus-messager/models"
+	"github.com/filecoin-project/venus-messager/testhelper"
+)
+
+func TestOwnerOf(t *testing.T) {
+	addrs := testhelper.RandAddresses(t, 100)
+	instances := []string{"a", "b", "c"}
+
+	owners := make(map[address.Address]string)
+	count := make(map[string]int)
+	for _, addr := range addrs {
+		owners[addr] = ownerOf(addr, instances)
+		count[owners[addr]]++
+	}
+	for _, id := range instances {
+		assert.Greater(t, count[id], 0)
+	}
+
+	// only the addresses of the disappeared instance move
+	for _, addr := range addrs {
+		owner := ownerOf(addr, []string{"a", "c"})
+		if owners[addr] != "b" {
+			assert.Equal(t, owners[addr], owner)
+		}
+	}
+}
+
+func TestAddressSharding(t *testing.T) {
+	ctx := context.Background()
+
+	fsRepo := filestore.NewMockFileStore(t.TempDir())
+	repo, err := models.SetDataBase(fsRepo)
+	require.NoError(t, err)
+	require.NoError(t, repo.AutoMigrate())
+
+	addrs := testhelper.RandAddresses(t, 20)
+	for _, addr := range addrs {
+		require.NoError(t, repo.AddressRepo().SaveAddress(ctx, &types.Address{
+			ID:        venustypes.NewUUID(),
+			Addr:      addr,
+			State:     types.AddressStateAlive,
+			MaxFee:    big.Zero(),
+			GasFeeCap: big.Zero(),
+			BaseFee:   big.Zero(),
+			IsDeleted: -1,
+		}))
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		as, err := newAddressSharding(repo, config.DefaultConfig().Sharding)
+		require.NoError(t, err)
+		for _, addr := range addrs {
+			assert.True(t, as.Owns(addr))
+		}
+		info, err := as.ShardingInfo(ctx)
+		assert.NoError(t, err)
+		assert.False(t, info.Enabled)
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := newAddressSharding(repo, &config.ShardingConfig{Enable: true, LeaseDuration: time.Second, RenewInterval: time.Second})
+		assert.Error(t, err)
+	})
+
+	var gainedLk sync.Mutex
+	gained := make(map[string][]address.Address)
+	newSharding := func(id string) (*AddressSharding, *fxtest.Lifecycle) {
+		lc := fxtest.NewLifecycle(t)
+		as, err := NewAddressSharding(lc, repo, &config.ShardingConfig{
+			Enable:        true,
+			ID:            id,
+			LeaseDuration: 2 * time.Second,
+			RenewInterval: 100 * time.Millisecond,
+		})
+		require.NoError(t, err)
+		as.OnGained(func(ctx context.Context, addrs []address.Address) {
+			gainedLk.Lock()
+			defer gainedLk.Unlock()
+			gained[id] = append(gained[id], addrs...)
+		})
+		return as, lc
+	}
+	ownedCount := func(as *AddressSharding) int {
+		count := 0
+		for _, addr := range addrs {
+			if as.Owns(addr) {
+				count++
+			}
+		}
+		return count
+	}
+
+	as1, lc1 := newSharding("instance-1")
+	lc1.RequireStart()
+	assert.Eventually(t, func() bool { return ownedCount(as1) == len(addrs) }, time.Second, 10*time.Millisecond)
+
+	as2, lc2 := newSharding("instance-2")
+	lc2.RequireStart()
+	defer lc2.RequireStop()
+
+	// addresses are rebalanced, every address is owned by at most one instance
+	var conflicted []address.Address
+	assert.Eventually(t, func() bool {
+		for _, addr := range addrs {
+			if as1.Owns(addr) && as2.Owns(addr) {
+				conflicted = append(conflicted, addr)
+			}
+		}
+		return ownedCount(as1)+ownedCount(as2) == len(addrs) && ownedCount(as2) > 0
+	}, 3*time.Second, 10*time.Millisecond)
+	assert.Empty(t, conflicted)
+	for _, addr := range addrs {
+		expect := ownerOf(addr, []string{"instance-1", "instance-2"})
+		assert.Equal(t, expect == "instance-1", as1.Owns(addr), addr)
+		assert.Equal(t, expect == "instance-2", as2.Owns(addr), addr)
+	}
+
+	info, err := as2.ShardingInfo(ctx)
+	assert.NoError(t, err)
+	assert.True(t, info.Enabled)
+	assert.Equal(t, []string{"instance-1", "instance-2"}, info.Instances)
+	assert.Len(t, info.OwnedAddresses, ownedCount(as2))
+
+	// instance-1 disappears, instance-2 takes over its addresses
+	lc1.RequireStop()
+	assert.Equal(t, 0, ownedCount(as1))
+	assert.Eventually(t, func() bool { return ownedCount(as2) == len(addrs) }, 3*time.Second, 10*time.Millisecond)
+
+	gainedLk.Lock()
+	defer gainedLk.Unlock()
+	assert.Len(t, gained["instance-1"], len(addrs))
+	assert.Len(t, gained["instance-2"], len(addrs))
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/venus-auth/jwtclient"
+
+	v1 "github.com/filecoin-project/venus/venus-shared/api/chain/v1"
+	venusTypes "github.com/filecoin-project/venus/venus-shared/types"
+	types "github.com/filecoin-project/venus/venus-shared/types/messager"
+	"github.com/filecoin-project/venus/venus-shared/utils"
+
+	"github.com/filecoin-project/venus-messager/config"
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/models/repo"
+)
+
+var (
+	errApproverNotFound  = errors.New("can not get approver from context")
+	errRequesterNotFound = errors.New("can not get requester from context, the message needs approval")
+	errSameApprover      = errors.New("approver must be different from the account which pushed the message")
+)
+
+type ApprovalService struct {
+	repo       repo.Repo
+	nodeClient v1.FullNode
+	cfg        *config.ApprovalConfig
+
+	valueThreshold big.Int
+	methods        map[string]struct{}
+}
+
+func NewApprovalService(repo repo.Repo, nodeClient v1.FullNode, cfg *config.ApprovalConfig) (*ApprovalService, error) {
+	as := &ApprovalService{
+		repo:           repo,
+		nodeClient:     nodeClient,
+		cfg:            cfg,
+		valueThreshold: big.Zero(),
+		methods:        make(map[string]struct{}, len(cfg.Methods)),
+	}
+	if len(cfg.ValueThreshold) != 0 {
+		threshold, err := venusTypes.ParseFIL(cfg.ValueThreshold)
+		if err != nil {
+			return nil, fmt.Errorf("parse approval value threshold %s failed: %v", cfg.ValueThreshold, err)
+		}
+		as.valueThreshold = big.Int(threshold)
+	}
+	for _, method := range cfg.Methods {
+		as.methods[method] = struct{}{}
+	}
+
+	return as, nil
+}
+
+// needApproval returns the reason why the message needs a second approver, empty means not need.
+func (as *ApprovalService) needApproval(ctx context.Context, msg *venusTypes.Message) (string, error) {
+	if !as.cfg.Enable {
+		return "", nil
+	}
+
+	if !as.valueThreshold.IsZero() && !msg.Value.Nil() && msg.Value.GreaterThanEqual(as.valueThreshold) {
+		return fmt.Sprintf("value %s exceeds threshold %s", venusTypes.FIL(msg.Value), venusTypes.FIL(as.valueThreshold)), nil
+	}
+
+	if len(as.methods) == 0 || msg.Method == 0 || !as.mayBeSensitive(msg.Method) {
+		return "", nil
+	}
+	actor, err := as.nodeClient.StateGetActor(ctx, msg.To, venusTypes.EmptyTSK)
+	if err != nil {
+		return "", fmt.Errorf("get actor(%s) failed: %w", msg.To, err)
+	}
+	methodMeta, found := utils.MethodsMap[actor.Code][msg.Method]
+	if !found {
+		return "", nil
+	}
+	if _, ok := as.methods[methodMeta.Name]; ok {
+		return fmt.Sprintf("sensitive method %s", methodMeta.Name), nil
+	}
+
+	return "", nil
+}
+
+// mayBeSensitive returns whether a sensitive method of any actor has the method number, it avoids getting the actor
+// of every message from node.
+func (as *ApprovalService) mayBeSensitive(method abi.MethodNum) bool {
+	for _, methods := range utils.MethodsMap {
+		if methodMeta, ok := methods[method]; ok {
+			if _, ok := as.methods[methodMeta.Name]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (as *ApprovalService) ApproveMessage(ctx context.Context, id string, comment string) error {
+	return as.decide(ctx, id, mtypes.ApprovalApproved, comment)
+}
+
+// RejectMessage reject a pending message, the message will be marked as failed and never be selected
+func (as *ApprovalService) RejectMessage(ctx context.Context, id string, comment string) error {
+	return as.decide(ctx, id, mtypes.ApprovalRejected, comment)
+}
+
+func (as *ApprovalService) decide(ctx context.Context, id string, state mtypes.ApprovalState, comment string) error {
+	approver, _ := jwtclient.CtxGetName(ctx)
+	if len(approver) == 0 {
+		return errApproverNotFound
+	}
+
+	var height int64
+	if head, err := as.nodeClient.ChainHead(ctx); err == nil {
+		height = int64(head.Height())
+	}
+
+	return as.repo.Transaction(func(txRepo repo.TxRepo) error {
+		approval, err := txRepo.ApprovalRepo().GetApproval(id)
+		if err != nil {
+			return fmt.Errorf("get approval of %s failed: %w", id, err)
+		}
+		if approval.State != mtypes.ApprovalPending {
+			return fmt.Errorf("message %s already %s by %s", id, approval.State, approval.Approver)
+		}
+		if approval.Requester == approver {
+			return errSameApprover
+		}
+
+		approval.State = state
+		approval.Approver = approver
+		approval.Comment = comment
+		if err := txRepo.ApprovalRepo().UpdateApproval(approval); err != nil {
+			return err
+		}
+
+		if state == mtypes.ApprovalRejected {
+			msg, err := txRepo.MessageRepo().GetMessageByUid(id)
+			if err != nil {
+				return err
+			}
+			if err := txRepo.MessageRepo().MarkBadMessage(id); err != nil {
+				return err
+			}
+			errMsg := fmt.Sprintf("rejected by %s", approver)
+			if len(comment) != 0 {
+				errMsg = fmt.Sprintf("%s: %s", errMsg, comment)
+			}
+			oldState := msg.State
+			msg.State = types.FailedMsg
+			msg.ErrorMsg = errMsg
+			if err := txRepo.MessageHistoryRepo().CreateMessageHistory(newMessageHistory(msg, oldState, mtypes.TriggerApproval, height)); err != nil {
+				return err
+			}
+			if err := txRepo.MessageRepo().UpdateErrMsg(id, errMsg); err != nil {
+				return err
+			}
+		}
+		log.Infof("message %s %s by %s, requester %s", id, state, approver, approval.Requester)
+
+		return nil
+	})
+}
+
+func (as *ApprovalService) ListMessageApproval(ctx context.Context, from address.Address, state mtypes.ApprovalState) ([]*mtypes.MessageApproval, error) {
+	return as.repo.ApprovalRepo().ListApproval(from, state)
+}
+
+func newApproval(ctx context.Context, msg *types.Message, reason string) (*mtypes.MessageApproval, error) {
+	requester, _ := jwtclient.CtxGetName(ctx)
+	// the two-person check can't be applied without the requester
+	if len(requester) == 0 {
+		return nil, errRequesterNotFound
+	}
+	return &mtypes.MessageApproval{
+		MsgID:     msg.ID,
+		From:      msg.From,
+		Reason:    reason,
+		Requester: requester,
+		State:     mtypes.ApprovalPending,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}, nil
+}
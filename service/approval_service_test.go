@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/venus-auth/jwtclient"
+	venusTypes "github.com/filecoin-project/venus/venus-shared/types"
+	types "github.com/filecoin-project/venus/venus-shared/types/messager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/venus-messager/config"
+	"github.com/filecoin-project/venus-messager/testhelper"
+)
+
+func TestApprovalService(t *testing.T) {
+	ctx := context.Background()
+	addrs := testhelper.RandAddresses(t, 2)
+
+	t.Run("skip getting actor of unknown method", func(t *testing.T) {
+		// node is nil, the test panics if getting actor
+		as, err := NewApprovalService(nil, nil, &config.ApprovalConfig{Enable: true, Methods: []string{"WithdrawBalance"}})
+		require.NoError(t, err)
+		reason, err := as.needApproval(ctx, &venusTypes.Message{From: addrs[0], To: addrs[1], Method: 1 << 40})
+		require.NoError(t, err)
+		assert.Empty(t, reason)
+	})
+
+	t.Run("requester is required", func(t *testing.T) {
+		msg := &types.Message{ID: venusTypes.NewUUID().String(), Message: venusTypes.Message{From: addrs[0]}}
+		_, err := newApproval(ctx, msg, "test")
+		assert.ErrorIs(t, err, errRequesterNotFound)
+
+		approval, err := newApproval(jwtclient.CtxWithName(ctx, "alice"), msg, "test")
+		require.NoError(t, err)
+		assert.Equal(t, "alice", approval.Requester)
+	})
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/filecoin-project/venus-auth/jwtclient"
+	venusTypes "github.com/filecoin-project/venus/venus-shared/types"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/models/repo"
+)
+
+type AuditService struct {
+	repo repo.Repo
+}
+
+func NewAuditService(repo repo.Repo) *AuditService {
+	return &AuditService{repo: repo}
+}
+
+// Record save a mutating call to audit log, failure is only logged and does not affect the call
+func (as *AuditService) Record(ctx context.Context, method string, params interface{}, prev interface{}, result interface{}, callErr error) {
+	account, _ := jwtclient.CtxGetName(ctx)
+	auditLog := &mtypes.AuditLog{
+		ID:        venusTypes.NewUUID().String(),
+		Account:   account,
+		Method:    method,
+		Params:    toJSON(params),
+		Previous:  toJSON(prev),
+		Result:    toJSON(result),
+		CreatedAt: time.Now(),
+	}
+	if callErr != nil {
+		auditLog.Error = callErr.Error()
+	}
+
+	if err := as.repo.AuditRepo().CreateAuditLog(auditLog); err != nil {
+		log.Errorf("failed to save audit log of %s called by %s: %v", method, account, err)
+	}
+}
+
+func (as *AuditService) ListAuditLog(ctx context.Context, query *mtypes.AuditQuery) ([]*mtypes.AuditLog, error) {
+	return as.repo.AuditRepo().ListAuditLog(query)
+}
+
+func toJSON(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
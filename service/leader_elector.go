@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+
+	"github.com/filecoin-project/venus-messager/config"
+	"github.com/filecoin-project/venus-messager/metrics"
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/models/repo"
+)
+
+const leaderLeaseName = "messager-leader"
+
+var ErrNotLeader = errors.New("current instance is standby")
+
+// LeaderElector elects the leader by a lease stored in the shared database, only the leader runs head
+// processing, message selection and publishing. When HA is not enabled, the instance is always the leader.
+type LeaderElector struct {
+	repo repo.Repo
+	cfg  *config.HAConfig
+	id   string
+
+	lk       sync.Mutex
+	isLeader bool
+	// changed is closed and replaced when leadership changed
+	changed  chan struct{}
+	expireAt time.Time
+}
+
+func NewLeaderElector(lc fx.Lifecycle, repo repo.Repo, cfg *config.HAConfig) (*LeaderElector, error) {
+	le, err := newLeaderElector(repo, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if !cfg.Enable {
+		return le, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go func() {
+				defer close(done)
+				le.run(ctx)
+			}()
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			cancel()
+			<-done
+			return nil
+		},
+	})
+	return le, nil
+}
+
+func newLeaderElector(repo repo.Repo, cfg *config.HAConfig) (*LeaderElector, error) {
+	le := &LeaderElector{
+		repo:     repo,
+		cfg:      cfg,
+		id:       cfg.ID,
+		isLeader: !cfg.Enable,
+		changed:  make(chan struct{}),
+	}
+	if !cfg.Enable {
+		return le, nil
+	}
+
+	if cfg.RenewInterval <= 0 || cfg.LeaseDuration <= cfg.RenewInterval {
+		return nil, fmt.Errorf("lease duration %v must bigger than renew interval %v", cfg.LeaseDuration, cfg.RenewInterval)
+	}
+	id, err := instanceID(cfg.ID)
+	if err != nil {
+		return nil, err
+	}
+	le.id = id
+	log.Infof("HA enabled, instance id %s", le.id)
+
+	return le, nil
+}
+
+// instanceID returns id if not empty, otherwise hostname and pid
+func instanceID(id string) (string, error) {
+	if len(id) != 0 {
+		return id, nil
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "", fmt.Errorf("get hostname failed: %v", err)
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid()), nil
+}
+
+func (le *LeaderElector) run(ctx context.Context) {
+	tm := time.NewTicker(le.cfg.RenewInterval)
+	defer tm.Stop()
+
+	le.tryAcquire(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			le.setLeader(ctx, false)
+			if err := le.repo.LeaseRepo().ReleaseLease(leaderLeaseName, le.id); err != nil {
+				log.Warnf("release leader lease failed: %v", err)
+			}
+			return
+		case <-tm.C:
+			le.tryAcquire(ctx)
+		}
+	}
+}
+
+func (le *LeaderElector) tryAcquire(ctx context.Context) {
+	// the lease is counted from the time of database, the local deadline counted before acquiring is earlier than it
+	expireAt := time.Now().Add(le.cfg.LeaseDuration)
+	ok, err := le.repo.LeaseRepo().AcquireLease(leaderLeaseName, le.id, le.cfg.LeaseDuration)
+	if err != nil {
+		log.Errorf("acquire leader lease failed: %v", err)
+		// step down before the lease expires, avoid two leaders
+		le.lk.Lock()
+		stepDown := le.isLeader && time.Now().Add(le.cfg.RenewInterval).After(le.expireAt)
+		le.lk.Unlock()
+		if stepDown {
+			le.setLeader(ctx, false)
+		}
+		return
+	}
+
+	le.lk.Lock()
+	if ok {
+		le.expireAt = expireAt
+	}
+	le.lk.Unlock()
+	le.setLeader(ctx, ok)
+}
+
+func (le *LeaderElector) setLeader(ctx context.Context, isLeader bool) {
+	le.lk.Lock()
+	if le.isLeader != isLeader {
+		if isLeader {
+			log.Infof("instance %s becomes leader", le.id)
+		} else {
+			log.Warnf("instance %s becomes standby", le.id)
+		}
+		le.isLeader = isLeader
+		close(le.changed)
+		le.changed = make(chan struct{})
+	}
+	le.lk.Unlock()
+
+	var v int64
+	if isLeader {
+		v = 1
+	}
+	stats.Record(ctx, metrics.IsLeader.M(v))
+}
+
+func (le *LeaderElector) IsLeader() bool {
+	le.lk.Lock()
+	defer le.lk.Unlock()
+	return le.isLeader
+}
+
+// leading returns a context which is canceled when leadership lost, it blocks until becoming leader.
+func (le *LeaderElector) leading(ctx context.Context) (context.Context, context.CancelFunc, error) {
+	for {
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+		le.lk.Lock()
+		isLeader, changed := le.isLeader, le.changed
+		le.lk.Unlock()
+
+		if isLeader {
+			leaderCtx, cancel := context.WithCancel(ctx)
+			go func() {
+				select {
+				case <-changed:
+				case <-leaderCtx.Done():
+				}
+				cancel()
+			}()
+			return leaderCtx, cancel, nil
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+	}
+}
+
+// RunWhenLeader run f every time the instance becomes leader, the context passed to f is canceled when
+// leadership lost. It returns when ctx done.
+func (le *LeaderElector) RunWhenLeader(ctx context.Context, name string, f func(ctx context.Context)) {
+	for {
+		leaderCtx, cancel, err := le.leading(ctx)
+		if err != nil {
+			return
+		}
+		log.Infof("start %s as leader", name)
+		f(leaderCtx)
+		// f maybe return before leadership lost, wait to avoid running it again and again
+		<-leaderCtx.Done()
+		cancel()
+		log.Infof("stop %s", name)
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+func (le *LeaderElector) LeaderInfo(ctx context.Context) (*mtypes.LeaderInfo, error) {
+	info := &mtypes.LeaderInfo{
+		ID:        le.id,
+		HAEnabled: le.cfg.Enable,
+		IsLeader:  le.IsLeader(),
+	}
+	if !le.cfg.Enable {
+		return info, nil
+	}
+
+	lease, err := le.repo.LeaseRepo().GetLease(leaderLeaseName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return info, nil
+		}
+		return nil, err
+	}
+	info.Leader = lease.Holder
+	info.ExpireAt = lease.ExpireAt
+
+	return info, nil
+}
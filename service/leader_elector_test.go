@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx/fxtest"
+
+	"github.com/filecoin-project/venus-messager/config"
+	"github.com/filecoin-project/venus-messager/filestore"
+	"github.com/filecoin-project/venus-messager/models"
+)
+
+func TestLeaderElector(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fsRepo := filestore.NewMockFileStore(t.TempDir())
+	repo, err := models.SetDataBase(fsRepo)
+	require.NoError(t, err)
+	require.NoError(t, repo.AutoMigrate())
+
+	t.Run("disabled", func(t *testing.T) {
+		le, err := newLeaderElector(repo, config.DefaultConfig().HA)
+		require.NoError(t, err)
+		assert.True(t, le.IsLeader())
+
+		info, err := le.LeaderInfo(ctx)
+		assert.NoError(t, err)
+		assert.False(t, info.HAEnabled)
+		assert.True(t, info.IsLeader)
+	})
+
+	t.Run("stop running when ctx done", func(t *testing.T) {
+		le, err := newLeaderElector(repo, config.DefaultConfig().HA)
+		require.NoError(t, err)
+
+		runCtx, runCancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		runs := 0
+		go func() {
+			defer close(done)
+			le.RunWhenLeader(runCtx, "test", func(ctx context.Context) {
+				runs++
+			})
+		}()
+		time.Sleep(100 * time.Millisecond)
+		runCancel()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("RunWhenLeader not return after ctx done")
+		}
+		// always leader when HA disabled, f returns immediately and isn't run again until leadership changes
+		assert.Equal(t, 1, runs)
+
+		_, _, err = le.leading(runCtx)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := newLeaderElector(repo, &config.HAConfig{Enable: true, LeaseDuration: time.Second, RenewInterval: time.Second})
+		assert.Error(t, err)
+	})
+
+	newElector := func(id string) (*LeaderElector, *fxtest.Lifecycle) {
+		lc := fxtest.NewLifecycle(t)
+		le, err := NewLeaderElector(lc, repo, &config.HAConfig{
+			Enable:        true,
+			ID:            id,
+			LeaseDuration: 2 * time.Second,
+			RenewInterval: 100 * time.Millisecond,
+		})
+		require.NoError(t, err)
+		return le, lc
+	}
+
+	le1, lc1 := newElector("instance-1")
+	lc1.RequireStart()
+	assert.Eventually(t, le1.IsLeader, time.Second, 10*time.Millisecond)
+
+	le2, lc2 := newElector("instance-2")
+	lc2.RequireStart()
+	defer lc2.RequireStop()
+
+	started := make(chan struct{}, 1)
+	stopped := make(chan struct{}, 1)
+	go le2.RunWhenLeader(ctx, "test", func(ctx context.Context) {
+		started <- struct{}{}
+		<-ctx.Done()
+		stopped <- struct{}{}
+	})
+
+	time.Sleep(300 * time.Millisecond)
+	assert.True(t, le1.IsLeader())
+	assert.False(t, le2.IsLeader())
+	select {
+	case <-started:
+		t.Fatal("standby should not run leader task")
+	default:
+	}
+
+	info, err := le2.LeaderInfo(ctx)
+	assert.NoError(t, err)
+	assert.True(t, info.HAEnabled)
+	assert.False(t, info.IsLeader)
+	assert.Equal(t, "instance-1", info.Leader)
+
+	// leader stopped and release the lease, standby take over
+	lc1.RequireStop()
+	assert.False(t, le1.IsLeader())
+	assert.Eventually(t, le2.IsLeader, time.Second, 10*time.Millisecond)
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("leader task not started")
+	}
+
+	info, err = le2.LeaderInfo(ctx)
+	assert.NoError(t, err)
+	assert.True(t, info.IsLeader)
+	assert.Equal(t, "instance-2", info.Leader)
+
+	// leader task stop when leadership lost
+	le2.setLeader(ctx, false)
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("leader task not stopped")
+	}
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+
+	venusTypes "github.com/filecoin-project/venus/venus-shared/types"
+	types "github.com/filecoin-project/venus/venus-shared/types/messager"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/models/repo"
+)
+
+// newMessageHistory build a history from the current fields of msg, msg.State is the new state
+func newMessageHistory(msg *types.Message, oldState types.MessageState, trigger mtypes.Trigger, height int64) *mtypes.MessageHistory {
+	return &mtypes.MessageHistory{
+		ID:         venusTypes.NewUUID().String(),
+		MsgID:      msg.ID,
+		OldState:   oldState,
+		NewState:   msg.State,
+		GasLimit:   msg.GasLimit,
+		GasFeeCap:  msg.GasFeeCap,
+		GasPremium: msg.GasPremium,
+		SignedCid:  msg.SignedCid,
+		Error:      msg.ErrorMsg,
+		Height:     height,
+		Trigger:    trigger,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// recordStateChange record the transition of message `id` to newState, must be called before the state updated.
+// Nothing is recorded when the message not exist, updating it takes no effect either.
+func recordStateChange(txRepo repo.TxRepo, id string, newState types.MessageState, trigger mtypes.Trigger, height int64) error {
+	msg, err := txRepo.MessageRepo().GetMessageByUid(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	oldState := msg.State
+	msg.State = newState
+	return txRepo.MessageHistoryRepo().CreateMessageHistory(newMessageHistory(msg, oldState, trigger, height))
+}
+
+func (ms *MessageService) currentHeight() int64 {
+	return atomic.LoadInt64(&ms.tsCache.CurrHeight)
+}
+
+func (ms *MessageService) ListMessageHistory(ctx context.Context, id string) ([]*mtypes.MessageHistory, error) {
+	return ms.repo.MessageHistoryRepo().ListMessageHistory(id)
+}
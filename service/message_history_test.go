@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/venus/venus-shared/types/messager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/venus-messager/filestore"
+	"github.com/filecoin-project/venus-messager/models"
+	"github.com/filecoin-project/venus-messager/testhelper"
+)
+
+func TestMessageHistoryRecord(t *testing.T) {
+	ctx := context.Background()
+
+	fsRepo := filestore.NewMockFileStore(t.TempDir())
+	repo, err := models.SetDataBase(fsRepo)
+	require.NoError(t, err)
+	require.NoError(t, repo.AutoMigrate())
+
+	msgs := testhelper.NewMessages(1)
+	require.NoError(t, repo.MessageRepo().CreateMessage(msgs[0]))
+	ms := &MessageService{repo: repo, tsCache: newTipsetCache()}
+
+	t.Run("record the change of error", func(t *testing.T) {
+		w := &work{repo: repo}
+		result := &MsgSelectResult{
+			Address: &messager.Address{Addr: msgs[0].From},
+			ErrMsg:  []msgErrInfo{{id: msgs[0].ID, err: "gas estimate failed"}},
+		}
+		// the same error is reported by the next rounds
+		for i := 0; i < 3; i++ {
+			require.NoError(t, w.saveSelectedMessages(ctx, result))
+		}
+		history, err := ms.ListMessageHistory(ctx, msgs[0].ID)
+		require.NoError(t, err)
+		assert.Len(t, history, 1)
+
+		result.ErrMsg[0].err = "balance not enough"
+		require.NoError(t, w.saveSelectedMessages(ctx, result))
+		history, err = ms.ListMessageHistory(ctx, msgs[0].ID)
+		require.NoError(t, err)
+		assert.Len(t, history, 2)
+	})
+
+	t.Run("update state of unknown cid", func(t *testing.T) {
+		unknown := testhelper.NewShareSignedMessage().Message.Cid().String()
+		_, err := ms.UpdateMessageStateByCid(ctx, unknown, messager.FailedMsg)
+		assert.NoError(t, err)
+		_, err = ms.UpdateMessageStateByCid(ctx, "bad cid", messager.FailedMsg)
+		assert.NoError(t, err)
+	})
+}
@@ -22,7 +22,9 @@ import (
 	logging "github.com/ipfs/go-log/v2"
 
 	"github.com/filecoin-project/venus-messager/config"
+	"github.com/filecoin-project/venus-messager/gateway"
 	"github.com/filecoin-project/venus-messager/metrics"
+	"github.com/filecoin-project/venus-messager/models/mtypes"
 	"github.com/filecoin-project/venus-messager/models/repo"
 	"github.com/filecoin-project/venus-messager/publisher"
 	"github.com/filecoin-project/venus-messager/utils"
@@ -47,6 +49,9 @@ type MsgSelectMgr struct {
 	addressService *AddressService
 	sps            *SharedParamsService
 	walletClient   gatewayAPI.IWalletClient
+	verifier       SigVerifier
+	sharding       *AddressSharding
+	breaker        *signBreaker
 
 	works       map[address.Address]*work
 	msgReceiver publisher.MessageReceiver
@@ -59,6 +64,8 @@ func newMsgSelectMgr(ctx context.Context,
 	addressService *AddressService,
 	sps *SharedParamsService,
 	walletClient gatewayAPI.IWalletClient,
+	verifier SigVerifier,
+	sharding *AddressSharding,
 	msgReceiver publisher.MessageReceiver,
 ) (*MsgSelectMgr, error) {
 	ms := &MsgSelectMgr{
@@ -69,12 +76,15 @@ func newMsgSelectMgr(ctx context.Context,
 		addressService: addressService,
 		sps:            sps,
 		walletClient:   walletClient,
+		verifier:       verifier,
+		sharding:       sharding,
+		breaker:        newSignBreaker(cfg.SignFailureThreshold, cfg.SignPauseCooldown),
 
 		msgReceiver: msgReceiver,
 		works:       make(map[address.Address]*work),
 	}
 
-	addrInfos, err := ms.addressService.ListActiveAddress(ctx)
+	addrInfos, err := ms.listOwnedAddress(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -89,7 +99,7 @@ func (msgSelectMgr *MsgSelectMgr) SelectMessage(ctx context.Context, ts *venusTy
 		return err
 	}
 
-	activeAddrs, err := msgSelectMgr.addressService.ListActiveAddress(ctx)
+	activeAddrs, err := msgSelectMgr.listOwnedAddress(ctx)
 	if err != nil {
 		return err
 	}
@@ -105,12 +115,27 @@ func (msgSelectMgr *MsgSelectMgr) SelectMessage(ctx context.Context, ts *venusTy
 	}
 
 	for _, w := range msgSelectMgr.works {
-		go w.startSelectMessage(appliedNonce, addrInfos[w.addr], ts, addrSelMsgNum[w.addr], sharedParams)
+		go w.startSelectMessage(ctx, appliedNonce, addrInfos[w.addr], ts, addrSelMsgNum[w.addr], sharedParams)
 	}
 
 	return nil
 }
 
+// listOwnedAddress list the active addresses owned by current instance
+func (msgSelectMgr *MsgSelectMgr) listOwnedAddress(ctx context.Context) ([]*types.Address, error) {
+	activeAddrs, err := msgSelectMgr.addressService.ListActiveAddress(ctx)
+	if err != nil {
+		return nil, err
+	}
+	owned := make([]*types.Address, 0, len(activeAddrs))
+	for _, addrInfo := range activeAddrs {
+		if msgSelectMgr.sharding.Owns(addrInfo.Addr) {
+			owned = append(owned, addrInfo)
+		}
+	}
+	return owned, nil
+}
+
 func (msgSelectMgr *MsgSelectMgr) getNonceInTipset(ctx context.Context, ts *venusTypes.TipSet) (*utils.NonceMap, error) {
 	applied := utils.NewNonceMap()
 	// todo change with venus/lotus message for tipset
@@ -149,7 +174,7 @@ func (msgSelectMgr *MsgSelectMgr) tryUpdateWorks(addrInfos map[address.Address]*
 		w, ok := msgSelectMgr.works[addrInfo.Addr]
 		if !ok {
 			msgSelectLog.Infof("add a work %v", addrInfo.Addr)
-			ws[addrInfo.Addr] = newWork(msgSelectMgr.ctx, addrInfo.Addr, msgSelectMgr.cfg, msgSelectMgr.fullNode, msgSelectMgr.repo, msgSelectMgr.addressService, msgSelectMgr.walletClient, msgSelectMgr.msgReceiver)
+			ws[addrInfo.Addr] = newWork(msgSelectMgr.ctx, addrInfo.Addr, msgSelectMgr.cfg, msgSelectMgr.fullNode, msgSelectMgr.repo, msgSelectMgr.addressService, msgSelectMgr.walletClient, msgSelectMgr.verifier, msgSelectMgr.breaker, msgSelectMgr.msgReceiver)
 		} else {
 			ws[addrInfo.Addr] = w
 			delete(msgSelectMgr.works, addrInfo.Addr)
@@ -208,11 +233,30 @@ func recordMetric(ctx context.Context, addr address.Address, selectResult *MsgSe
 
 var errSingMessage = errors.New("sign message faield")
 
+// signError is errSingMessage caused by the error of wallet, it unwraps to the error of wallet
+type signError struct {
+	err error
+}
+
+func (e *signError) Error() string {
+	return fmt.Sprintf("%v %v", e.err, errSingMessage)
+}
+
+func (e *signError) Unwrap() error {
+	return e.err
+}
+
+func (e *signError) Is(target error) bool {
+	return target == errSingMessage
+}
+
 type MsgSelectResult struct {
 	Address   *types.Address
 	SelectMsg []*types.Message
 	ToPushMsg []*venusTypes.SignedMessage
 	ErrMsg    []msgErrInfo
+	// Height is the height of tipset which the selection based on
+	Height abi.ChainEpoch
 }
 
 type msgErrInfo struct {
@@ -230,6 +274,8 @@ type work struct {
 	repo           repo.Repo
 	addressService *AddressService
 	walletClient   gatewayAPI.IWalletClient
+	verifier       SigVerifier
+	breaker        *signBreaker
 	msgReceiver    publisher.MessageReceiver
 
 	start       time.Time
@@ -243,6 +289,8 @@ func newWork(ctx context.Context,
 	repo repo.Repo,
 	addressService *AddressService,
 	walletClient gatewayAPI.IWalletClient,
+	verifier SigVerifier,
+	breaker *signBreaker,
 	msgReceiver publisher.MessageReceiver,
 ) *work {
 	ctx, cancel := context.WithCancel(ctx)
@@ -255,12 +303,15 @@ func newWork(ctx context.Context,
 		fullNode:       fullNode,
 		repo:           repo,
 		walletClient:   walletClient,
+		verifier:       verifier,
+		breaker:        breaker,
 		msgReceiver:    msgReceiver,
 		controlChan:    make(chan struct{}, 1),
 	}
 }
 
 func (w *work) startSelectMessage(
+	ctx context.Context,
 	appliedNonce *utils.NonceMap,
 	addrInfo *types.Address,
 	ts *venusTypes.TipSet,
@@ -283,9 +334,17 @@ func (w *work) startSelectMessage(
 	}
 
 	w.start = time.Now()
-	ctx, cancel := context.WithTimeout(w.ctx, (w.cfg.SignMessageTimeout+w.cfg.EstimateMessageTimeout)*time.Second)
+	// ctx is canceled when leadership lost, stop selecting to avoid assigning nonce with the new leader
+	ctx, cancel := context.WithTimeout(ctx, (w.cfg.SignMessageTimeout+w.cfg.EstimateMessageTimeout)*time.Second)
 	defer w.finish()
 	defer cancel()
+	go func() {
+		select {
+		case <-w.ctx.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
 
 	log := logWithAddress(w.addr)
 	selectResult, err := w.selectMessage(ctx, appliedNonce, addrInfo, ts, maxAllowPendingMessage, sharedParams)
@@ -356,15 +415,29 @@ func (w *work) selectMessage(ctx context.Context, appliedNonce *utils.NonceMap,
 			Address:   addrInfo,
 		}, nil
 	}
+	if w.signPaused(ctx, accounts) {
+		log.Warnf("signing is paused for repeated failures, skip selecting")
+		return &MsgSelectResult{
+			ToPushMsg: toPushMessage,
+			Address:   addrInfo,
+		}, nil
+	}
 	wantCount := maxAllowPendingMessage - nonceGap
 	log.Infof("state actor nonce %d, latest nonce in ts %d, assigned nonce %d, nonce gap %d, want %d", actorNonce, nonceInLatestTs, addrInfo.Nonce, nonceGap, wantCount)
 
+	// messages waiting for approval can not be selected
+	pendingIDs, err := w.repo.ApprovalRepo().ListPendingMsgID(addrInfo.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("list pending approval error %v", err)
+	}
+
 	// get unfill message
-	selectCount := mathutil.MinUint64(wantCount*2, 100)
+	selectCount := mathutil.MinUint64(wantCount*2, 100) + uint64(len(pendingIDs))
 	messages, err := w.repo.MessageRepo().ListUnChainMessageByAddress(addrInfo.Addr, int(selectCount))
 	if err != nil {
 		return nil, fmt.Errorf("list unfill message error %v", err)
 	}
+	messages = excludeMessages(messages, pendingIDs)
 
 	if len(messages) == 0 {
 		log.Infof("have no unfill message")
@@ -411,12 +484,23 @@ func (w *work) selectMessage(ctx context.Context, appliedNonce *utils.NonceMap,
 			if errors.Is(err, errSingMessage) {
 				errMsg = append(errMsg, msgErrInfo{id: msg.ID, err: fmt.Sprintf("%v%v", signMsg, errors.Unwrap(err))})
 				log.Errorf("sign message %s failed %v", msg.ID, err)
+				if w.breaker.failure(ctx, w.addr, errors.Unwrap(err)) {
+					log.Warnf("pause signing after %d consecutive failures, resume after %v", w.cfg.SignFailureThreshold, w.cfg.SignPauseCooldown)
+				}
+				break
+			}
+			if errors.Is(err, errInvalidSignature) {
+				// the wallet is likely to sign the following messages with the wrong key too
+				errMsg = append(errMsg, msgErrInfo{id: msg.ID, err: signMsg + err.Error()})
+				log.Errorf("sign message %s failed %v", msg.ID, err)
+				w.breaker.failure(ctx, w.addr, err)
 				break
 			}
 			log.Error(err)
 			continue
 		}
 
+		w.breaker.success(ctx, w.addr)
 		msg.Signature = sig
 		msg.State = types.FillMsg
 
@@ -438,9 +522,28 @@ func (w *work) selectMessage(ctx context.Context, appliedNonce *utils.NonceMap,
 		ToPushMsg: toPushMessage,
 		Address:   addrInfo,
 		ErrMsg:    errMsg,
+		Height:    ts.Height(),
 	}, nil
 }
 
+func excludeMessages(msgs []*types.Message, ids []string) []*types.Message {
+	if len(ids) == 0 {
+		return msgs
+	}
+	excluded := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		excluded[id] = struct{}{}
+	}
+	res := make([]*types.Message, 0, len(msgs))
+	for _, msg := range msgs {
+		if _, ok := excluded[msg.ID]; !ok {
+			res = append(res, msg)
+		}
+	}
+
+	return res
+}
+
 func (w *work) getNonce(ctx context.Context, ts *venusTypes.TipSet, appliedNonce *utils.NonceMap) (uint64, uint64, error) {
 	timeoutCtx, cancel := context.WithTimeout(ctx, w.cfg.DefaultTimeout)
 	defer cancel()
@@ -530,17 +633,41 @@ func (w *work) signMessage(ctx context.Context, msg *types.Message, accounts []s
 		return nil, fmt.Errorf("serialize message %s failed %v", msg.ID, err)
 	}
 
+	toSign := msg.Message.Cid().Bytes()
 	signMsgCtx, signMsgCancel := context.WithTimeout(ctx, w.cfg.SignMessageTimeout)
-	sigI, err := handleTimeout(signMsgCtx, w.walletClient.WalletSign, []interface{}{w.addr, accounts, msg.Message.Cid().Bytes(), venusTypes.MsgMeta{
+	sigI, err := handleTimeout(signMsgCtx, w.walletClient.WalletSign, []interface{}{w.addr, accounts, toSign, venusTypes.MsgMeta{
 		Type:  venusTypes.MTChainMsg,
 		Extra: data.RawData(),
 	}})
 	signMsgCancel()
 	if err != nil {
-		return nil, fmt.Errorf("%v %w", err, errSingMessage)
+		return nil, &signError{err: err}
+	}
+	sig := sigI.(*crypto.Signature)
+	if err := verifySignature(ctx, w.fullNode, w.verifier, msg.From, sig, toSign); err != nil {
+		return nil, err
 	}
 
-	return sigI.(*crypto.Signature), nil
+	return sig, nil
+}
+
+// signPaused returns whether signing of the address is paused, a paused address is probed by WalletHas to resume
+// as soon as the wallet gets the address back. No gateway owning the address means the wallet doesn't have it.
+func (w *work) signPaused(ctx context.Context, accounts []string) bool {
+	if !w.breaker.paused(ctx, w.addr) {
+		return false
+	}
+	probeCtx, cancel := context.WithTimeout(ctx, w.cfg.SignMessageTimeout)
+	defer cancel()
+	has, err := w.walletClient.WalletHas(probeCtx, w.addr, accounts)
+	if err != nil && !errors.Is(err, gateway.ErrWalletNotFound) {
+		return true
+	}
+	if w.breaker.probe(ctx, w.addr, has) {
+		logWithAddress(w.addr).Infof("wallet has the address again, resume signing")
+		return false
+	}
+	return true
 }
 
 func (w *work) saveSelectedMessages(ctx context.Context, selectResult *MsgSelectResult) error {
@@ -557,10 +684,35 @@ func (w *work) saveSelectedMessages(ctx context.Context, selectResult *MsgSelect
 			if err := txRepo.AddressRepo().UpdateNonce(ctx, addrInfo.Addr, addrInfo.Nonce); err != nil {
 				return err
 			}
+
+			history := make([]*mtypes.MessageHistory, 0, len(selectResult.SelectMsg))
+			versions := make([]*mtypes.MessageVersion, 0, len(selectResult.SelectMsg))
+			for _, msg := range selectResult.SelectMsg {
+				history = append(history, newMessageHistory(msg, types.UnFillMsg, mtypes.TriggerSelector, int64(selectResult.Height)))
+				versions = append(versions, newMessageVersion(msg))
+			}
+			if err := txRepo.MessageHistoryRepo().CreateMessageHistory(history...); err != nil {
+				return err
+			}
+			if err := txRepo.MessageVersionRepo().SaveMessageVersion(versions...); err != nil {
+				return err
+			}
 		}
 
 		for _, m := range selectResult.ErrMsg {
 			msgSelectLog.Infof("update message %s error info with error %s", m.id, m.err)
+			msg, err := txRepo.MessageRepo().GetMessageByUid(m.id)
+			if err != nil {
+				return err
+			}
+			// the same error is reported every round, only record the change of it
+			if msg.ErrorMsg == m.err {
+				continue
+			}
+			msg.ErrorMsg = m.err
+			if err := txRepo.MessageHistoryRepo().CreateMessageHistory(newMessageHistory(msg, msg.State, mtypes.TriggerSelector, int64(selectResult.Height))); err != nil {
+				return err
+			}
 			if err := txRepo.MessageRepo().UpdateErrMsg(m.id, m.err); err != nil {
 				return err
 			}
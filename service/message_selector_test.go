@@ -12,9 +12,13 @@ import (
 
 	"github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/crypto"
 
+	"github.com/filecoin-project/venus-messager/config"
 	"github.com/filecoin-project/venus-messager/filestore"
+	"github.com/filecoin-project/venus-messager/gateway"
 	"github.com/filecoin-project/venus-messager/models"
+	"github.com/filecoin-project/venus-messager/models/mtypes"
 	"github.com/filecoin-project/venus-messager/testhelper"
 
 	"github.com/filecoin-project/venus/venus-shared/testutil"
@@ -207,6 +211,18 @@ func TestSelectMessage(t *testing.T) {
 	assert.Equal(t, totalMsg, len(selectedMsgs))
 
 	checkMsgs(ctx, t, ms, msgs, selectedMsgs)
+
+	for _, msg := range selectedMsgs {
+		history, err := ms.ListMessageHistory(ctx, msg.ID)
+		assert.NoError(t, err)
+		// message maybe already on chain, so there are at least push and select history
+		assert.GreaterOrEqual(t, len(history), 2)
+		assert.Equal(t, mtypes.TriggerPush, history[0].Trigger)
+		assert.Equal(t, types.UnFillMsg, history[0].NewState)
+		assert.Equal(t, mtypes.TriggerSelector, history[1].Trigger)
+		assert.Equal(t, types.FillMsg, history[1].NewState)
+		assert.NotNil(t, history[1].SignedCid)
+	}
 }
 
 func TestSelectNum(t *testing.T) {
@@ -437,6 +453,107 @@ func TestSignMessageFailed(t *testing.T) {
 	}
 }
 
+func TestSignPausedProbeMissingAddress(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	msh := newMessageServiceHelper(ctx, t, skipPushMessage())
+	addrs := msh.genAddresses()
+	ms := msh.MessageService
+	msh.start()
+	defer msh.stop()
+
+	// sign by a gateway pool, which fails WalletHas when no gateway has the address
+	gwCfg := config.DefaultConfig().Gateway
+	ms.walletClient = gateway.NewMockWalletPool(&gwCfg, map[string]*gateway.MockWalletProxy{"gateway": msh.walletProxy})
+	msh.genAndPushMessages(len(addrs) * 10)
+
+	addr := addrs[0]
+	assert.NoError(t, msh.walletProxy.RemoveAddress(msh.token, []address.Address{addr}))
+	ts, err := msh.fullNode.ChainHead(ctx)
+	assert.NoError(t, err)
+	for i := 0; i < ms.msgSelectMgr.cfg.SignFailureThreshold; i++ {
+		selectResult := selectMsgWithAddress(ctx, t, msh, []address.Address{addr}, ts)
+		assert.Len(t, selectResult.ErrMsg, 1)
+	}
+	assert.True(t, ms.msgSelectMgr.breaker.paused(ctx, addr))
+	// the error of wallet is the reason
+	paused, err := ms.ListPausedAddress(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, paused, 1) {
+		assert.Equal(t, addr, paused[0].Addr)
+		assert.Contains(t, paused[0].Reason, gateway.ErrWalletNotFound.Error())
+		assert.NotContains(t, paused[0].Reason, errSingMessage.Error())
+	}
+
+	// the probe finds the address missing
+	selectResult := selectMsgWithAddress(ctx, t, msh, []address.Address{addr}, ts)
+	assert.Empty(t, selectResult.SelectMsg)
+	assert.Empty(t, selectResult.ErrMsg)
+
+	// signing resumes once the wallet has the address again
+	assert.NoError(t, msh.walletProxy.AddAddress(msh.token, []address.Address{addr}))
+	selectResult = selectMsgWithAddress(ctx, t, msh, []address.Address{addr}, ts)
+	assert.NotEmpty(t, selectResult.SelectMsg)
+	assert.Empty(t, selectResult.ErrMsg)
+	assert.False(t, ms.msgSelectMgr.breaker.paused(ctx, addr))
+}
+
+// wrongKeyWallet signs the messages of badAddrs with the key of another address
+type wrongKeyWallet struct {
+	*gateway.MockWalletProxy
+	badAddrs map[address.Address]struct{}
+}
+
+func (w *wrongKeyWallet) WalletSign(ctx context.Context, addr address.Address, accounts []string, toSign []byte, meta shared.MsgMeta) (*crypto.Signature, error) {
+	sig, err := w.MockWalletProxy.WalletSign(ctx, addr, accounts, toSign, meta)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := w.badAddrs[addr]; ok {
+		sig.Data = append(append([]byte{}, toSign...), address.TestAddress.Bytes()...)
+	}
+	return sig, nil
+}
+
+func TestInvalidSignature(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	msh := newMessageServiceHelper(ctx, t, skipPushMessage())
+	addrs := msh.genAddresses()
+	ms := msh.MessageService
+	msh.start()
+	defer msh.stop()
+
+	msgs := msh.genAndPushMessages(len(addrs) * 10)
+
+	badAddrs := addrs[:len(addrs)/2]
+	wallet := &wrongKeyWallet{MockWalletProxy: msh.walletProxy, badAddrs: make(map[address.Address]struct{})}
+	for _, addr := range badAddrs {
+		wallet.badAddrs[addr] = struct{}{}
+	}
+	ms.walletClient = wallet
+
+	ts, err := msh.fullNode.ChainHead(ctx)
+	assert.NoError(t, err)
+	selectResult := selectMsgWithAddress(ctx, t, msh, addrs, ts)
+	assert.Len(t, selectResult.SelectMsg, (len(addrs)-len(badAddrs))*10)
+	assert.Len(t, selectResult.ErrMsg, len(badAddrs))
+
+	ms.msgSelectMgr.msgReceiver <- selectResult.ToPushMsg
+	checkMsgs(ctx, t, ms, msgs, selectResult.SelectMsg)
+
+	for _, errInfo := range selectResult.ErrMsg {
+		res, err := ms.GetMessageByUid(ctx, errInfo.id)
+		assert.NoError(t, err)
+		assert.Contains(t, res.ErrorMsg, errInvalidSignature.Error())
+		assert.Equal(t, types.UnFillMsg, res.State)
+		_, ok := wallet.badAddrs[res.From]
+		assert.True(t, ok)
+	}
+}
+
 func TestCapGasFee(t *testing.T) {
 	// stm: @MESSENGER_SELECTOR_CAP_MESSAGE_GAS_001
 	msg := testhelper.NewMessage().Message
@@ -589,7 +706,7 @@ func selectMsgWithAddress(ctx context.Context,
 	addrSelMsgNum := addrSelectMsgNum(activeAddrs, sharedParams.SelMsgNum)
 	allSelectRes := &MsgSelectResult{}
 	for _, addr := range addrs {
-		work := newWork(ctx, addr, ms.msgSelectMgr.cfg, msh.fullNode, ms.repo, ms.addressService, ms.walletClient, ms.msgReceiver)
+		work := newWork(ctx, addr, ms.msgSelectMgr.cfg, msh.fullNode, ms.repo, ms.addressService, ms.walletClient, ms.verifier, ms.msgSelectMgr.breaker, ms.msgReceiver)
 		appliedNonce, err := ms.msgSelectMgr.getNonceInTipset(ctx, ts)
 		assert.NoError(t, err)
 		addrInfo, err := ms.addressService.GetAddress(ctx, addr)
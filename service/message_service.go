@@ -20,6 +20,7 @@ import (
 	"github.com/filecoin-project/venus-auth/jwtclient"
 
 	"github.com/filecoin-project/venus/pkg/constants"
+	"github.com/filecoin-project/venus/venus-shared/actors/builtin"
 	v1 "github.com/filecoin-project/venus/venus-shared/api/chain/v1"
 	gatewayAPI "github.com/filecoin-project/venus/venus-shared/api/gateway/v2"
 	venusTypes "github.com/filecoin-project/venus/venus-shared/types"
@@ -27,6 +28,7 @@ import (
 
 	"github.com/filecoin-project/venus-messager/filestore"
 	"github.com/filecoin-project/venus-messager/metrics"
+	"github.com/filecoin-project/venus-messager/models/mtypes"
 	"github.com/filecoin-project/venus-messager/models/repo"
 	"github.com/filecoin-project/venus-messager/publisher"
 )
@@ -39,9 +41,11 @@ const (
 type MessageService struct {
 	repo           repo.Repo
 	fsRepo         filestore.FSRepo
+	transfer       *transferPolicy
 	nodeClient     v1.FullNode
 	addressService *AddressService
 	walletClient   gatewayAPI.IWalletClient
+	verifier       SigVerifier
 
 	triggerPush chan *venusTypes.TipSet
 	headChans   chan *headChan
@@ -60,6 +64,12 @@ type MessageService struct {
 	blockDelay time.Duration
 
 	msgReceiver publisher.MessageReceiver
+
+	approvalService *ApprovalService
+	elector         *LeaderElector
+	sharding        *AddressSharding
+
+	nodeSync nodeSyncState
 }
 
 type headChan struct {
@@ -79,14 +89,23 @@ func NewMessageService(ctx context.Context,
 	fsRepo filestore.FSRepo,
 	addressService *AddressService,
 	sps *SharedParamsService,
+	approvalService *ApprovalService,
+	elector *LeaderElector,
+	sharding *AddressSharding,
 	walletClient gatewayAPI.IWalletClient,
+	verifier SigVerifier,
 	msgReceiver publisher.MessageReceiver,
 ) (*MessageService, error) {
-	msgSelectMgr, err := newMsgSelectMgr(ctx, repo, &fsRepo.Config().MessageService, nc, addressService, sps, walletClient, msgReceiver)
+	msgSelectMgr, err := newMsgSelectMgr(ctx, repo, &fsRepo.Config().MessageService, nc, addressService, sps, walletClient, verifier, sharding, msgReceiver)
+	if err != nil {
+		return nil, err
+	}
+	transfer, err := newTransferPolicy(ctx, nc, fsRepo.Config().Transfer)
 	if err != nil {
 		return nil, err
 	}
 	ms := &MessageService{
+		transfer:           transfer,
 		repo:               repo,
 		nodeClient:         nc,
 		fsRepo:             fsRepo,
@@ -94,13 +113,29 @@ func NewMessageService(ctx context.Context,
 		headChans:          make(chan *headChan, MaxHeadChangeProcess),
 		addressService:     addressService,
 		walletClient:       walletClient,
+		verifier:           verifier,
 		tsCache:            newTipsetCache(),
 		triggerPush:        make(chan *venusTypes.TipSet, 20),
 		sps:                sps,
 		cleanUnFillMsgFunc: make(chan func() (int, error)),
 		cleanUnFillMsgRes:  make(chan cleanUnFillMsgResult),
 		msgReceiver:        msgReceiver,
-	}
+		approvalService:    approvalService,
+		elector:            elector,
+		sharding:           sharding,
+		nodeSync:           nodeSyncState{status: mtypes.NodeSyncStatus{Synced: true}},
+	}
+	// messages of the address landed while it had no owner are missed by the state refresher
+	sharding.OnGained(func(ctx context.Context, addrs []address.Address) {
+		go func() {
+			if !ms.elector.IsLeader() {
+				return
+			}
+			if _, err := ms.updateFilledMessageByAddress(ctx, addrs); err != nil {
+				log.Errorf("update filled message of taken over address failed: %v", err)
+			}
+		}()
+	})
 	ms.refreshMessageState(ctx)
 	if err := ms.tsCache.Load(ms.fsRepo.TipsetFile()); err != nil {
 		log.Infof("load tipset file failed: %v", err)
@@ -110,6 +145,14 @@ func NewMessageService(ctx context.Context,
 		go ms.recordMetricsProc(ctx)
 	}
 
+	if topUpCfg := fsRepo.Config().TopUp; topUpCfg != nil && topUpCfg.Enable {
+		rules, dailyCap, err := parseTopUpRules(topUpCfg)
+		if err != nil {
+			return nil, err
+		}
+		go ms.topUpProc(ctx, topUpCfg, rules, dailyCap)
+	}
+
 	networkParams, err := ms.nodeClient.StateGetNetworkParams(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("get network params failed %v", err)
@@ -137,6 +180,11 @@ func (ms *MessageService) verifyNetworkName() error {
 }
 
 func (ms *MessageService) pushMessage(ctx context.Context, msg *types.Message) error {
+	return ms.pushMessageWith(ctx, msg, nil)
+}
+
+// pushMessageWith saves msg, and calls `with` in the same transaction to save the records depend on msg
+func (ms *MessageService) pushMessageWith(ctx context.Context, msg *types.Message, with func(txRepo repo.TxRepo) error) error {
 	if len(msg.ID) == 0 {
 		return errors.New("empty uid")
 	}
@@ -169,16 +217,7 @@ func (ms *MessageService) pushMessage(ctx context.Context, msg *types.Message) e
 			return nil
 		}
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			if err = txRepo.AddressRepo().SaveAddress(ctx, &types.Address{
-				ID:        venusTypes.NewUUID(),
-				Addr:      msg.From,
-				Nonce:     0,
-				SelMsgNum: 0,
-				State:     types.AddressStateAlive,
-				IsDeleted: repo.NotDeleted,
-				CreatedAt: time.Now(),
-				UpdatedAt: time.Now(),
-			}); err != nil {
+			if err = txRepo.AddressRepo().SaveAddress(ctx, newDefaultAddress(msg.From)); err != nil {
 				return fmt.Errorf("save address %s failed %v", msg.From.String(), err)
 			}
 			log.Infof("add new address %s", msg.From.String())
@@ -194,7 +233,35 @@ func (ms *MessageService) pushMessage(ctx context.Context, msg *types.Message) e
 
 	msg.Nonce = 0
 
-	return ms.repo.MessageRepo().CreateMessage(msg)
+	reason, err := ms.approvalService.needApproval(ctx, &msg.Message)
+	if err != nil {
+		return err
+	}
+	var approval *mtypes.MessageApproval
+	if len(reason) != 0 {
+		log.Infof("message %s from %s need approval: %s", msg.ID, msg.From, reason)
+		if approval, err = newApproval(ctx, msg, reason); err != nil {
+			return err
+		}
+	}
+
+	return ms.repo.Transaction(func(txRepo repo.TxRepo) error {
+		if err := txRepo.MessageRepo().CreateMessage(msg); err != nil {
+			return err
+		}
+		if err := txRepo.MessageHistoryRepo().CreateMessageHistory(newMessageHistory(msg, types.UnKnown, mtypes.TriggerPush, ms.currentHeight())); err != nil {
+			return err
+		}
+		if approval != nil {
+			if err := txRepo.ApprovalRepo().CreateApproval(approval); err != nil {
+				return err
+			}
+		}
+		if with == nil {
+			return nil
+		}
+		return with(txRepo)
+	})
 }
 
 func (ms *MessageService) PushMessage(ctx context.Context, msg *venusTypes.Message, meta *types.SendSpec) (string, error) {
@@ -203,6 +270,12 @@ func (ms *MessageService) PushMessage(ctx context.Context, msg *venusTypes.Messa
 
 func (ms *MessageService) PushMessageWithId(ctx context.Context, id string, msg *venusTypes.Message, meta *types.SendSpec) (string, error) {
 	account, _ := jwtclient.CtxGetName(ctx)
+	// plain transfers pushed directly are also limited by `[transfer]` config
+	if msg.Method == builtin.MethodSend {
+		if err := ms.transfer.check(ctx, msg.From, msg.To, msg.Value); err != nil {
+			return id, err
+		}
+	}
 	if err := ms.pushMessage(ctx, &types.Message{
 		ID:         id,
 		Message:    *msg,
@@ -316,6 +389,14 @@ func (ms *MessageService) GetMessageBySignedCid(ctx context.Context, signedCid c
 		return nil, err
 	}
 	msg, err := ms.repo.MessageRepo().GetMessageBySignedCid(signedCid)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// maybe a historical version of replaced message, the conflict one is not ours
+		version, verErr := ms.repo.MessageVersionRepo().GetMessageVersion(signedCid)
+		if verErr != nil || version.Conflict {
+			return nil, err
+		}
+		msg, err = ms.repo.MessageRepo().GetMessageByUid(version.MsgID)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -438,12 +519,30 @@ func (ms *MessageService) ListBlockedMessage(ctx context.Context, addr address.A
 	return msgs, nil
 }
 
-func (ms *MessageService) UpdateMessageStateByCid(ctx context.Context, cid string, state types.MessageState) (string, error) {
-	return cid, ms.repo.MessageRepo().UpdateMessageStateByCid(cid, state)
+func (ms *MessageService) UpdateMessageStateByCid(ctx context.Context, unsignedCid string, state types.MessageState) (string, error) {
+	return unsignedCid, ms.repo.Transaction(func(txRepo repo.TxRepo) error {
+		// updating the message of an unknown cid takes no effect, so nothing is recorded for it
+		if c, err := cid.Decode(unsignedCid); err == nil {
+			msg, err := txRepo.MessageRepo().GetMessageByCid(c)
+			if err == nil {
+				if err := recordStateChange(txRepo, msg.ID, state, mtypes.TriggerAPI, ms.currentHeight()); err != nil {
+					return err
+				}
+			} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+		}
+		return txRepo.MessageRepo().UpdateMessageStateByCid(unsignedCid, state)
+	})
 }
 
 func (ms *MessageService) UpdateMessageStateByID(ctx context.Context, id string, state types.MessageState) error {
-	return ms.repo.MessageRepo().UpdateMessageStateByID(id, state)
+	return ms.repo.Transaction(func(txRepo repo.TxRepo) error {
+		if err := recordStateChange(txRepo, id, state, mtypes.TriggerAPI, ms.currentHeight()); err != nil {
+			return err
+		}
+		return txRepo.MessageRepo().UpdateMessageStateByID(id, state)
+	})
 }
 
 func (ms *MessageService) UpdateMessageInfoByCid(unsignedCid string, receipt *venusTypes.MessageReceipt,
@@ -613,6 +712,11 @@ func (ms *MessageService) StartPushMessage(ctx context.Context, skipPushMsg bool
 				log.Info("skip push message")
 				continue
 			}
+			// keep messages untouched until the node synced
+			if err := ms.checkNodeSync(ctx, newHead); err != nil {
+				log.Debugf("skip select message at %s: %v", newHead.String(), err)
+				continue
+			}
 			start := time.Now()
 			log.Infof("start select message %s task wait task %d", newHead.String(), len(ms.triggerPush))
 			err := ms.msgSelectMgr.SelectMessage(ctx, newHead)
@@ -637,9 +741,20 @@ func (ms *MessageService) tryClearUnFillMsg() {
 }
 
 func (ms *MessageService) UpdateAllFilledMessage(ctx context.Context) (int, error) {
+	var addrs []address.Address
+	for addr := range ms.addressService.ActiveAddresses(ctx) {
+		if ms.sharding.Owns(addr) {
+			addrs = append(addrs, addr)
+		}
+	}
+
+	return ms.updateFilledMessageByAddress(ctx, addrs)
+}
+
+func (ms *MessageService) updateFilledMessageByAddress(ctx context.Context, addrs []address.Address) (int, error) {
 	msgs := make([]*types.Message, 0)
 
-	for addr := range ms.addressService.ActiveAddresses(ctx) {
+	for _, addr := range addrs {
 		filledMsgs, err := ms.repo.MessageRepo().ListFilledMessageByAddress(addr)
 		if err != nil {
 			log.Errorf("list filled message %v %v", addr, err)
@@ -668,7 +783,22 @@ func (ms *MessageService) updateFilledMessage(ctx context.Context, msg *types.Me
 		if err != nil || msgLookup == nil {
 			return fmt.Errorf("search message %s from node %v", cid.String(), err)
 		}
-		if _, err := ms.UpdateMessageInfoByCid(msg.UnsignedCid.String(), &msgLookup.Receipt, msgLookup.Height, types.OnChainMsg, msgLookup.TipSet); err != nil {
+		// the message found maybe a replaced one, which isn't saved as a version yet
+		chainMsg := &msg.Message
+		if !msgLookup.Message.Equals(*cid) {
+			if chainMsg, err = ms.nodeClient.ChainGetMessage(ctx, msgLookup.Message); err != nil {
+				return fmt.Errorf("get message %s from node %v", msgLookup.Message, err)
+			}
+		}
+		if err := ms.repo.Transaction(func(txRepo repo.TxRepo) error {
+			if err := recordStateChange(txRepo, msg.ID, types.OnChainMsg, mtypes.TriggerAPI, int64(msgLookup.Height)); err != nil {
+				return err
+			}
+			if err := saveIncludedVersion(txRepo, msg.ID, msgLookup.Message, chainMsg); err != nil {
+				return err
+			}
+			return txRepo.MessageRepo().UpdateMessageInfoByCid(msg.UnsignedCid.String(), &msgLookup.Receipt, msgLookup.Height, types.OnChainMsg, msgLookup.TipSet)
+		}); err != nil {
 			return err
 		}
 		log.Infof("update message %v by node success, height: %d", msg.ID, msgLookup.Height)
@@ -697,6 +827,13 @@ func (ms *MessageService) ReplaceMessage(ctx context.Context, params *types.Repl
 	if msg.State == types.OnChainMsg {
 		return cid.Undef, fmt.Errorf("message already on chain")
 	}
+	oldState := msg.State
+	var oldVersion *mtypes.MessageVersion
+	if msg.SignedCid != nil {
+		// messages signed before versions were recorded have no version yet
+		oldVersion = newMessageVersion(msg)
+		oldVersion.CreatedAt = msg.UpdatedAt
+	}
 
 	if params.Auto {
 		minRBF := computeMinRBF(msg.GasPremium)
@@ -756,12 +893,24 @@ func (ms *MessageService) ReplaceMessage(ctx context.Context, params *types.Repl
 	if err != nil {
 		return cid.Undef, err
 	}
-	signedMsg, err := ToSignedMsg(ctx, ms.walletClient, msg, accounts)
+	signedMsg, err := ToSignedMsg(ctx, ms.walletClient, ms.verifier, ms.nodeClient, msg, accounts)
 	if err != nil {
 		return cid.Undef, err
 	}
 
-	if err := ms.repo.MessageRepo().UpdateMessageByState(msg, types.FillMsg); err != nil {
+	if err := ms.repo.Transaction(func(txRepo repo.TxRepo) error {
+		if err := txRepo.MessageRepo().UpdateMessageByState(msg, types.FillMsg); err != nil {
+			return err
+		}
+		versions := []*mtypes.MessageVersion{newMessageVersion(msg)}
+		if oldVersion != nil {
+			versions = append([]*mtypes.MessageVersion{oldVersion}, versions...)
+		}
+		if err := txRepo.MessageVersionRepo().SaveMessageVersion(versions...); err != nil {
+			return err
+		}
+		return txRepo.MessageHistoryRepo().CreateMessageHistory(newMessageHistory(msg, oldState, mtypes.TriggerReplace, ms.currentHeight()))
+	}); err != nil {
 		return cid.Undef, err
 	}
 
@@ -769,7 +918,12 @@ func (ms *MessageService) ReplaceMessage(ctx context.Context, params *types.Repl
 }
 
 func (ms *MessageService) MarkBadMessage(ctx context.Context, id string) error {
-	return ms.repo.MessageRepo().MarkBadMessage(id)
+	return ms.repo.Transaction(func(txRepo repo.TxRepo) error {
+		if err := recordStateChange(txRepo, id, types.FailedMsg, mtypes.TriggerAPI, ms.currentHeight()); err != nil {
+			return err
+		}
+		return txRepo.MessageRepo().MarkBadMessage(id)
+	})
 }
 
 func (ms *MessageService) RecoverFailedMsg(ctx context.Context, addr address.Address) ([]string, error) {
@@ -791,7 +945,7 @@ func (ms *MessageService) RecoverFailedMsg(ctx context.Context, addr address.Add
 	}
 	for _, msg := range msgs {
 		if msg.Nonce >= actor.Nonce {
-			if err = ms.repo.MessageRepo().UpdateMessageStateByID(msg.ID, types.FillMsg); err != nil {
+			if err = ms.UpdateMessageStateByID(ctx, msg.ID, types.FillMsg); err != nil {
 				return nil, err
 			}
 			recoverIDs = append(recoverIDs, msg.ID)
@@ -821,7 +975,13 @@ func (ms *MessageService) RepublishMessage(ctx context.Context, id string) error
 	return nil
 }
 
-func ToSignedMsg(ctx context.Context, walletCli gatewayAPI.IWalletClient, msg *types.Message, accounts []string) (venusTypes.SignedMessage, error) {
+func ToSignedMsg(ctx context.Context,
+	walletCli gatewayAPI.IWalletClient,
+	verifier SigVerifier,
+	fullNode v1.FullNode,
+	msg *types.Message,
+	accounts []string,
+) (venusTypes.SignedMessage, error) {
 	unsignedCid := msg.Message.Cid()
 	msg.UnsignedCid = &unsignedCid
 	// 签名
@@ -836,6 +996,9 @@ func ToSignedMsg(ctx context.Context, walletCli gatewayAPI.IWalletClient, msg *t
 	if err != nil {
 		return venusTypes.SignedMessage{}, fmt.Errorf("wallet sign failed %s fail %v", msg.ID, err)
 	}
+	if err := verifySignature(ctx, fullNode, verifier, msg.From, sig, unsignedCid.Bytes()); err != nil {
+		return venusTypes.SignedMessage{}, err
+	}
 
 	msg.Signature = sig
 	// state
@@ -859,6 +1022,11 @@ func (ms *MessageService) clearUnFillMessage(ctx context.Context, addr address.A
 			return err
 		}
 		for _, msg := range unFillMsgs {
+			oldState := msg.State
+			msg.State = types.FailedMsg
+			if err := txRepo.MessageHistoryRepo().CreateMessageHistory(newMessageHistory(msg, oldState, mtypes.TriggerAPI, ms.currentHeight())); err != nil {
+				return err
+			}
 			if err := txRepo.MessageRepo().MarkBadMessage(msg.ID); err != nil {
 				return fmt.Errorf("mark bad message %s failed %v", msg.ID, err)
 			}
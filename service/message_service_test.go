@@ -123,6 +123,25 @@ func TestReplaceMessage(t *testing.T) {
 		assert.Equal(t, msg.GasPremium, res.GasPremium)
 	}
 
+	// the signed cid before replaced still can be resolved to the message
+	for _, msg := range selectResult.SelectMsg {
+		if _, ok := blockedMsgs[msg.ID]; !ok {
+			continue
+		}
+		res, err := ms.GetMessageBySignedCid(ctx, *msg.SignedCid)
+		assert.NoError(t, err)
+		assert.Equal(t, msg.ID, res.ID)
+		assert.NotEqual(t, *msg.SignedCid, *res.SignedCid)
+
+		versions, err := ms.ListMessageVersion(ctx, msg.ID)
+		assert.NoError(t, err)
+		assert.Len(t, versions, 2)
+		assert.Equal(t, *msg.SignedCid, versions[0].SignedCid)
+		assert.False(t, versions[0].Included)
+		assert.Equal(t, *res.SignedCid, versions[1].SignedCid)
+		assert.True(t, versions[1].Included)
+	}
+
 	failedMessageReplace := func(*types.ReplacMessageParams) {
 		_, err = ms.ReplaceMessage(ctx, nil)
 		assert.Error(t, err)
@@ -590,19 +609,27 @@ func newMessageServiceHelper(ctx context.Context, t *testing.T, opts ...opt) *me
 
 	authClient := testhelper.NewMockAuthClient()
 	walletProxy := gateway.NewMockWalletProxy()
-	addressService := NewAddressService(repo, walletProxy, authClient)
+	addressService := NewAddressService(repo, walletProxy, fullNode, authClient, &cfg.JWT)
 	sharedParamsService, err := NewSharedParamsService(ctx, repo)
 	assert.NoError(t, err)
 
-	rpcPublisher := publisher.NewRpcPublisher(ctx, fullNode, repo.NodeRepo(), false)
+	router, err := publisher.NewRouter(cfg.Publisher, fullNode)
+	assert.NoError(t, err)
+	rpcPublisher := publisher.NewRpcPublisher(ctx, fullNode, repo.NodeRepo(), &config.PublisherConfig{}, router)
 	networkParams := &shared.NetworkParams{BlockDelaySecs: 30}
-	msgPublisher, err := publisher.NewIMsgPublisher(ctx, networkParams, cfg.Publisher, nil, rpcPublisher)
+	msgPublisher, err := publisher.NewIMsgPublisher(ctx, networkParams, cfg.Publisher, nil, rpcPublisher, nil)
 	assert.NoError(t, err)
 
 	msgReceiver, err := publisher.NewMessageReciver(ctx, msgPublisher)
 	assert.NoError(t, err)
+	approvalService, err := NewApprovalService(repo, fullNode, cfg.Approval)
+	assert.NoError(t, err)
+	elector, err := newLeaderElector(repo, cfg.HA)
+	assert.NoError(t, err)
+	sharding, err := newAddressSharding(repo, cfg.Sharding)
+	assert.NoError(t, err)
 	ms, err := NewMessageService(ctx, repo, fullNode, fsRepo, addressService, sharedParamsService,
-		walletProxy, msgReceiver)
+		approvalService, elector, sharding, walletProxy, walletProxy, msgReceiver)
 	assert.NoError(t, err)
 
 	return &messageServiceHelper{
@@ -654,6 +681,7 @@ func newMessageService(msh *messageServiceHelper) *MessageService {
 	return &MessageService{
 		repo:           msh.MessageService.repo,
 		fsRepo:         filestore.NewMockFileStore(msh.t.TempDir()),
+		transfer:       msh.MessageService.transfer,
 		nodeClient:     msh.fullNode,
 		addressService: msh.MessageService.addressService,
 		walletClient:   msh.walletProxy,
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/filecoin-project/go-state-types/abi"
@@ -14,6 +15,7 @@ import (
 	types "github.com/filecoin-project/venus/venus-shared/types/messager"
 
 	"github.com/filecoin-project/venus-messager/metrics"
+	"github.com/filecoin-project/venus-messager/models/mtypes"
 	"github.com/filecoin-project/venus-messager/models/repo"
 )
 
@@ -65,12 +67,12 @@ func (ms *MessageService) doRefreshMessageState(ctx context.Context, h *headChan
 	}
 
 	// update db
-	replaceMsg, invalidMsgs, err := ms.updateMessageState(ctx, applyMsgs, revertMsgs)
+	replaceMsg, invalidMsgs, err := ms.updateMessageState(ctx, applyMsgs, revertMsgs, int64(h.apply[0].Height()))
 	if err != nil {
 		return err
 	}
 
-	ms.tsCache.CurrHeight = int64(h.apply[0].Height())
+	atomic.StoreInt64(&ms.tsCache.CurrHeight, int64(h.apply[0].Height()))
 	ms.tsCache.Add(h.apply...)
 	if err := ms.tsCache.Save(ms.fsRepo.TipsetFile()); err != nil {
 		msgStateLog.Errorf("store tipsetkey failed %v", err)
@@ -89,12 +91,22 @@ func (ms *MessageService) doRefreshMessageState(ctx context.Context, h *headChan
 	return nil
 }
 
-func (ms *MessageService) updateMessageState(ctx context.Context, applyMsgs []applyMessage, revertMsgs map[cid.Cid]struct{}) (map[string]*types.Message, map[cid.Cid]struct{}, error) {
+func (ms *MessageService) updateMessageState(ctx context.Context, applyMsgs []applyMessage, revertMsgs map[cid.Cid]struct{}, height int64) (map[string]*types.Message, map[cid.Cid]struct{}, error) {
 	replaceMsg := make(map[string]*types.Message)
 	invalidMsgs := make(map[cid.Cid]struct{})
 	return replaceMsg, invalidMsgs, ms.repo.Transaction(func(txRepo repo.TxRepo) error {
-		for cid := range revertMsgs {
-			if err := txRepo.MessageRepo().UpdateMessageInfoByCid(cid.String(), &venustypes.MessageReceipt{ExitCode: -1},
+		for unsignedCid := range revertMsgs {
+			localMsg, err := txRepo.MessageRepo().GetMessageByCid(unsignedCid)
+			if err != nil {
+				return fmt.Errorf("get revert message %s failed: %v", unsignedCid, err)
+			}
+			if err := recordStateChange(txRepo, localMsg.ID, types.FillMsg, mtypes.TriggerStateRefresh, height); err != nil {
+				return err
+			}
+			if err := txRepo.MessageVersionRepo().SetIncluded(localMsg.ID, cid.Undef); err != nil {
+				return err
+			}
+			if err := txRepo.MessageRepo().UpdateMessageInfoByCid(unsignedCid.String(), &venustypes.MessageReceipt{ExitCode: -1},
 				abi.ChainEpoch(0), types.FillMsg, venustypes.EmptyTSK); err != nil {
 				return err
 			}
@@ -109,9 +121,24 @@ func (ms *MessageService) updateMessageState(ctx context.Context, applyMsgs []ap
 				invalidMsgs[msg.signedCID] = struct{}{}
 				continue
 			}
-			if localMsg.SignedCid != nil && !(*localMsg.SignedCid).Equals(msg.signedCID) {
+			replaced := localMsg.SignedCid != nil && !(*localMsg.SignedCid).Equals(msg.signedCID)
+			restored := false
+			if replaced {
+				landed, err := landedVersion(txRepo, localMsg.ID, msg.signedCID)
+				if err != nil {
+					return err
+				}
+				if landed != nil {
+					// an older version of the message landed, eg. the one signed before replaced
+					msgStateLog.Infof("old version %s of message %s landed, current version %s", msg.signedCID, localMsg.ID, localMsg.SignedCid)
+					useVersion(localMsg, landed, msg.msg.Cid())
+					replaced, restored = false, true
+				}
+			}
+			if replaced {
 				msgStateLog.Warnf("replace message old msg cid %s, new msg cid %s, id %s", localMsg.SignedCid, msg.signedCID, localMsg.ID)
 				// replace msg
+				oldState := localMsg.State
 				localMsg.State = types.NonceConflictMsg
 				localMsg.Receipt = msg.receipt
 				localMsg.Height = int64(msg.height)
@@ -119,9 +146,31 @@ func (ms *MessageService) updateMessageState(ctx context.Context, applyMsgs []ap
 				if err = txRepo.MessageRepo().UpdateMessage(localMsg); err != nil {
 					return fmt.Errorf("update message receipt failed, cid:%s failed:%v", msg.signedCID, err)
 				}
+				if err = txRepo.MessageHistoryRepo().CreateMessageHistory(newMessageHistory(localMsg, oldState, mtypes.TriggerStateRefresh, int64(msg.height))); err != nil {
+					return err
+				}
+				// record the one which actually landed as a conflict, it isn't a version of the local message
+				if err = saveConflictVersion(txRepo, localMsg.ID, msg.signedCID, msg.msg); err != nil {
+					return err
+				}
 				replaceMsg[localMsg.ID] = localMsg
 			} else {
-				if err = txRepo.MessageRepo().UpdateMessageInfoByCid(msg.msg.Cid().String(), msg.receipt, msg.height, types.OnChainMsg, msg.tsk); err != nil {
+				oldState := localMsg.State
+				localMsg.State = types.OnChainMsg
+				if err = txRepo.MessageHistoryRepo().CreateMessageHistory(newMessageHistory(localMsg, oldState, mtypes.TriggerStateRefresh, int64(msg.height))); err != nil {
+					return err
+				}
+				if err = saveIncludedVersion(txRepo, localMsg.ID, msg.signedCID, msg.msg); err != nil {
+					return err
+				}
+				if restored {
+					localMsg.Receipt = msg.receipt
+					localMsg.Height = int64(msg.height)
+					localMsg.TipSetKey = msg.tsk
+					if err = txRepo.MessageRepo().UpdateMessage(localMsg); err != nil {
+						return fmt.Errorf("update message failed, cid:%s failed:%v", msg.signedCID, err)
+					}
+				} else if err = txRepo.MessageRepo().UpdateMessageInfoByCid(msg.msg.Cid().String(), msg.receipt, msg.height, types.OnChainMsg, msg.tsk); err != nil {
 					return fmt.Errorf("update message receipt failed, cid:%s failed:%v", msg.msg.Cid(), err)
 				}
 			}
@@ -155,7 +204,7 @@ func (ms *MessageService) processRevertHead(ctx context.Context, h *headChan) (m
 
 		addrs := ms.addressService.ActiveAddresses(ctx)
 		for _, msg := range msgs {
-			if _, ok := addrs[msg.From]; ok && msg.UnsignedCid != nil {
+			if _, ok := addrs[msg.From]; ok && msg.UnsignedCid != nil && ms.sharding.Owns(msg.From) {
 				revertMsgs[*msg.UnsignedCid] = struct{}{}
 			}
 		}
@@ -193,7 +242,7 @@ func (ms *MessageService) processBlockParentMessages(ctx context.Context, apply
 
 		for i := range receipts {
 			msg := msgs[i].Message
-			if _, ok := addrs[msg.From]; ok {
+			if _, ok := addrs[msg.From]; ok && ms.sharding.Owns(msg.From) {
 				applyMsgs = append(applyMsgs, applyMessage{
 					height:    ts.Height(),
 					tsk:       ts.Key(),
@@ -11,6 +11,7 @@ import (
 	"github.com/ipfs/go-cid"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/filecoin-project/venus-messager/models/repo"
 	"github.com/filecoin-project/venus-messager/testhelper"
 
 	"github.com/filecoin-project/venus/pkg/constants"
@@ -182,6 +183,99 @@ func TestDoRefreshMessageState(t *testing.T) {
 			assert.Equal(t, msgLookup.Height, abi.ChainEpoch(res.Height))
 			assert.Equal(t, msgLookup.TipSet, res.TipSetKey)
 			assert.Equal(t, msgLookup.Receipt, *res.Receipt)
+
+			// the landed message is recorded as a conflict, not a version of the local message
+			versions, err := ms.ListMessageVersion(ctx, msg.ID)
+			assert.NoError(t, err)
+			conflicts := 0
+			for _, v := range versions {
+				assert.False(t, v.Included)
+				assert.Equal(t, v.SignedCid == *cm.replacedMsgs[i].SignedCid, v.Conflict)
+				if v.Conflict {
+					conflicts++
+				}
+			}
+			assert.Equal(t, 1, conflicts)
+
+			// the conflict message is not found by its signed cid
+			_, err = ms.GetMessageBySignedCid(ctx, *cm.replacedMsgs[i].SignedCid)
+			assert.Error(t, err)
+		}
+	})
+
+	t.Run("old version landed", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		msh := newMessageServiceHelper(ctx, t)
+		defer msh.lc.RequireStop()
+
+		msh.genAddresses()
+		addrs := msh.addrs
+		ms := msh.MessageService
+		msh.start()
+
+		_ = msh.genAndPushMessages(len(addrs) * 2)
+
+		ts, err := msh.fullNode.ChainHead(ctx)
+		assert.NoError(t, err)
+		selectResult := selectMsgWithAddress(ctx, t, msh, addrs, ts)
+		assert.Len(t, selectResult.SelectMsg, len(addrs)*2)
+
+		// replace the selected messages locally, but the versions signed before replaced land on chain
+		replacedMsgs := make([]*types.Message, 0, len(selectResult.SelectMsg))
+		for _, msg := range selectResult.SelectMsg {
+			msgCopy := *msg
+			msgCopy.GasLimit = int64(float64(msgCopy.GasLimit) * 1.5)
+			msgCopy.GasFeeCap = big.Mul(msgCopy.GasFeeCap, big.NewInt(2))
+			c := msgCopy.Message.Cid()
+			msgCopy.UnsignedCid = &c
+			signedCID := (&shared.SignedMessage{
+				Message:   msgCopy.Message,
+				Signature: *msg.Signature,
+			}).Cid()
+			msgCopy.SignedCid = &signedCID
+			replacedMsgs = append(replacedMsgs, &msgCopy)
+		}
+		assert.NoError(t, ms.repo.Transaction(func(txRepo repo.TxRepo) error {
+			for _, msg := range replacedMsgs {
+				if err := txRepo.MessageRepo().UpdateMessage(msg); err != nil {
+					return err
+				}
+				if err := txRepo.MessageVersionRepo().SaveMessageVersion(newMessageVersion(msg)); err != nil {
+					return err
+				}
+			}
+			return nil
+		}))
+
+		ctx, calcel := context.WithTimeout(ctx, time.Minute*3)
+		defer calcel()
+
+		go func() {
+			ms.msgSelectMgr.msgReceiver <- selectResult.ToPushMsg
+		}()
+		for i, msg := range selectResult.SelectMsg {
+			res, err := waitMsgWithTimeout(ctx, ms, msg.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, types.OnChainMsg, res.State)
+			assert.Equal(t, msg.GasLimit, res.GasLimit)
+			assert.Equal(t, msg.GasFeeCap, res.GasFeeCap)
+			assert.Equal(t, msg.UnsignedCid, res.UnsignedCid)
+			assert.Equal(t, msg.SignedCid, res.SignedCid)
+
+			versions, err := ms.ListMessageVersion(ctx, msg.ID)
+			assert.NoError(t, err)
+			assert.Len(t, versions, 2)
+			for _, v := range versions {
+				assert.False(t, v.Conflict)
+				assert.Equal(t, v.SignedCid == *msg.SignedCid, v.Included)
+				assert.NotEqual(t, v.SignedCid == *replacedMsgs[i].SignedCid, v.Included)
+			}
+
+			found, err := ms.GetMessageBySignedCid(ctx, *msg.SignedCid)
+			assert.NoError(t, err)
+			assert.Equal(t, msg.ID, found.ID)
 		}
 	})
 
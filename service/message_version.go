@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ipfs/go-cid"
+	"gorm.io/gorm"
+
+	venusTypes "github.com/filecoin-project/venus/venus-shared/types"
+	types "github.com/filecoin-project/venus/venus-shared/types/messager"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/models/repo"
+)
+
+// newMessageVersion build a version from the signed message, msg.SignedCid must not be nil
+func newMessageVersion(msg *types.Message) *mtypes.MessageVersion {
+	return &mtypes.MessageVersion{
+		SignedCid:  *msg.SignedCid,
+		MsgID:      msg.ID,
+		GasLimit:   msg.GasLimit,
+		GasFeeCap:  msg.GasFeeCap,
+		GasPremium: msg.GasPremium,
+		Signature:  msg.Signature,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// saveIncludedVersion save the version found on chain and mark it as the included one, the version maybe not
+// signed by messager, eg. replaced by other tools with the same nonce
+func saveIncludedVersion(txRepo repo.TxRepo, msgID string, signedCid cid.Cid, chainMsg *venusTypes.Message) error {
+	if err := txRepo.MessageVersionRepo().SaveMessageVersion(&mtypes.MessageVersion{
+		SignedCid:  signedCid,
+		MsgID:      msgID,
+		GasLimit:   chainMsg.GasLimit,
+		GasFeeCap:  chainMsg.GasFeeCap,
+		GasPremium: chainMsg.GasPremium,
+		CreatedAt:  time.Now(),
+	}); err != nil {
+		return err
+	}
+	return txRepo.MessageVersionRepo().SetIncluded(msgID, signedCid)
+}
+
+// saveConflictVersion save the version which took the nonce of message on chain, it is not a version of the
+// message, so it is only recorded as a conflict and none of the versions of message is included
+func saveConflictVersion(txRepo repo.TxRepo, msgID string, signedCid cid.Cid, chainMsg *venusTypes.Message) error {
+	if err := txRepo.MessageVersionRepo().SaveMessageVersion(&mtypes.MessageVersion{
+		SignedCid:  signedCid,
+		MsgID:      msgID,
+		GasLimit:   chainMsg.GasLimit,
+		GasFeeCap:  chainMsg.GasFeeCap,
+		GasPremium: chainMsg.GasPremium,
+		Conflict:   true,
+		CreatedAt:  time.Now(),
+	}); err != nil {
+		return err
+	}
+	return txRepo.MessageVersionRepo().SetIncluded(msgID, cid.Undef)
+}
+
+func (ms *MessageService) ListMessageVersion(ctx context.Context, id string) ([]*mtypes.MessageVersion, error) {
+	return ms.repo.MessageVersionRepo().ListMessageVersion(id)
+}
+
+// landedVersion returns the version of message msgID whose signed cid is signedCid, nil if it is not a version of the
+// message signed by messager
+func landedVersion(txRepo repo.TxRepo, msgID string, signedCid cid.Cid) (*mtypes.MessageVersion, error) {
+	version, err := txRepo.MessageVersionRepo().GetMessageVersion(signedCid)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if version.MsgID != msgID || version.Conflict {
+		return nil, nil
+	}
+	return version, nil
+}
+
+// useVersion restores the gas and signature of msg to version, unsignedCid is the cid of the unsigned version
+func useVersion(msg *types.Message, version *mtypes.MessageVersion, unsignedCid cid.Cid) {
+	msg.GasLimit = version.GasLimit
+	msg.GasFeeCap = version.GasFeeCap
+	msg.GasPremium = version.GasPremium
+	if version.Signature != nil {
+		msg.Signature = version.Signature
+	}
+	signedCid := version.SignedCid
+	msg.SignedCid = &signedCid
+	msg.UnsignedCid = &unsignedCid
+}
@@ -21,6 +21,10 @@ func MessagerService() fx.Option {
 		fx.Provide(NewSharedParamsService),
 		fx.Provide(NewINodeService),
 		fx.Provide(NewNodeService),
+		fx.Provide(NewApprovalService),
+		fx.Provide(NewAuditService),
+		fx.Provide(NewLeaderElector),
+		fx.Provide(NewAddressSharding),
 	)
 }
 
@@ -32,8 +36,11 @@ func StartNodeEvents(lc fx.Lifecycle, client v1.FullNode, msgService *MessageSer
 
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
-			go msgService.StartPushMessage(ctx, msgService.fsRepo.Config().MessageService.SkipPushMessage)
-			go func() {
+			// only the leader selects and pushes messages
+			go msgService.elector.RunWhenLeader(ctx, "push message", func(ctx context.Context) {
+				msgService.StartPushMessage(ctx, msgService.fsRepo.Config().MessageService.SkipPushMessage)
+			})
+			go msgService.elector.RunWhenLeader(ctx, "listen head changes", func(ctx context.Context) {
 				for {
 					if err := nd.listenHeadChangesOnce(ctx); err != nil {
 						log.Errorf("listen head changes errored: %s", err)
@@ -49,7 +56,7 @@ func StartNodeEvents(lc fx.Lifecycle, client v1.FullNode, msgService *MessageSer
 
 					log.Info("restarting listenHeadChanges")
 				}
-			}()
+			})
 			return nil
 		},
 	})
@@ -0,0 +1,440 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/filecoin-project/go-jsonrpc"
+	"go.uber.org/fx"
+
+	v1 "github.com/filecoin-project/venus/venus-shared/api/chain/v1"
+	"github.com/filecoin-project/venus/venus-shared/api/permission"
+	"github.com/filecoin-project/venus/venus-shared/types"
+
+	"github.com/filecoin-project/venus-messager/config"
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/models/repo"
+	"github.com/filecoin-project/venus-messager/publisher"
+)
+
+const mainNodeName = "mainNode"
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+type poolNode struct {
+	name   string
+	url    string
+	token  string
+	client v1.FullNode
+	closer jsonrpc.ClientCloser
+
+	height    int64
+	err       error
+	checkedAt time.Time
+}
+
+// NodePool is a v1.FullNode which routes every call to the active node. When failover is enabled, the active
+// node is the healthiest one of the main node and the nodes added by `node add`, the pool switches to another
+// node when the active one disconnected or fell behind, and read calls are retried on other healthy nodes when
+// failed to connect.
+type NodePool struct {
+	v1.FullNodeStruct
+
+	cfg      *config.NodeConfig
+	mainNode *poolNode
+
+	lk     sync.RWMutex
+	nodes  map[string]*poolNode
+	active *poolNode
+	// changed is closed and replaced when the active node changed
+	changed chan struct{}
+
+	dial func(ctx context.Context, url, token string) (v1.FullNode, jsonrpc.ClientCloser, error)
+}
+
+func NewNodePool(cfg *config.NodeConfig, mainNode v1.FullNode) *NodePool {
+	main := &poolNode{name: mainNodeName, url: cfg.Url, client: mainNode}
+	np := &NodePool{
+		cfg:      cfg,
+		mainNode: main,
+		nodes:    map[string]*poolNode{mainNodeName: main},
+		active:   main,
+		changed:  make(chan struct{}),
+		dial: func(ctx context.Context, url, token string) (v1.FullNode, jsonrpc.ClientCloser, error) {
+			return v1.DialFullNodeRPC(ctx, url, token, nil)
+		},
+	}
+	np.bindStruct(reflect.ValueOf(&np.FullNodeStruct).Elem())
+
+	return np
+}
+
+// StartNodePool keeps checking the health of nodes when failover enabled
+func StartNodePool(lc fx.Lifecycle, np *NodePool, nodeProvider repo.INodeProvider) {
+	if !np.cfg.EnableFailover {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go func() {
+				defer close(done)
+				np.run(ctx, nodeProvider)
+			}()
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			cancel()
+			<-done
+			np.closeNodes()
+			return nil
+		},
+	})
+}
+
+func (np *NodePool) run(ctx context.Context, nodeProvider repo.INodeProvider) {
+	tm := time.NewTicker(np.cfg.HealthCheckInterval)
+	defer tm.Stop()
+
+	np.checkHealth(ctx, nodeProvider)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tm.C:
+			np.checkHealth(ctx, nodeProvider)
+		}
+	}
+}
+
+func (np *NodePool) checkHealth(ctx context.Context, nodeProvider repo.INodeProvider) {
+	np.syncNodes(ctx, nodeProvider)
+
+	np.lk.RLock()
+	nodes := make([]*poolNode, 0, len(np.nodes))
+	for _, node := range np.nodes {
+		nodes = append(nodes, node)
+	}
+	np.lk.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, node := range nodes {
+		if node.client == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(node *poolNode) {
+			defer wg.Done()
+			cctx, cancel := context.WithTimeout(ctx, np.cfg.HealthCheckInterval)
+			defer cancel()
+			head, err := node.client.ChainHead(cctx)
+
+			np.lk.Lock()
+			defer np.lk.Unlock()
+			node.err = err
+			node.checkedAt = time.Now()
+			if err == nil {
+				node.height = int64(head.Height())
+			}
+		}(node)
+	}
+	wg.Wait()
+
+	np.lk.Lock()
+	np.selectActive()
+	np.lk.Unlock()
+}
+
+// syncNodes dial the nodes added and close the nodes removed
+func (np *NodePool) syncNodes(ctx context.Context, nodeProvider repo.INodeProvider) {
+	nodeList, err := nodeProvider.ListNode()
+	if err != nil {
+		log.Warnf("list node failed: %v", err)
+		return
+	}
+
+	np.lk.RLock()
+	exists := make(map[string]*poolNode, len(np.nodes))
+	for name, node := range np.nodes {
+		exists[name] = node
+	}
+	np.lk.RUnlock()
+
+	nodes := map[string]*poolNode{mainNodeName: np.mainNode}
+	for _, n := range nodeList {
+		if n.Name == mainNodeName {
+			continue
+		}
+		if node, ok := exists[n.Name]; ok && node.client != nil && node.url == n.URL && node.token == n.Token {
+			nodes[n.Name] = node
+			delete(exists, n.Name)
+			continue
+		}
+		node := &poolNode{name: n.Name, url: n.URL, token: n.Token}
+		node.client, node.closer, node.err = np.dial(ctx, n.URL, n.Token)
+		if node.err != nil {
+			log.Warnf("connect node %s failed: %v", n.Name, node.err)
+			node.client = nil
+		}
+		nodes[n.Name] = node
+	}
+
+	np.lk.Lock()
+	np.nodes = nodes
+	if _, ok := nodes[np.active.name]; !ok || nodes[np.active.name] != np.active {
+		np.active.err = errors.New("node removed")
+		np.selectActive()
+	}
+	np.lk.Unlock()
+
+	for name, node := range exists {
+		if _, ok := nodes[name]; ok && nodes[name] == node {
+			continue
+		}
+		if node.closer != nil {
+			node.closer()
+		}
+	}
+}
+
+func (np *NodePool) healthy(node *poolNode, maxHeight int64) bool {
+	return node.client != nil && node.err == nil && !node.checkedAt.IsZero() && node.height >= maxHeight-np.cfg.MaxLag
+}
+
+func (np *NodePool) maxHeight() int64 {
+	var maxHeight int64
+	for _, node := range np.nodes {
+		if node.err == nil && node.height > maxHeight {
+			maxHeight = node.height
+		}
+	}
+	return maxHeight
+}
+
+// selectActive keeps the active node if it is healthy, otherwise prefer the main node and then the highest node,
+// it must be called with lock held.
+func (np *NodePool) selectActive() {
+	maxHeight := np.maxHeight()
+	if _, ok := np.nodes[np.active.name]; ok && np.healthy(np.active, maxHeight) {
+		return
+	}
+
+	var candidate *poolNode
+	if np.healthy(np.mainNode, maxHeight) {
+		candidate = np.mainNode
+	} else {
+		for _, node := range np.sortedNodes() {
+			if np.healthy(node, maxHeight) && (candidate == nil || node.height > candidate.height) {
+				candidate = node
+			}
+		}
+	}
+	if candidate == nil {
+		if _, ok := np.nodes[np.active.name]; ok {
+			return
+		}
+		candidate = np.mainNode
+	}
+	if candidate == np.active {
+		return
+	}
+
+	log.Warnf("switch active node from %s(height %d, err %v) to %s(height %d)", np.active.name, np.active.height,
+		np.active.err, candidate.name, candidate.height)
+	np.active = candidate
+	close(np.changed)
+	np.changed = make(chan struct{})
+}
+
+func (np *NodePool) sortedNodes() []*poolNode {
+	nodes := make([]*poolNode, 0, len(np.nodes))
+	for _, node := range np.nodes {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].name < nodes[j].name
+	})
+	return nodes
+}
+
+// candidates returns the active node first and then other healthy nodes
+func (np *NodePool) candidates() []*poolNode {
+	np.lk.RLock()
+	defer np.lk.RUnlock()
+
+	nodes := []*poolNode{np.active}
+	if !np.cfg.EnableFailover {
+		return nodes
+	}
+	maxHeight := np.maxHeight()
+	for _, node := range np.sortedNodes() {
+		if node != np.active && np.healthy(node, maxHeight) {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+func (np *NodePool) markFailed(node *poolNode, err error) {
+	np.lk.Lock()
+	defer np.lk.Unlock()
+	node.err = err
+	np.selectActive()
+}
+
+func (np *NodePool) closeNodes() {
+	np.lk.Lock()
+	defer np.lk.Unlock()
+	for _, node := range np.nodes {
+		if node.closer != nil {
+			node.closer()
+		}
+	}
+}
+
+func (np *NodePool) bindStruct(rv reflect.Value) {
+	rt := rv.Type()
+	for i := 0; i < rv.NumField(); i++ {
+		field := rt.Field(i)
+		if field.Name == "Internal" {
+			np.bindInternal(rv.Field(i))
+			continue
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			np.bindStruct(rv.Field(i))
+		}
+	}
+}
+
+func (np *NodePool) bindInternal(rv reflect.Value) {
+	rt := rv.Type()
+	for i := 0; i < rv.NumField(); i++ {
+		field := rt.Field(i)
+		if field.Type.Kind() != reflect.Func {
+			continue
+		}
+		if field.Name == "ChainNotify" {
+			rv.Field(i).Set(reflect.ValueOf(np.chainNotify))
+			continue
+		}
+
+		name, ft := field.Name, field.Type
+		retry := np.cfg.EnableFailover && field.Tag.Get("perm") == string(permission.PermRead) &&
+			ft.NumOut() > 0 && ft.Out(ft.NumOut()-1) == errorType
+		rv.Field(i).Set(reflect.MakeFunc(ft, func(args []reflect.Value) []reflect.Value {
+			return np.call(name, ft, retry, args)
+		}))
+	}
+}
+
+func (np *NodePool) call(method string, ft reflect.Type, retry bool, args []reflect.Value) []reflect.Value {
+	var out []reflect.Value
+	for _, node := range np.candidates() {
+		fn := reflect.ValueOf(node.client).MethodByName(method)
+		if ft.IsVariadic() {
+			out = fn.CallSlice(args)
+		} else {
+			out = fn.Call(args)
+		}
+		if !retry {
+			return out
+		}
+		err, _ := out[len(out)-1].Interface().(error)
+		var clientErr *jsonrpc.ErrClient
+		if err == nil || !errors.As(err, &clientErr) {
+			return out
+		}
+		log.Warnf("call %s on node %s failed: %v, try next node", method, node.name, err)
+		np.markFailed(node, err)
+	}
+	return out
+}
+
+// chainNotify follows the head changes of the active node, the returned channel is closed when the active node
+// changed, the caller should call it again to follow the new one.
+func (np *NodePool) chainNotify(ctx context.Context) (<-chan []*types.HeadChange, error) {
+	np.lk.RLock()
+	node, changed := np.active, np.changed
+	np.lk.RUnlock()
+
+	notifs, err := node.client.ChainNotify(ctx)
+	if err != nil {
+		if np.cfg.EnableFailover {
+			np.markFailed(node, err)
+		}
+		return nil, err
+	}
+	log.Infof("follow chain head from node %s", node.name)
+
+	out := make(chan []*types.HeadChange)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case notif, ok := <-notifs:
+				if !ok {
+					if np.cfg.EnableFailover {
+						np.markFailed(node, errors.New("chain notify closed"))
+					}
+					return
+				}
+				select {
+				case out <- notif:
+				case <-changed:
+					return
+				case <-ctx.Done():
+					return
+				}
+			case <-changed:
+				log.Warnf("active node changed, stop following chain head from node %s", node.name)
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+var _ publisher.ActiveNodeGetter = (*NodePool)(nil)
+
+// ActiveNode returns the node which calls are routed to now
+func (np *NodePool) ActiveNode() (string, string, v1.FullNode) {
+	np.lk.RLock()
+	defer np.lk.RUnlock()
+	return np.active.name, np.active.url, np.active.client
+}
+
+func (np *NodePool) NodePoolInfo(ctx context.Context) (*mtypes.NodePoolInfo, error) {
+	np.lk.RLock()
+	defer np.lk.RUnlock()
+
+	info := &mtypes.NodePoolInfo{
+		FailoverEnabled: np.cfg.EnableFailover,
+		Active:          np.active.name,
+	}
+	if !np.cfg.EnableFailover {
+		return info, nil
+	}
+	maxHeight := np.maxHeight()
+	for _, node := range np.sortedNodes() {
+		health := &mtypes.NodeHealth{
+			Name:      node.name,
+			Height:    node.height,
+			Healthy:   np.healthy(node, maxHeight),
+			CheckedAt: node.checkedAt,
+		}
+		if node.err != nil {
+			health.Error = node.err.Error()
+		}
+		info.Nodes = append(info.Nodes, health)
+	}
+
+	return info, nil
+}
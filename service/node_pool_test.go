@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-jsonrpc"
+	"github.com/filecoin-project/go-state-types/abi"
+	v1 "github.com/filecoin-project/venus/venus-shared/api/chain/v1"
+	venustypes "github.com/filecoin-project/venus/venus-shared/types"
+	types "github.com/filecoin-project/venus/venus-shared/types/messager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/venus-messager/config"
+	"github.com/filecoin-project/venus-messager/testhelper"
+)
+
+type fakePoolNode struct {
+	v1.FullNode
+
+	lk      sync.Mutex
+	height  abi.ChainEpoch
+	err     error
+	actor   *venustypes.Actor
+	notifys []chan []*venustypes.HeadChange
+}
+
+func (f *fakePoolNode) set(height abi.ChainEpoch, err error) {
+	f.lk.Lock()
+	defer f.lk.Unlock()
+	f.height, f.err = height, err
+}
+
+func (f *fakePoolNode) ChainHead(ctx context.Context) (*venustypes.TipSet, error) {
+	f.lk.Lock()
+	defer f.lk.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	return testhelper.GenTipset(f.height, 1, nil)
+}
+
+func (f *fakePoolNode) StateGetActor(ctx context.Context, addr address.Address, tsk venustypes.TipSetKey) (*venustypes.Actor, error) {
+	f.lk.Lock()
+	defer f.lk.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.actor, nil
+}
+
+func (f *fakePoolNode) ChainNotify(ctx context.Context) (<-chan []*venustypes.HeadChange, error) {
+	f.lk.Lock()
+	defer f.lk.Unlock()
+	ch := make(chan []*venustypes.HeadChange, 1)
+	f.notifys = append(f.notifys, ch)
+	return ch, nil
+}
+
+type fakeNodeProvider []*types.Node
+
+func (f fakeNodeProvider) ListNode() ([]*types.Node, error) {
+	return f, nil
+}
+
+func TestNodePool(t *testing.T) {
+	ctx := context.Background()
+
+	mainNode := &fakePoolNode{height: 100, actor: &venustypes.Actor{Nonce: 1}}
+	backupNode := &fakePoolNode{height: 100, actor: &venustypes.Actor{Nonce: 2}}
+
+	t.Run("failover disabled", func(t *testing.T) {
+		cfg := config.DefaultConfig().Node
+		np := NewNodePool(&cfg, mainNode)
+
+		actor, err := np.StateGetActor(ctx, address.Undef, venustypes.EmptyTSK)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), actor.Nonce)
+
+		info, err := np.NodePoolInfo(ctx)
+		assert.NoError(t, err)
+		assert.False(t, info.FailoverEnabled)
+		assert.Equal(t, mainNodeName, info.Active)
+	})
+
+	cfg := config.DefaultConfig().Node
+	cfg.EnableFailover = true
+	np := NewNodePool(&cfg, mainNode)
+	np.dial = func(ctx context.Context, url, token string) (v1.FullNode, jsonrpc.ClientCloser, error) {
+		return backupNode, func() {}, nil
+	}
+	provider := fakeNodeProvider{{ID: venustypes.NewUUID(), Name: "backup", URL: "backup-url"}}
+
+	np.checkHealth(ctx, provider)
+	info, err := np.NodePoolInfo(ctx)
+	assert.NoError(t, err)
+	assert.True(t, info.FailoverEnabled)
+	assert.Equal(t, mainNodeName, info.Active)
+	assert.Len(t, info.Nodes, 2)
+
+	notifs, err := np.ChainNotify(ctx)
+	require.NoError(t, err)
+
+	// main node falls behind, switch to backup node and stop following main node
+	mainNode.set(100, nil)
+	backupNode.set(100+abi.ChainEpoch(cfg.MaxLag)+1, nil)
+	np.checkHealth(ctx, provider)
+	info, err = np.NodePoolInfo(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "backup", info.Active)
+	select {
+	case _, ok := <-notifs:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("chain notify of old node not closed")
+	}
+
+	actor, err := np.StateGetActor(ctx, address.Undef, venustypes.EmptyTSK)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), actor.Nonce)
+
+	// main node catches up, keep using backup node
+	mainNode.set(100+abi.ChainEpoch(cfg.MaxLag)+1, nil)
+	np.checkHealth(ctx, provider)
+	assert.Equal(t, "backup", np.candidates()[0].name)
+
+	// backup node disconnected, read call retried on main node
+	backupNode.set(0, &jsonrpc.ErrClient{})
+	actor, err = np.StateGetActor(ctx, address.Undef, venustypes.EmptyTSK)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), actor.Nonce)
+	assert.Equal(t, mainNodeName, np.candidates()[0].name)
+
+	// other errors are returned directly
+	mainNode.set(0, errors.New("actor not found"))
+	_, err = np.StateGetActor(ctx, address.Undef, venustypes.EmptyTSK)
+	assert.EqualError(t, err, "actor not found")
+	assert.Equal(t, mainNodeName, np.candidates()[0].name)
+}
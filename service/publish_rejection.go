@@ -0,0 +1,42 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/ipfs/go-cid"
+	"gorm.io/gorm"
+
+	types "github.com/filecoin-project/venus/venus-shared/types/messager"
+
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/models/repo"
+	"github.com/filecoin-project/venus-messager/publisher"
+)
+
+// WatchPublishRejection records the persistent rejections of nodes in the error of message, so that user can see why
+// the message never landed
+func WatchPublishRejection(rpcPublisher *publisher.RpcPublisher, msgService *MessageService) {
+	rpcPublisher.OnRejected(msgService.recordPublishRejection)
+}
+
+func (ms *MessageService) recordPublishRejection(signedCid cid.Cid, pubErr *publisher.PublishError) {
+	errMsg := pubErr.Error()
+	err := ms.repo.Transaction(func(txRepo repo.TxRepo) error {
+		msg, err := txRepo.MessageRepo().GetMessageBySignedCid(signedCid)
+		if err != nil {
+			return err
+		}
+		// the message may be replaced or landed
+		if msg.State != types.FillMsg || msg.ErrorMsg == errMsg {
+			return nil
+		}
+		msg.ErrorMsg = errMsg
+		if err := txRepo.MessageHistoryRepo().CreateMessageHistory(newMessageHistory(msg, msg.State, mtypes.TriggerPublisher, ms.currentHeight())); err != nil {
+			return err
+		}
+		return txRepo.MessageRepo().UpdateErrMsg(msg.ID, errMsg)
+	})
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Warnf("record rejection of message %s failed: %v", signedCid, err)
+	}
+}
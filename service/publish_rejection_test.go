@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/filecoin-project/venus/venus-shared/types/messager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/venus-messager/filestore"
+	"github.com/filecoin-project/venus-messager/models"
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/publisher"
+	"github.com/filecoin-project/venus-messager/testhelper"
+)
+
+func TestRecordPublishRejection(t *testing.T) {
+	ctx := context.Background()
+
+	fsRepo := filestore.NewMockFileStore(t.TempDir())
+	repo, err := models.SetDataBase(fsRepo)
+	require.NoError(t, err)
+	require.NoError(t, repo.AutoMigrate())
+
+	msgs := testhelper.NewSignedMessages(2)
+	msgs[0].State = messager.FillMsg
+	msgs[1].State = messager.OnChainMsg
+	for _, msg := range msgs {
+		require.NoError(t, repo.MessageRepo().CreateMessage(msg))
+	}
+
+	ms := &MessageService{repo: repo, tsCache: newTipsetCache()}
+	pubErr := &publisher.PublishError{Node: "node", Class: mtypes.PublishErrFeeCapTooLow, Err: errors.New("gas fee cap too low")}
+
+	ms.recordPublishRejection(*msgs[0].SignedCid, pubErr)
+	msg, err := repo.MessageRepo().GetMessageByUid(msgs[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, pubErr.Error(), msg.ErrorMsg)
+	history, err := ms.ListMessageHistory(ctx, msgs[0].ID)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, mtypes.TriggerPublisher, history[0].Trigger)
+
+	// recorded only once
+	ms.recordPublishRejection(*msgs[0].SignedCid, pubErr)
+	history, err = ms.ListMessageHistory(ctx, msgs[0].ID)
+	require.NoError(t, err)
+	assert.Len(t, history, 1)
+
+	// landed message is not changed
+	ms.recordPublishRejection(*msgs[1].SignedCid, pubErr)
+	msg, err = repo.MessageRepo().GetMessageByUid(msgs[1].ID)
+	require.NoError(t, err)
+	assert.Empty(t, msg.ErrorMsg)
+
+	// unknown message is ignored
+	ms.recordPublishRejection(testhelper.NewShareSignedMessage().Cid(), pubErr)
+}
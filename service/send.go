@@ -12,11 +12,15 @@ import (
 
 	"github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
 
 	"github.com/filecoin-project/venus/venus-shared/actors/builtin"
+	v1 "github.com/filecoin-project/venus/venus-shared/api/chain/v1"
 	venusTypes "github.com/filecoin-project/venus/venus-shared/types"
 	types "github.com/filecoin-project/venus/venus-shared/types/messager"
 	msgparser "github.com/filecoin-project/venus/venus-shared/utils/msg_parser"
+
+	"github.com/filecoin-project/venus-messager/config"
 )
 
 func (ms *MessageService) Send(ctx context.Context, params types.QuickSendParams) (string, error) {
@@ -24,16 +28,20 @@ func (ms *MessageService) Send(ctx context.Context, params types.QuickSendParams
 	var err error
 
 	if params.Method == builtin.MethodSend {
-		return "", fmt.Errorf("do not use it to send funds")
+		if err := ms.checkTransfer(ctx, params.From, params.To, params.Val); err != nil {
+			return "", err
+		}
 	}
 
-	switch params.ParamsType {
-	case types.QuickSendParamsCodecJSON:
+	switch {
+	case params.Method == builtin.MethodSend && len(params.Params) == 0:
+		// plain transfer, no params
+	case params.ParamsType == types.QuickSendParamsCodecJSON:
 		decParams, err = ms.decodeTypedParamsFromJSON(ctx, params.To, params.Method, params.Params)
 		if err != nil {
 			return "", fmt.Errorf("failed to decode json params: %w", err)
 		}
-	case types.QuickSendParamsCodecHex:
+	case params.ParamsType == types.QuickSendParamsCodecHex:
 		decParams, err = hex.DecodeString(params.Params)
 		if err != nil {
 			return "", fmt.Errorf("failed to decode hex params: %w", err)
@@ -107,3 +115,120 @@ func (ms *MessageService) decodeTypedParamsFromJSON(ctx context.Context, to addr
 	}
 	return buf.Bytes(), nil
 }
+
+// transferPolicy is the parsed `[transfer]` config, an address matches the config in either ID or robust form
+type transferPolicy struct {
+	nodeClient v1.FullNode
+
+	enable       bool
+	maxValue     big.Int
+	addrMaxValue map[address.Address]big.Int
+	allowedTo    map[address.Address]struct{}
+}
+
+func newTransferPolicy(ctx context.Context, nc v1.FullNode, cfg *config.TransferConfig) (*transferPolicy, error) {
+	tp := &transferPolicy{
+		nodeClient:   nc,
+		maxValue:     big.Zero(),
+		addrMaxValue: make(map[address.Address]big.Int),
+		allowedTo:    make(map[address.Address]struct{}),
+	}
+	if cfg == nil {
+		return tp, nil
+	}
+	tp.enable = cfg.Enable
+
+	parseFIL := func(v string) (big.Int, error) {
+		if len(v) == 0 {
+			return big.Zero(), nil
+		}
+		fil, err := venusTypes.ParseFIL(v)
+		if err != nil {
+			return big.Zero(), err
+		}
+		return big.Int(fil), nil
+	}
+	var err error
+	if tp.maxValue, err = parseFIL(cfg.MaxValue); err != nil {
+		return nil, fmt.Errorf("parse transfer max value %s failed: %v", cfg.MaxValue, err)
+	}
+	for a, v := range cfg.AddressMaxValue {
+		addr, err := address.NewFromString(a)
+		if err != nil {
+			return nil, fmt.Errorf("parse transfer address %s failed: %v", a, err)
+		}
+		limit, err := parseFIL(v)
+		if err != nil {
+			return nil, fmt.Errorf("parse transfer max value %s of %s failed: %v", v, a, err)
+		}
+		for _, form := range tp.forms(ctx, addr) {
+			tp.addrMaxValue[form] = limit
+		}
+	}
+	for _, a := range cfg.AllowedTo {
+		addr, err := address.NewFromString(a)
+		if err != nil {
+			return nil, fmt.Errorf("parse transfer address %s failed: %v", a, err)
+		}
+		for _, form := range tp.forms(ctx, addr) {
+			tp.allowedTo[form] = struct{}{}
+		}
+	}
+
+	return tp, nil
+}
+
+// forms returns addr with its ID address and key address which can be found from node
+func (tp *transferPolicy) forms(ctx context.Context, addr address.Address) []address.Address {
+	forms := []address.Address{addr}
+	if tp.nodeClient == nil {
+		return forms
+	}
+	if addr.Protocol() == address.ID {
+		if key, err := tp.nodeClient.StateAccountKey(ctx, addr, venusTypes.EmptyTSK); err == nil {
+			forms = append(forms, key)
+		}
+	} else if id, err := tp.nodeClient.StateLookupID(ctx, addr, venusTypes.EmptyTSK); err == nil {
+		forms = append(forms, id)
+	}
+	return forms
+}
+
+// check checks the destination allowlist and the value limits of a transfer
+func (tp *transferPolicy) check(ctx context.Context, from, to address.Address, val abi.TokenAmount) error {
+	if len(tp.allowedTo) != 0 {
+		allowed := false
+		for _, form := range tp.forms(ctx, to) {
+			if _, ok := tp.allowedTo[form]; ok {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("destination %s is not in the allowlist", to)
+		}
+	}
+
+	limit := tp.maxValue
+	if len(tp.addrMaxValue) != 0 {
+		for _, form := range tp.forms(ctx, from) {
+			if v, ok := tp.addrMaxValue[form]; ok {
+				limit = v
+				break
+			}
+		}
+	}
+	if !limit.IsZero() && val.GreaterThan(limit) {
+		return fmt.Errorf("value %s exceeds the limit %s of %s", venusTypes.FIL(val), venusTypes.FIL(limit), from)
+	}
+
+	return nil
+}
+
+// checkTransfer check whether the transfer is allowed by `[transfer]` config
+func (ms *MessageService) checkTransfer(ctx context.Context, from, to address.Address, val abi.TokenAmount) error {
+	if !ms.transfer.enable {
+		return fmt.Errorf("send funds is disabled, set `transfer.enable` to enable it")
+	}
+	return ms.transfer.check(ctx, from, to, val)
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	venusTypes "github.com/filecoin-project/venus/venus-shared/types"
+
+	"github.com/filecoin-project/venus-messager/config"
+	"github.com/filecoin-project/venus-messager/testhelper"
+)
+
+func TestCheckTransfer(t *testing.T) {
+	ctx := context.Background()
+	fullNode, err := testhelper.NewMockFullNode(ctx, time.Second)
+	require.NoError(t, err)
+
+	addrs := testhelper.RandAddresses(t, 3)
+	from, to, other := addrs[0], addrs[1], addrs[2]
+	fil := func(v string) abi.TokenAmount {
+		return abi.TokenAmount(venusTypes.MustParseFIL(v))
+	}
+	idAddr := func(id uint64) (address.Address, address.Address) {
+		addr, err := address.NewIDAddress(id)
+		require.NoError(t, err)
+		robust, err := testhelper.ResolveIDAddr(addr)
+		require.NoError(t, err)
+		return addr, robust
+	}
+
+	newService := func(cfg *config.TransferConfig) *MessageService {
+		tp, err := newTransferPolicy(ctx, fullNode, cfg)
+		require.NoError(t, err)
+		return &MessageService{transfer: tp}
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		ms := newService(config.DefaultConfig().Transfer)
+		assert.Error(t, ms.checkTransfer(ctx, from, to, fil("1")))
+	})
+
+	t.Run("no limit", func(t *testing.T) {
+		ms := newService(&config.TransferConfig{Enable: true})
+		assert.NoError(t, ms.checkTransfer(ctx, from, to, fil("10000")))
+	})
+
+	t.Run("max value", func(t *testing.T) {
+		ms := newService(&config.TransferConfig{
+			Enable:          true,
+			MaxValue:        "10",
+			AddressMaxValue: map[string]string{from.String(): "1"},
+		})
+		assert.NoError(t, ms.checkTransfer(ctx, from, to, fil("1")))
+		assert.Error(t, ms.checkTransfer(ctx, from, to, fil("1.1")))
+		assert.NoError(t, ms.checkTransfer(ctx, other, to, fil("10")))
+		assert.Error(t, ms.checkTransfer(ctx, other, to, fil("11")))
+	})
+
+	t.Run("allowlist", func(t *testing.T) {
+		ms := newService(&config.TransferConfig{
+			Enable:    true,
+			AllowedTo: []string{to.String()},
+		})
+		assert.NoError(t, ms.checkTransfer(ctx, from, to, fil("1")))
+		assert.Error(t, ms.checkTransfer(ctx, from, other, fil("1")))
+	})
+
+	t.Run("resolved address", func(t *testing.T) {
+		fromID, fromRobust := idAddr(1001)
+		toID, toRobust := idAddr(1002)
+		ms := newService(&config.TransferConfig{
+			Enable:          true,
+			AddressMaxValue: map[string]string{fromID.String(): "1"},
+			AllowedTo:       []string{toRobust.String()},
+		})
+		// the limit of ID address also applies to its robust address
+		assert.Error(t, ms.checkTransfer(ctx, fromRobust, toRobust, fil("2")))
+		assert.Error(t, ms.checkTransfer(ctx, fromID, toRobust, fil("2")))
+		assert.NoError(t, ms.checkTransfer(ctx, fromRobust, toRobust, fil("1")))
+		// the allowlist of robust address also allows its ID address
+		assert.NoError(t, ms.checkTransfer(ctx, fromRobust, toID, fil("1")))
+		otherID, _ := idAddr(1003)
+		assert.Error(t, ms.checkTransfer(ctx, fromRobust, otherID, fil("1")))
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := newTransferPolicy(ctx, fullNode, &config.TransferConfig{MaxValue: "abc"})
+		assert.Error(t, err)
+		_, err = newTransferPolicy(ctx, fullNode, &config.TransferConfig{AllowedTo: []string{"abc"}})
+		assert.Error(t, err)
+	})
+}
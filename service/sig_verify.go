@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/crypto"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+
+	v1 "github.com/filecoin-project/venus/venus-shared/api/chain/v1"
+	venusTypes "github.com/filecoin-project/venus/venus-shared/types"
+
+	"github.com/filecoin-project/venus-messager/metrics"
+)
+
+var errInvalidSignature = errors.New("invalid signature")
+
+// SigVerifier verifies the signatures returned by wallet locally, a wallet signing with the wrong key would make the
+// message never be packed and the nonce stuck
+type SigVerifier interface {
+	Verify(sig *crypto.Signature, addr address.Address, msg []byte) error
+}
+
+// verifySignature checks sig is signed by the key address of from, the ID address is resolved by the node
+func verifySignature(ctx context.Context,
+	fullNode v1.FullNode,
+	verifier SigVerifier,
+	from address.Address,
+	sig *crypto.Signature,
+	toSign []byte,
+) error {
+	signer := from
+	if from.Protocol() == address.ID {
+		var err error
+		signer, err = fullNode.StateAccountKey(ctx, from, venusTypes.EmptyTSK)
+		if err != nil {
+			return fmt.Errorf("getting key address of %s: %w", from, err)
+		}
+	}
+	if err := verifier.Verify(sig, signer, toSign); err != nil {
+		ctx, _ = tag.New(ctx, tag.Upsert(metrics.WalletAddress, from.String()))
+		stats.Record(ctx, metrics.InvalidSignature.M(1))
+		return fmt.Errorf("%w of %s: %v", errInvalidSignature, from, err)
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+
+	"github.com/filecoin-project/venus-messager/metrics"
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+)
+
+type signState struct {
+	failures int
+	pausedAt time.Time
+	reason   string
+	// probing is set when the address resumes without a successful sign, one more failure pauses it again
+	probing bool
+	// missing is set when the wallet reports it doesn't have the paused address
+	missing bool
+}
+
+// signBreaker pauses signing of an address after `threshold` consecutive failures, so a broken wallet doesn't fail
+// the same message every round. A paused address is tried again after `cooldown`, or earlier when the wallet which
+// lost the address reports having it again.
+type signBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	lk     sync.Mutex
+	states map[address.Address]*signState
+}
+
+func newSignBreaker(threshold int, cooldown time.Duration) *signBreaker {
+	return &signBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		states:    make(map[address.Address]*signState),
+	}
+}
+
+func (sb *signBreaker) enabled() bool {
+	return sb.threshold > 0
+}
+
+// success resets the failures of addr
+func (sb *signBreaker) success(ctx context.Context, addr address.Address) {
+	if !sb.enabled() {
+		return
+	}
+	sb.lk.Lock()
+	defer sb.lk.Unlock()
+	if _, ok := sb.states[addr]; ok {
+		delete(sb.states, addr)
+		recordSignPaused(ctx, addr, false)
+	}
+}
+
+// failure records a signing failure of addr, it returns true if addr is paused by this failure
+func (sb *signBreaker) failure(ctx context.Context, addr address.Address, err error) bool {
+	if !sb.enabled() {
+		return false
+	}
+	sb.lk.Lock()
+	defer sb.lk.Unlock()
+	state, ok := sb.states[addr]
+	if !ok {
+		state = &signState{}
+		sb.states[addr] = state
+	}
+	if !state.pausedAt.IsZero() {
+		return false
+	}
+	state.failures++
+	if state.failures < sb.threshold && !state.probing {
+		return false
+	}
+
+	state.pausedAt = time.Now()
+	state.probing = false
+	state.reason = err.Error()
+	recordSignPaused(ctx, addr, true)
+	return true
+}
+
+// paused returns whether addr is paused, an address whose cooldown has passed is resumed to try once
+func (sb *signBreaker) paused(ctx context.Context, addr address.Address) bool {
+	if !sb.enabled() {
+		return false
+	}
+	sb.lk.Lock()
+	defer sb.lk.Unlock()
+	state, ok := sb.states[addr]
+	if !ok || state.pausedAt.IsZero() {
+		return false
+	}
+	if time.Since(state.pausedAt) < sb.cooldown {
+		return true
+	}
+	sb.halfOpen(ctx, addr, state)
+	return false
+}
+
+// probe records whether the wallet has the paused addr, addr resumes to try once when the wallet has it again after
+// reporting missing. It returns true if addr is resumed.
+func (sb *signBreaker) probe(ctx context.Context, addr address.Address, has bool) bool {
+	sb.lk.Lock()
+	defer sb.lk.Unlock()
+	state, ok := sb.states[addr]
+	if !ok || state.pausedAt.IsZero() {
+		return false
+	}
+	if !has {
+		state.missing = true
+		return false
+	}
+	if !state.missing {
+		return false
+	}
+	sb.halfOpen(ctx, addr, state)
+	return true
+}
+
+func (sb *signBreaker) halfOpen(ctx context.Context, addr address.Address, state *signState) {
+	state.pausedAt = time.Time{}
+	state.probing = true
+	state.missing = false
+	recordSignPaused(ctx, addr, false)
+}
+
+// resume clears the state of addr, it fails if addr is not paused
+func (sb *signBreaker) resume(ctx context.Context, addr address.Address) error {
+	sb.lk.Lock()
+	defer sb.lk.Unlock()
+	state, ok := sb.states[addr]
+	if !ok || state.pausedAt.IsZero() {
+		return fmt.Errorf("address %s is not paused", addr)
+	}
+	delete(sb.states, addr)
+	recordSignPaused(ctx, addr, false)
+	return nil
+}
+
+func (sb *signBreaker) list() []*mtypes.PausedAddress {
+	sb.lk.Lock()
+	defer sb.lk.Unlock()
+	res := make([]*mtypes.PausedAddress, 0, len(sb.states))
+	for addr, state := range sb.states {
+		if state.pausedAt.IsZero() {
+			continue
+		}
+		res = append(res, &mtypes.PausedAddress{
+			Addr:     addr,
+			Reason:   state.reason,
+			Failures: state.failures,
+			PausedAt: state.pausedAt,
+			ResumeAt: state.pausedAt.Add(sb.cooldown),
+		})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Addr.String() < res[j].Addr.String()
+	})
+	return res
+}
+
+func recordSignPaused(ctx context.Context, addr address.Address, paused bool) {
+	var v int64
+	if paused {
+		v = 1
+	}
+	ctx, _ = tag.New(ctx, tag.Upsert(metrics.WalletAddress, addr.String()))
+	stats.Record(ctx, metrics.AddressSignPaused.M(v))
+}
+
+// ListPausedAddress returns the addresses whose signing is paused for repeated failures
+func (ms *MessageService) ListPausedAddress(ctx context.Context) ([]*mtypes.PausedAddress, error) {
+	return ms.msgSelectMgr.breaker.list(), nil
+}
+
+// ResumeAddress resumes signing of the paused address immediately
+func (ms *MessageService) ResumeAddress(ctx context.Context, addr address.Address) error {
+	if err := ms.msgSelectMgr.breaker.resume(ctx, addr); err != nil {
+		return err
+	}
+	log.Infof("resume signing of %s", addr)
+	return nil
+}
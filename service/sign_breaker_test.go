@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/venus-messager/testhelper"
+)
+
+func TestSignBreaker(t *testing.T) {
+	ctx := context.Background()
+	addrs := testhelper.RandAddresses(t, 2)
+	addr, other := addrs[0], addrs[1]
+	signErr := errors.New("wallet not found")
+
+	t.Run("pause after threshold", func(t *testing.T) {
+		sb := newSignBreaker(3, time.Hour)
+		assert.False(t, sb.failure(ctx, addr, signErr))
+		assert.False(t, sb.failure(ctx, addr, signErr))
+		// success resets the failures
+		sb.success(ctx, addr)
+		assert.False(t, sb.failure(ctx, addr, signErr))
+		assert.False(t, sb.failure(ctx, addr, signErr))
+		assert.False(t, sb.paused(ctx, addr))
+		assert.True(t, sb.failure(ctx, addr, signErr))
+		assert.True(t, sb.paused(ctx, addr))
+		assert.False(t, sb.paused(ctx, other))
+
+		paused := sb.list()
+		require.Len(t, paused, 1)
+		assert.Equal(t, addr, paused[0].Addr)
+		assert.Equal(t, signErr.Error(), paused[0].Reason)
+		assert.Equal(t, 3, paused[0].Failures)
+		assert.Equal(t, paused[0].PausedAt.Add(time.Hour), paused[0].ResumeAt)
+
+		assert.Error(t, sb.resume(ctx, other))
+		assert.NoError(t, sb.resume(ctx, addr))
+		assert.False(t, sb.paused(ctx, addr))
+		assert.Len(t, sb.list(), 0)
+	})
+
+	t.Run("resume after cooldown", func(t *testing.T) {
+		sb := newSignBreaker(1, 50*time.Millisecond)
+		assert.True(t, sb.failure(ctx, addr, signErr))
+		assert.True(t, sb.paused(ctx, addr))
+		time.Sleep(60 * time.Millisecond)
+		assert.False(t, sb.paused(ctx, addr))
+		assert.Len(t, sb.list(), 0)
+
+		// one more failure pauses again
+		assert.True(t, sb.failure(ctx, addr, signErr))
+		assert.True(t, sb.paused(ctx, addr))
+	})
+
+	t.Run("resume by probe", func(t *testing.T) {
+		sb := newSignBreaker(2, time.Hour)
+		sb.failure(ctx, addr, signErr)
+		sb.failure(ctx, addr, signErr)
+		assert.True(t, sb.paused(ctx, addr))
+
+		// wallet has the address but fails to sign, wait for cooldown
+		assert.False(t, sb.probe(ctx, addr, true))
+		assert.True(t, sb.paused(ctx, addr))
+
+		// wallet gets the address back
+		assert.False(t, sb.probe(ctx, addr, false))
+		assert.True(t, sb.probe(ctx, addr, true))
+		assert.False(t, sb.paused(ctx, addr))
+
+		assert.True(t, sb.failure(ctx, addr, signErr))
+		assert.True(t, sb.paused(ctx, addr))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		sb := newSignBreaker(0, time.Hour)
+		for i := 0; i < 10; i++ {
+			assert.False(t, sb.failure(ctx, addr, signErr))
+		}
+		assert.False(t, sb.paused(ctx, addr))
+		assert.Len(t, sb.list(), 0)
+	})
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/filecoin-project/venus-messager/config"
+	"github.com/filecoin-project/venus-messager/metrics"
+)
+
+type signerEntry struct {
+	accounts  []string
+	fetchedAt time.Time
+}
+
+// signerCache caches the users bound to signers, so the auth service isn't requested for each address in every
+// selecting round. When refreshing an expired entry fails, the entry is kept using under `keep` policy, so an
+// outage of the auth service doesn't stop selecting messages. The signers are keyed by key address, and the
+// concurrent misses of a signer share one request.
+type signerCache struct {
+	cfg *config.SignerCacheConfig
+
+	lk      sync.Mutex
+	entries map[address.Address]*signerEntry
+	// keys is the key addresses of ID addresses, which never change, so they are kept even the cache is disabled
+	keys  map[address.Address]address.Address
+	group singleflight.Group
+}
+
+func newSignerCache(cfg *config.SignerCacheConfig) *signerCache {
+	return &signerCache{
+		cfg:     cfg,
+		entries: make(map[address.Address]*signerEntry),
+		keys:    make(map[address.Address]address.Address),
+	}
+}
+
+// key returns the key address of the ID address id, resolve is called only when it isn't resolved before
+func (sc *signerCache) key(id address.Address, resolve func() (address.Address, error)) (address.Address, error) {
+	sc.lk.Lock()
+	key, ok := sc.keys[id]
+	sc.lk.Unlock()
+	if ok {
+		return key, nil
+	}
+
+	key, err := resolve()
+	if err != nil {
+		return address.Undef, err
+	}
+	sc.lk.Lock()
+	sc.keys[id] = key
+	sc.lk.Unlock()
+	return key, nil
+}
+
+func (sc *signerCache) enabled() bool {
+	return sc.cfg != nil && sc.cfg.TTL > 0
+}
+
+// get returns the cached users of addr, the users are fetched again when expired
+func (sc *signerCache) get(ctx context.Context, addr address.Address, fetch func() ([]string, error)) ([]string, error) {
+	entry, ok := sc.entry(addr)
+	if ok && time.Since(entry.fetchedAt) < sc.cfg.TTL {
+		return entry.accounts, nil
+	}
+
+	res, err, _ := sc.group.Do(addr.String(), func() (interface{}, error) {
+		return sc.fetch(ctx, addr, fetch)
+	})
+	if err != nil {
+		if ok && sc.keepStale(entry) {
+			log.Warnf("get users of %s failed %v, use the users fetched at %s", addr, err,
+				entry.fetchedAt.Format("2006-01-02 15:04:05"))
+			ctx, _ = tag.New(ctx, tag.Upsert(metrics.WalletAddress, addr.String()))
+			stats.Record(ctx, metrics.SignerCacheStale.M(1))
+			return entry.accounts, nil
+		}
+		return nil, err
+	}
+	return res.([]string), nil
+}
+
+// fetch requests the users of addr and caches them
+func (sc *signerCache) fetch(ctx context.Context, addr address.Address, fetch func() ([]string, error)) ([]string, error) {
+	start := time.Now()
+	accounts, err := fetch()
+	stats.Record(ctx, metrics.AuthRequestLatency.M(float64(time.Since(start).Milliseconds())))
+	if err != nil {
+		stats.Record(ctx, metrics.AuthRequestErrors.M(1))
+		return nil, err
+	}
+
+	if sc.enabled() {
+		sc.lk.Lock()
+		sc.entries[addr] = &signerEntry{accounts: accounts, fetchedAt: time.Now()}
+		sc.lk.Unlock()
+	}
+	return accounts, nil
+}
+
+func (sc *signerCache) entry(addr address.Address) (*signerEntry, bool) {
+	if !sc.enabled() {
+		return nil, false
+	}
+	sc.lk.Lock()
+	defer sc.lk.Unlock()
+	entry, ok := sc.entries[addr]
+	return entry, ok
+}
+
+func (sc *signerCache) keepStale(entry *signerEntry) bool {
+	if sc.cfg.StalePolicy != config.StaleKeep {
+		return false
+	}
+	return sc.cfg.MaxStale == 0 || time.Since(entry.fetchedAt) < sc.cfg.TTL+sc.cfg.MaxStale
+}
+
+// invalidate removes the cached users of addrs, all are removed when addrs is empty, the resolved key addresses are
+// kept
+func (sc *signerCache) invalidate(addrs []address.Address) {
+	sc.lk.Lock()
+	defer sc.lk.Unlock()
+	if len(addrs) == 0 {
+		sc.entries = make(map[address.Address]*signerEntry)
+		return
+	}
+	for _, addr := range addrs {
+		delete(sc.entries, addr)
+	}
+}
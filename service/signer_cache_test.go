@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/venus-messager/config"
+	"github.com/filecoin-project/venus-messager/testhelper"
+)
+
+func TestSignerCache(t *testing.T) {
+	ctx := context.Background()
+	addrs := testhelper.RandAddresses(t, 2)[:2]
+	addr := addrs[0]
+	authErr := errors.New("auth is down")
+
+	var calls int
+	var fetchErr error
+	accounts := []string{"acc1"}
+	fetch := func() ([]string, error) {
+		calls++
+		if fetchErr != nil {
+			return nil, fetchErr
+		}
+		return accounts, nil
+	}
+	reset := func() {
+		calls = 0
+		fetchErr = nil
+	}
+
+	t.Run("cache until expired", func(t *testing.T) {
+		reset()
+		sc := newSignerCache(&config.SignerCacheConfig{TTL: 50 * time.Millisecond, StalePolicy: config.StaleKeep})
+		for i := 0; i < 3; i++ {
+			res, err := sc.get(ctx, addr, fetch)
+			require.NoError(t, err)
+			assert.Equal(t, accounts, res)
+		}
+		assert.Equal(t, 1, calls)
+
+		time.Sleep(60 * time.Millisecond)
+		_, err := sc.get(ctx, addr, fetch)
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls)
+
+		// errors are not cached
+		fetchErr = authErr
+		_, err = sc.get(ctx, addrs[1], fetch)
+		assert.ErrorIs(t, err, authErr)
+		_, err = sc.get(ctx, addrs[1], fetch)
+		assert.ErrorIs(t, err, authErr)
+		assert.Equal(t, 4, calls)
+	})
+
+	t.Run("keep stale", func(t *testing.T) {
+		reset()
+		sc := newSignerCache(&config.SignerCacheConfig{TTL: 20 * time.Millisecond, StalePolicy: config.StaleKeep, MaxStale: 50 * time.Millisecond})
+		_, err := sc.get(ctx, addr, fetch)
+		require.NoError(t, err)
+
+		time.Sleep(30 * time.Millisecond)
+		fetchErr = authErr
+		res, err := sc.get(ctx, addr, fetch)
+		require.NoError(t, err)
+		assert.Equal(t, accounts, res)
+
+		// longer than MaxStale
+		time.Sleep(50 * time.Millisecond)
+		_, err = sc.get(ctx, addr, fetch)
+		assert.ErrorIs(t, err, authErr)
+	})
+
+	t.Run("reject stale", func(t *testing.T) {
+		reset()
+		sc := newSignerCache(&config.SignerCacheConfig{TTL: 20 * time.Millisecond, StalePolicy: config.StaleReject})
+		_, err := sc.get(ctx, addr, fetch)
+		require.NoError(t, err)
+
+		time.Sleep(30 * time.Millisecond)
+		fetchErr = authErr
+		_, err = sc.get(ctx, addr, fetch)
+		assert.ErrorIs(t, err, authErr)
+	})
+
+	t.Run("invalidate", func(t *testing.T) {
+		reset()
+		sc := newSignerCache(&config.SignerCacheConfig{TTL: time.Hour})
+		for _, a := range addrs {
+			_, err := sc.get(ctx, a, fetch)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 2, calls)
+
+		sc.invalidate(addrs[:1])
+		for _, a := range addrs {
+			_, err := sc.get(ctx, a, fetch)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 3, calls)
+
+		sc.invalidate(nil)
+		for _, a := range addrs {
+			_, err := sc.get(ctx, a, fetch)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 5, calls)
+	})
+
+	t.Run("share concurrent misses", func(t *testing.T) {
+		sc := newSignerCache(&config.SignerCacheConfig{TTL: time.Hour})
+		var fetched int32
+		release := make(chan struct{})
+		blockingFetch := func() ([]string, error) {
+			atomic.AddInt32(&fetched, 1)
+			<-release
+			return accounts, nil
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				res, err := sc.get(ctx, addr, blockingFetch)
+				assert.NoError(t, err)
+				assert.Equal(t, accounts, res)
+			}()
+		}
+		assert.Eventually(t, func() bool { return atomic.LoadInt32(&fetched) == 1 }, time.Second, 10*time.Millisecond)
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+		wg.Wait()
+		assert.Equal(t, int32(1), atomic.LoadInt32(&fetched))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		reset()
+		sc := newSignerCache(&config.SignerCacheConfig{StalePolicy: config.StaleKeep})
+		for i := 0; i < 3; i++ {
+			_, err := sc.get(ctx, addr, fetch)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 3, calls)
+	})
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+
+	venusTypes "github.com/filecoin-project/venus/venus-shared/types"
+
+	"github.com/filecoin-project/venus-messager/config"
+	"github.com/filecoin-project/venus-messager/metrics"
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+)
+
+var errNodeNotSynced = errors.New("node not synced")
+
+type nodeSyncState struct {
+	lk     sync.Mutex
+	status mtypes.NodeSyncStatus
+}
+
+// checkNodeSync refuses to select messages when the node is out of sync, the gas estimated and the nonce assigned
+// against stale state are likely wrong. Selecting resumes once the node catches up.
+func (ms *MessageService) checkNodeSync(ctx context.Context, head *venusTypes.TipSet) error {
+	err := ms.nodeSyncErr(ctx, &ms.fsRepo.Config().MessageService, head)
+
+	ms.nodeSync.lk.Lock()
+	prevSynced := ms.nodeSync.status.Synced
+	ms.nodeSync.status = mtypes.NodeSyncStatus{
+		Synced:     err == nil,
+		HeadHeight: int64(head.Height()),
+		HeadTime:   time.Unix(int64(head.MinTimestamp()), 0),
+		CheckedAt:  time.Now(),
+	}
+	if err != nil {
+		ms.nodeSync.status.Reason = err.Error()
+	}
+	ms.nodeSync.lk.Unlock()
+
+	if err != nil && prevSynced {
+		log.Warnf("pause selecting messages: %v", err)
+	} else if err == nil && !prevSynced {
+		log.Infof("node synced at %d, resume selecting messages", head.Height())
+	}
+
+	var v int64
+	if err == nil {
+		v = 1
+	}
+	stats.Record(ctx, metrics.NodeSynced.M(v))
+
+	return err
+}
+
+func (ms *MessageService) nodeSyncErr(ctx context.Context, cfg *config.MessageServiceConfig, head *venusTypes.TipSet) error {
+	if cfg.MaxHeadDelayEpoch <= 0 {
+		return nil
+	}
+
+	maxDelay := ms.blockDelay * time.Duration(cfg.MaxHeadDelayEpoch)
+	if delay := time.Since(time.Unix(int64(head.MinTimestamp()), 0)); delay > maxDelay {
+		return fmt.Errorf("%w: head %d is %v old, exceed %v", errNodeNotSynced, head.Height(), delay.Truncate(time.Second), maxDelay)
+	}
+
+	if !cfg.CheckSyncState {
+		return nil
+	}
+	state, err := ms.nodeClient.SyncState(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: get sync state failed: %v", errNodeNotSynced, err)
+	}
+	for _, activeSync := range state.ActiveSyncs {
+		if activeSync.Target == nil || activeSync.Stage == venusTypes.StageSyncComplete ||
+			activeSync.Stage == venusTypes.StageIdle || activeSync.Stage == venusTypes.StageSyncErrored {
+			continue
+		}
+		if int64(activeSync.Target.Height()-activeSync.Height) > cfg.MaxHeadDelayEpoch {
+			return fmt.Errorf("%w: syncing %d to %d, stage %s", errNodeNotSynced, activeSync.Height,
+				activeSync.Target.Height(), activeSync.Stage)
+		}
+	}
+
+	return nil
+}
+
+func (ms *MessageService) NodeSyncStatus(ctx context.Context) (*mtypes.NodeSyncStatus, error) {
+	ms.nodeSync.lk.Lock()
+	defer ms.nodeSync.lk.Unlock()
+	status := ms.nodeSync.status
+	return &status, nil
+}
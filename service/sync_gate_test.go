@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	v1 "github.com/filecoin-project/venus/venus-shared/api/chain/v1"
+	venustypes "github.com/filecoin-project/venus/venus-shared/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/venus-messager/filestore"
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/testhelper"
+)
+
+type syncStateNode struct {
+	v1.FullNode
+	state *venustypes.SyncState
+}
+
+func (s *syncStateNode) SyncState(ctx context.Context) (*venustypes.SyncState, error) {
+	return s.state, nil
+}
+
+func genTipsetAt(t *testing.T, height abi.ChainEpoch, tm time.Time) *venustypes.TipSet {
+	blk, err := testhelper.GenBlockHead(address.TestAddress, height, nil)
+	require.NoError(t, err)
+	blk.Timestamp = uint64(tm.Unix())
+	ts, err := venustypes.NewTipSet([]*venustypes.BlockHeader{blk})
+	require.NoError(t, err)
+	return ts
+}
+
+func TestCheckNodeSync(t *testing.T) {
+	ctx := context.Background()
+
+	fsRepo := filestore.NewMockFileStore(t.TempDir())
+	cfg := &fsRepo.Config().MessageService
+	cfg.MaxHeadDelayEpoch = 5
+	node := &syncStateNode{state: &venustypes.SyncState{}}
+	ms := &MessageService{
+		fsRepo:     fsRepo,
+		nodeClient: node,
+		blockDelay: 30 * time.Second,
+		nodeSync:   nodeSyncState{status: mtypes.NodeSyncStatus{Synced: true}},
+	}
+
+	// fresh head
+	assert.NoError(t, ms.checkNodeSync(ctx, genTipsetAt(t, 100, time.Now().Add(-time.Minute))))
+
+	// head is too old, pause
+	err := ms.checkNodeSync(ctx, genTipsetAt(t, 101, time.Now().Add(-time.Hour)))
+	assert.True(t, errors.Is(err, errNodeNotSynced))
+	status, err := ms.NodeSyncStatus(ctx)
+	assert.NoError(t, err)
+	assert.False(t, status.Synced)
+	assert.Equal(t, int64(101), status.HeadHeight)
+	assert.Contains(t, status.Reason, errNodeNotSynced.Error())
+
+	// caught up, resume
+	assert.NoError(t, ms.checkNodeSync(ctx, genTipsetAt(t, 200, time.Now())))
+	status, err = ms.NodeSyncStatus(ctx)
+	assert.NoError(t, err)
+	assert.True(t, status.Synced)
+	assert.Empty(t, status.Reason)
+
+	// sync state is checked only when enabled
+	target := genTipsetAt(t, 300, time.Now())
+	node.state.ActiveSyncs = []venustypes.ActiveSync{{Target: target, Stage: venustypes.StageMessages, Height: 200}}
+	assert.NoError(t, ms.checkNodeSync(ctx, genTipsetAt(t, 200, time.Now())))
+
+	cfg.CheckSyncState = true
+	err = ms.checkNodeSync(ctx, genTipsetAt(t, 200, time.Now()))
+	assert.True(t, errors.Is(err, errNodeNotSynced))
+
+	node.state.ActiveSyncs[0].Height = 298
+	assert.NoError(t, ms.checkNodeSync(ctx, genTipsetAt(t, 298, time.Now())))
+
+	// disabled
+	cfg.MaxHeadDelayEpoch = 0
+	assert.NoError(t, ms.checkNodeSync(ctx, genTipsetAt(t, 101, time.Now().Add(-time.Hour))))
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/venus-auth/jwtclient"
+
+	"github.com/filecoin-project/venus/venus-shared/actors/builtin"
+	venusTypes "github.com/filecoin-project/venus/venus-shared/types"
+	types "github.com/filecoin-project/venus/venus-shared/types/messager"
+
+	"github.com/filecoin-project/venus-messager/config"
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/models/repo"
+)
+
+var errExceedDailyCap = errors.New("exceed daily cap")
+
+// topUpRequester is the account of top up messages, it is recorded as the requester when they need approval
+const topUpRequester = "messager-top-up"
+
+type topUpRule struct {
+	addr          address.Address
+	treasury      address.Address
+	minBalance    big.Int
+	targetBalance big.Int
+}
+
+func parseTopUpRules(cfg *config.TopUpConfig) ([]*topUpRule, big.Int, error) {
+	dailyCap := big.Zero()
+	if len(cfg.DailyCap) != 0 {
+		v, err := venusTypes.ParseFIL(cfg.DailyCap)
+		if err != nil {
+			return nil, dailyCap, fmt.Errorf("parse daily cap %s failed: %v", cfg.DailyCap, err)
+		}
+		dailyCap = big.Int(v)
+	}
+
+	rules := make([]*topUpRule, 0, len(cfg.Rules))
+	for _, r := range cfg.Rules {
+		addr, err := address.NewFromString(r.Address)
+		if err != nil {
+			return nil, dailyCap, fmt.Errorf("parse top up address %s failed: %v", r.Address, err)
+		}
+		treasury, err := address.NewFromString(r.Treasury)
+		if err != nil {
+			return nil, dailyCap, fmt.Errorf("parse treasury address %s failed: %v", r.Treasury, err)
+		}
+		minBalance, err := venusTypes.ParseFIL(r.MinBalance)
+		if err != nil {
+			return nil, dailyCap, fmt.Errorf("parse min balance of %s failed: %v", r.Address, err)
+		}
+		targetBalance, err := venusTypes.ParseFIL(r.TargetBalance)
+		if err != nil {
+			return nil, dailyCap, fmt.Errorf("parse target balance of %s failed: %v", r.Address, err)
+		}
+		if big.Cmp(big.Int(targetBalance), big.Int(minBalance)) <= 0 {
+			return nil, dailyCap, fmt.Errorf("target balance %s of %s must bigger than min balance %s", targetBalance, r.Address, minBalance)
+		}
+
+		rules = append(rules, &topUpRule{
+			addr:          addr,
+			treasury:      treasury,
+			minBalance:    big.Int(minBalance),
+			targetBalance: big.Int(targetBalance),
+		})
+	}
+
+	return rules, dailyCap, nil
+}
+
+func (ms *MessageService) topUpProc(ctx context.Context, cfg *config.TopUpConfig, rules []*topUpRule, dailyCap big.Int) {
+	tm := time.NewTicker(cfg.CheckInterval)
+	defer tm.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Warnf("stop top up: %v", ctx.Err())
+			return
+		case <-tm.C:
+			if !ms.elector.IsLeader() {
+				continue
+			}
+			for _, rule := range rules {
+				// the message is sent from treasury, so top up is done by the owner of it
+				if !ms.sharding.Owns(rule.treasury) {
+					continue
+				}
+				if err := ms.tryTopUp(ctx, cfg, rule, dailyCap); err != nil {
+					log.Errorf("top up %s from %s failed: %v", rule.addr, rule.treasury, err)
+				}
+			}
+		}
+	}
+}
+
+func (ms *MessageService) tryTopUp(ctx context.Context, cfg *config.TopUpConfig, rule *topUpRule, dailyCap big.Int) error {
+	actor, err := ms.nodeClient.StateGetActor(ctx, rule.addr, venusTypes.EmptyTSK)
+	if err != nil {
+		return fmt.Errorf("get actor failed: %w", err)
+	}
+	if actor.Balance.GreaterThanEqual(rule.minBalance) {
+		return nil
+	}
+
+	latest, err := ms.repo.TopUpRepo().GetLatestTopUp(rule.addr)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if latest != nil {
+		if time.Since(latest.CreatedAt) < cfg.MinInterval {
+			log.Debugf("skip top up %s, last top up at %v", rule.addr, latest.CreatedAt)
+			return nil
+		}
+		// wait the last top up on chain, avoid topping up twice
+		state, err := ms.repo.MessageRepo().GetMessageState(latest.MsgID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if state == types.UnFillMsg || state == types.FillMsg {
+			log.Infof("skip top up %s, last top up message %s is %s", rule.addr, latest.MsgID, state)
+			return nil
+		}
+	}
+
+	value := big.Sub(rule.targetBalance, actor.Balance)
+	if !dailyCap.IsZero() {
+		records, err := ms.repo.TopUpRepo().ListTopUpByTreasury(rule.treasury, time.Now().Add(-24*time.Hour))
+		if err != nil {
+			return err
+		}
+		paid := big.Zero()
+		for _, r := range records {
+			paid = big.Add(paid, r.Value)
+		}
+		if big.Add(paid, value).GreaterThan(dailyCap) {
+			return fmt.Errorf("%w: paid %s, want %s, cap %s", errExceedDailyCap, venusTypes.FIL(paid),
+				venusTypes.FIL(value), venusTypes.FIL(dailyCap))
+		}
+	}
+
+	msg := &types.Message{
+		ID: venusTypes.NewUUID().String(),
+		Message: venusTypes.Message{
+			From:       rule.treasury,
+			To:         rule.addr,
+			Value:      value,
+			Method:     builtin.MethodSend,
+			GasFeeCap:  abi.NewTokenAmount(0),
+			GasPremium: abi.NewTokenAmount(0),
+		},
+		State: types.UnFillMsg,
+	}
+	record := &mtypes.TopUpRecord{
+		ID:        venusTypes.NewUUID().String(),
+		Addr:      rule.addr,
+		Treasury:  rule.treasury,
+		MsgID:     msg.ID,
+		Value:     value,
+		Balance:   actor.Balance,
+		CreatedAt: time.Now(),
+	}
+	// save the record with the message, a top up can't be sent without the record, otherwise it is sent again
+	if err := ms.pushMessageWith(jwtclient.CtxWithName(ctx, topUpRequester), msg, func(txRepo repo.TxRepo) error {
+		return txRepo.TopUpRepo().CreateTopUp(record)
+	}); err != nil {
+		return err
+	}
+	log.Infof("top up %s from %s, balance %s, value %s, message %s", rule.addr, rule.treasury,
+		venusTypes.FIL(actor.Balance), venusTypes.FIL(value), msg.ID)
+
+	return nil
+}
+
+func (ms *MessageService) ListTopUpRecord(ctx context.Context, addr address.Address) ([]*mtypes.TopUpRecord, error) {
+	return ms.repo.TopUpRepo().ListTopUp(addr)
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/go-state-types/big"
+	venusTypes "github.com/filecoin-project/venus/venus-shared/types"
+	types "github.com/filecoin-project/venus/venus-shared/types/messager"
+
+	"github.com/filecoin-project/venus-messager/config"
+	"github.com/filecoin-project/venus-messager/models/mtypes"
+	"github.com/filecoin-project/venus-messager/testhelper"
+)
+
+func TestParseTopUpRules(t *testing.T) {
+	addrs := testhelper.RandAddresses(t, 2)
+
+	cfg := config.DefaultConfig().TopUp
+	cfg.DailyCap = "100"
+	cfg.Rules = []config.TopUpRule{
+		{Address: addrs[0].String(), Treasury: addrs[1].String(), MinBalance: "1", TargetBalance: "10"},
+	}
+	rules, dailyCap, err := parseTopUpRules(cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, big.Int(venusTypes.MustParseFIL("100")), dailyCap)
+	assert.Len(t, rules, 1)
+	assert.Equal(t, addrs[0], rules[0].addr)
+	assert.Equal(t, addrs[1], rules[0].treasury)
+	assert.Equal(t, big.Int(venusTypes.MustParseFIL("1")), rules[0].minBalance)
+	assert.Equal(t, big.Int(venusTypes.MustParseFIL("10")), rules[0].targetBalance)
+
+	cfg.Rules[0].TargetBalance = "1"
+	_, _, err = parseTopUpRules(cfg)
+	assert.Error(t, err)
+
+	cfg.Rules[0].TargetBalance = "10"
+	cfg.Rules[0].Treasury = "bad address"
+	_, _, err = parseTopUpRules(cfg)
+	assert.Error(t, err)
+}
+
+func TestTryTopUp(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	newRule := func(addr, treasury string) *topUpRule {
+		cfg := config.DefaultConfig().TopUp
+		cfg.Rules = []config.TopUpRule{{Address: addr, Treasury: treasury, MinBalance: "1", TargetBalance: "10"}}
+		rules, _, err := parseTopUpRules(cfg)
+		require.NoError(t, err)
+		return rules[0]
+	}
+	setup := func(t *testing.T) (*messageServiceHelper, *topUpRule, *topUpRule) {
+		msh := newMessageServiceHelper(ctx, t)
+		addrs := msh.genAddresses()
+		return msh, newRule(addrs[1].String(), addrs[0].String()), newRule(addrs[2].String(), addrs[0].String())
+	}
+	records := func(t *testing.T, msh *messageServiceHelper, rule *topUpRule) []*mtypes.TopUpRecord {
+		list, err := msh.MessageService.ListTopUpRecord(ctx, rule.addr)
+		require.NoError(t, err)
+		return list
+	}
+
+	t.Run("min interval", func(t *testing.T) {
+		msh, rule, _ := setup(t)
+		ms := msh.MessageService
+		cfg := &config.TopUpConfig{MinInterval: time.Hour}
+
+		require.NoError(t, ms.tryTopUp(ctx, cfg, rule, big.Zero()))
+		list := records(t, msh, rule)
+		require.Len(t, list, 1)
+		msg, err := ms.GetMessageByUid(ctx, list[0].MsgID)
+		require.NoError(t, err)
+		assert.Equal(t, rule.treasury, msg.From)
+		assert.Equal(t, rule.addr, msg.To)
+		assert.Equal(t, list[0].Value, msg.Value)
+
+		// top up message is on chain, but the interval is not reached
+		require.NoError(t, ms.repo.MessageRepo().UpdateMessageStateByID(msg.ID, types.OnChainMsg))
+		require.NoError(t, ms.tryTopUp(ctx, cfg, rule, big.Zero()))
+		assert.Len(t, records(t, msh, rule), 1)
+	})
+
+	t.Run("skip in-flight top up", func(t *testing.T) {
+		msh, rule, _ := setup(t)
+		ms := msh.MessageService
+		cfg := &config.TopUpConfig{}
+
+		require.NoError(t, ms.tryTopUp(ctx, cfg, rule, big.Zero()))
+		list := records(t, msh, rule)
+		require.Len(t, list, 1)
+
+		// the last top up message is not on chain
+		require.NoError(t, ms.tryTopUp(ctx, cfg, rule, big.Zero()))
+		assert.Len(t, records(t, msh, rule), 1)
+
+		require.NoError(t, ms.repo.MessageRepo().UpdateMessageStateByID(list[0].MsgID, types.OnChainMsg))
+		require.NoError(t, ms.tryTopUp(ctx, cfg, rule, big.Zero()))
+		assert.Len(t, records(t, msh, rule), 2)
+	})
+
+	t.Run("daily cap", func(t *testing.T) {
+		msh, rule, rule2 := setup(t)
+		ms := msh.MessageService
+		cfg := &config.TopUpConfig{}
+		dailyCap := big.Int(venusTypes.MustParseFIL("15"))
+
+		require.NoError(t, ms.tryTopUp(ctx, cfg, rule, dailyCap))
+		// rule2 uses the same treasury, the sum exceeds the daily cap
+		assert.ErrorIs(t, ms.tryTopUp(ctx, cfg, rule2, dailyCap), errExceedDailyCap)
+		assert.Len(t, records(t, msh, rule2), 0)
+
+		require.NoError(t, ms.tryTopUp(ctx, cfg, rule2, big.Int(venusTypes.MustParseFIL("20"))))
+		assert.Len(t, records(t, msh, rule2), 1)
+	})
+
+	t.Run("requester of approval", func(t *testing.T) {
+		msh, rule, _ := setup(t)
+		ms := msh.MessageService
+		as, err := NewApprovalService(ms.repo, msh.fullNode, &config.ApprovalConfig{Enable: true, ValueThreshold: "1"})
+		require.NoError(t, err)
+		ms.approvalService = as
+
+		require.NoError(t, ms.tryTopUp(ctx, &config.TopUpConfig{}, rule, big.Zero()))
+		list := records(t, msh, rule)
+		require.Len(t, list, 1)
+		approval, err := ms.repo.ApprovalRepo().GetApproval(list[0].MsgID)
+		require.NoError(t, err)
+		assert.Equal(t, topUpRequester, approval.Requester)
+	})
+}
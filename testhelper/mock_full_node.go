@@ -360,6 +360,11 @@ func (f *MockFullNode) StateAccountKey(ctx context.Context, addr address.Address
 	return ResolveIDAddr(addr)
 }
 
+// StateLookupID is the reverse of StateAccountKey, only the addresses resolved from ID addresses can be found
+func (f *MockFullNode) StateLookupID(ctx context.Context, addr address.Address, tsk types.TipSetKey) (address.Address, error) {
+	return LookupIDAddr(addr)
+}
+
 func (f *MockFullNode) StateNetworkName(ctx context.Context) (types.NetworkName, error) {
 	return types.NetworkNameMain, nil
 }
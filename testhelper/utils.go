@@ -27,6 +27,28 @@ func ResolveIDAddr(addr address.Address) (address.Address, error) {
 	return address.NewFromBytes(append([]byte{address.BLS}, data...))
 }
 
+// LookupIDAddr returns the ID address which is resolved to addr by ResolveIDAddr
+func LookupIDAddr(addr address.Address) (address.Address, error) {
+	if addr.Protocol() == address.ID {
+		return addr, nil
+	}
+	if addr.Protocol() == address.BLS {
+		payload := addr.Payload()
+		i := 0
+		for i < len(payload) && payload[i] >= '0' && payload[i] <= '9' {
+			i++
+		}
+		if i > 0 {
+			if id, err := address.NewFromString("f0" + string(payload[:i])); err == nil {
+				if resolved, err := ResolveIDAddr(id); err == nil && resolved == addr {
+					return id, nil
+				}
+			}
+		}
+	}
+	return address.Undef, fmt.Errorf("actor %s not found", addr)
+}
+
 func ResolveAddr(t *testing.T, addr address.Address) address.Address {
 	if addr.Protocol() != address.ID {
 		return addr
@@ -114,7 +114,11 @@ func WriteConfig(path string, cfg interface{}) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(path, cfgBytes, 0o666)
+	// the config contains tokens, so it is only accessible by the owner
+	if err := os.WriteFile(path, cfgBytes, 0o600); err != nil {
+		return err
+	}
+	return os.Chmod(path, 0o600)
 }
 
 func MsgsGroupByAddress(msgs []*types.SignedMessage) map[address.Address][]*types.SignedMessage {